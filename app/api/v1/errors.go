@@ -20,7 +20,35 @@ var (
 	ErrBanRole               = newError(40000, "账号因违规行为已被封禁")
 
 	// questionBank
-	ErrTitleAlreadyUse = newError(40000, "题库或题目已存在")
+	ErrTitleAlreadyUse       = newError(40000, "题库或题目已存在")
+	ErrQuestionAlreadyInBank = newError(40000, "题目已在题库中")
+	ErrQuestionNearDuplicate = newError(40000, "存在疑似重复的题目")
+	ErrAIGenerateQuestion    = newError(50000, "AI 生成题目失败，请稍后再试")
+
+	// review
+	ErrReviewStatus          = newError(40000, "审核状态错误")
+	ErrReviewMessageRequired = newError(40000, "拒绝时必须填写审核原因")
+
+	// synonym
+	ErrIllegalSynonymRule = newError(40000, "同义词规则不规范")
+
+	// mockInterview
+	ErrMockInterviewChat        = newError(50000, "AI 回复失败，请稍后再试")
+	ErrMockInterviewNotFinished = newError(40000, "面试尚未结束")
+	ErrMockInterviewBankEmpty   = newError(40000, "题库中没有可用的题目")
+
+	// mockInterview state
+	ErrMockInterviewAlreadyStarted   = newError(40010, "面试已经开始")
+	ErrMockInterviewNotInProgress    = newError(40011, "面试未在进行中")
+	ErrMockInterviewNotPaused        = newError(40012, "面试未暂停")
+	ErrMockInterviewClosed           = newError(40013, "面试已结束或已放弃")
+	ErrMockInterviewStateChanged     = newError(40014, "面试状态已变化，请刷新后重试")
+	ErrMockInterviewShareInvalid     = newError(40015, "分享链接无效或已过期")
+	ErrMockInterviewReportGenerating = newError(40016, "评估报告生成中，请稍后再试")
+
+	// promptTemplate
+	ErrPromptTemplateInvalid = newError(40000, "Prompt 模板不规范")
+	ErrPromptPersonaNotFound = newError(40000, "面试官风格不存在")
 
 	ErrSystemIsBusy = newError(40000, "系统繁忙,请稍后再试")
 
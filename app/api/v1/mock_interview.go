@@ -6,11 +6,17 @@ import (
 
 // MockInterviewEventRequest 模拟面试事件请求
 type MockInterviewEventRequest struct {
-	Event   string `json:"event,omitempty"`   // 事件类型
+	Event   string `json:"event,omitempty"`   // 事件类型（start、chat、pause、resume、end、abandon）
 	ID      uint64 `json:"id,omitempty"`      // 事件关联的ID
 	Message string `json:"message,omitempty"` // 事件消息内容
 }
 
+// MockInterviewStreamResult 流式模拟面试结束时推送的结果
+type MockInterviewStreamResult struct {
+	Content string `json:"content"` // 完整回复内容
+	Status  int    `json:"status"`  // 本轮结束后的面试状态（0-待开始、1-进行中、2-已结束、3-已暂停、4-已放弃）
+}
+
 // MockInterview 模拟面试信息
 type MockInterview struct {
 	CreateTime     time.Time `json:"createTime,omitempty"`     // 创建时间
@@ -18,18 +24,25 @@ type MockInterview struct {
 	ID             uint64    `json:"id,omitempty"`             // 面试 ID
 	IsDelete       int8      `json:"isDelete,omitempty"`       // 是否删除
 	JobPosition    string    `json:"jobPosition,omitempty"`    // 工作岗位
-	Messages       string    `json:"messages,omitempty"`       // 消息列表（JSON 对象数组字段，同时包括了总结）
-	Status         int       `json:"status,omitempty"`         // 状态（0-待开始、1-进行中、2-已结束）
+	Messages       string    `json:"messages,omitempty"`       // 消息列表（JSON 对象数组字段，不含系统 Prompt，仅查询单个面试时返回）
+	Status         int       `json:"status,omitempty"`         // 状态（0-待开始、1-进行中、2-已结束、3-已暂停、4-已放弃）
 	UpdateTime     time.Time `json:"updateTime,omitempty"`     // 更新时间
 	UserID         uint64    `json:"userId,omitempty"`         // 创建人（用户 ID）
 	WorkExperience string    `json:"workExperience,omitempty"` // 工作年限
+	QuestionBankID *string   `json:"questionBankId,omitempty"` // 出题题库 ID，为空表示由 AI 自由出题
+	QuestionNum    int       `json:"questionNum,omitempty"`    // 从题库抽取的题目数量
+	Persona        string    `json:"persona,omitempty"`        // 面试官风格
+	PromptVersion  int       `json:"promptVersion,omitempty"`  // 使用的 Prompt 模板版本号，0 表示内置模板
 }
 
 // MockInterviewAddRequest 模拟面试添加请求
 type MockInterviewAddRequest struct {
-	Difficulty     string `json:"difficulty,omitempty"`     // 面试难度
-	JobPosition    string `json:"jobPosition,omitempty"`    // 工作岗位
-	WorkExperience string `json:"workExperience,omitempty"` // 工作年限
+	Difficulty     string  `json:"difficulty,omitempty"`     // 面试难度
+	JobPosition    string  `json:"jobPosition,omitempty"`    // 工作岗位
+	WorkExperience string  `json:"workExperience,omitempty"` // 工作年限
+	QuestionBankID *string `json:"questionBankId,omitempty"` // 出题题库 ID，指定后从该题库抽题提问
+	QuestionNum    int     `json:"questionNum,omitempty"`    // 抽题数量，默认 5，最多 20
+	Persona        string  `json:"persona,omitempty"`        // 面试官风格，默认 strict，可选值见 /mockInterview/persona/list
 }
 
 // MockInterviewGetRequest 获取模拟面试信息
@@ -46,7 +59,7 @@ type MockInterviewQueryRequest struct {
 	PageSize       int    `json:"pageSize,omitempty"`       // 每页大小
 	SortField      string `json:"sortField,omitempty"`      // 排序字段
 	SortOrder      string `json:"sortOrder,omitempty"`      // 排序顺序
-	Status         int    `json:"status,omitempty"`         // 状态（0-待开始、1-进行中、2-已结束）
+	Status         int    `json:"status,omitempty"`         // 状态（0-待开始、1-进行中、2-已结束、3-已暂停、4-已放弃）
 	UserID         uint64 `json:"userId,omitempty"`         // 创建人（用户 ID）
 	WorkExperience string `json:"workExperience,omitempty"` // 工作年限
 }
@@ -64,3 +77,101 @@ type PageMockInterview struct {
 	Size             int             `json:"size,omitempty"`             // 每页大小
 	Total            int             `json:"total,omitempty"`            // 总记录数
 }
+
+// MockInterviewReportRequest 获取模拟面试评估报告请求
+type MockInterviewReportRequest struct {
+	ID uint64 `form:"id"` // 面试 ID
+}
+
+// MockInterviewDimension 评估维度得分
+type MockInterviewDimension struct {
+	Score   int    `json:"score"`   // 得分（0-100）
+	Comment string `json:"comment"` // 评语
+}
+
+// MockInterviewDimensions 各评估维度
+type MockInterviewDimensions struct {
+	TechnicalDepth MockInterviewDimension `json:"technicalDepth"` // 技术深度
+	Communication  MockInterviewDimension `json:"communication"`  // 沟通表达
+	ProblemSolving MockInterviewDimension `json:"problemSolving"` // 问题解决
+}
+
+// MockInterviewQuestionAssessment 逐题评估
+type MockInterviewQuestionAssessment struct {
+	Question      string `json:"question"`      // 面试官提出的问题
+	AnswerSummary string `json:"answerSummary"` // 候选人回答概要
+	Score         int    `json:"score"`         // 得分（0-100）
+	Comment       string `json:"comment"`       // 评语
+}
+
+// MockInterviewReportDetail 模型生成的评估内容，需符合评估报告 JSON Schema
+type MockInterviewReportDetail struct {
+	OverallScore        int                               `json:"overallScore"`        // 总分（0-100）
+	Dimensions          MockInterviewDimensions           `json:"dimensions"`          // 各维度得分
+	QuestionAssessments []MockInterviewQuestionAssessment `json:"questionAssessments"` // 逐题评估
+	Strengths           []string                          `json:"strengths"`           // 优势
+	Weaknesses          []string                          `json:"weaknesses"`          // 不足
+	RecommendedTopics   []string                          `json:"recommendedTopics"`   // 建议加强的知识点
+	Summary             string                            `json:"summary"`             // 总结
+}
+
+// MockInterviewReport 模拟面试评估报告
+type MockInterviewReport struct {
+	MockInterviewReportDetail
+	MockInterviewID      uint64              `json:"mockInterviewId"`      // 面试 ID
+	RecommendedQuestions []SimilarQuestionVO `json:"recommendedQuestions"` // 从题库中推荐练习的题目
+	CreateTime           time.Time           `json:"createTime"`           // 生成时间
+}
+
+// MockInterviewMessageQueryRequest 分页查询模拟面试对话记录
+type MockInterviewMessageQueryRequest struct {
+	MockInterviewID uint64 `json:"mockInterviewId,omitempty"` // 面试 ID
+	Current         *int   `json:"current,omitempty"`         // 当前页码
+	PageSize        *int   `json:"pageSize,omitempty"`        // 每页大小
+}
+
+// MockInterviewMessageVO 模拟面试对话消息
+type MockInterviewMessageVO struct {
+	Seq              int       `json:"seq"`                        // 顺序号
+	Role             string    `json:"role"`                       // 角色（user/assistant）
+	Content          string    `json:"content"`                    // 消息内容
+	QuestionID       *string   `json:"questionId,omitempty"`       // 题库模式下对应的题目 ID
+	Tokens           int       `json:"tokens,omitempty"`           // 消息 token 数
+	PromptTokens     int       `json:"promptTokens,omitempty"`     // 生成该回复时的输入 token 数
+	CompletionTokens int       `json:"completionTokens,omitempty"` // 生成该回复时的输出 token 数
+	LatencyMs        int64     `json:"latencyMs,omitempty"`        // 生成该回复的耗时（毫秒）
+	ModelName        string    `json:"modelName,omitempty"`        // 生成该回复的模型
+	CreateTime       time.Time `json:"createTime"`                 // 创建时间
+}
+
+// MockInterviewExportRequest 导出模拟面试记录
+type MockInterviewExportRequest struct {
+	ID     uint64 `form:"id,omitempty"`     // 面试 ID
+	Format string `form:"format,omitempty"` // 导出格式（markdown、html），默认 markdown
+}
+
+// MockInterviewExportFile 导出的文件
+type MockInterviewExportFile struct {
+	FileName    string // 文件名
+	ContentType string // 内容类型
+	Content     []byte // 文件内容
+}
+
+// MockInterviewShareRequest 创建模拟面试分享链接
+type MockInterviewShareRequest struct {
+	ID          uint64 `json:"id,omitempty"`          // 面试 ID
+	ExpireHours int    `json:"expireHours,omitempty"` // 有效期（小时），默认 72，最多 720
+}
+
+// MockInterviewShareVO 模拟面试分享链接
+type MockInterviewShareVO struct {
+	Token      string    `json:"token"`      // 签名 token
+	Path       string    `json:"path"`       // 无需登录即可查看的访问路径
+	ExpireTime time.Time `json:"expireTime"` // 过期时间
+}
+
+// MockInterviewShareViewRequest 通过分享链接查看模拟面试记录
+type MockInterviewShareViewRequest struct {
+	Token  string `form:"token,omitempty"`  // 签名 token
+	Format string `form:"format,omitempty"` // 查看格式（markdown、html），默认 html
+}
@@ -0,0 +1,53 @@
+package v1
+
+import "time"
+
+// SavePromptTemplateRequest 保存 Prompt 模板，每次保存生成该场景、风格的一个新版本
+type SavePromptTemplateRequest struct {
+	Scene       *string `json:"scene,omitempty"`       // 场景（mock_interview、generate_question）
+	Persona     *string `json:"persona,omitempty"`     // 风格，小写字母、数字或下划线
+	Name        *string `json:"name,omitempty"`        // 风格名称
+	Description *string `json:"description,omitempty"` // 风格描述
+	Content     *string `json:"content,omitempty"`     // 模板内容（Go text/template 语法）
+}
+
+// GetPromptTemplateRequest 获取 Prompt 模板版本
+type GetPromptTemplateRequest struct {
+	ID *string `form:"id" json:"id,omitempty"` // 模板版本 ID
+}
+
+// DeletePromptTemplateRequest 停用场景、风格下的全部版本
+type DeletePromptTemplateRequest struct {
+	Scene   *string `json:"scene,omitempty"`   // 场景
+	Persona *string `json:"persona,omitempty"` // 风格
+}
+
+// PromptTemplateQueryRequest 分页查询 Prompt 模板版本
+type PromptTemplateQueryRequest struct {
+	Scene    *string `json:"scene,omitempty"`    // 场景
+	Persona  *string `json:"persona,omitempty"`  // 风格
+	Current  *int    `json:"current,omitempty"`  // 当前页码
+	PageSize *int    `json:"pageSize,omitempty"` // 每页大小
+}
+
+// PromptTemplateVO Prompt 模板版本
+type PromptTemplateVO struct {
+	ID          *string    `json:"id,omitempty"`          // 模板版本 ID
+	Scene       *string    `json:"scene,omitempty"`       // 场景
+	Persona     *string    `json:"persona,omitempty"`     // 风格
+	Version     *int       `json:"version,omitempty"`     // 版本号
+	Name        *string    `json:"name,omitempty"`        // 风格名称
+	Description *string    `json:"description,omitempty"` // 风格描述
+	Content     *string    `json:"content,omitempty"`     // 模板内容
+	EditorID    *string    `json:"editorId,omitempty"`    // 修改人 ID
+	CreateTime  *time.Time `json:"createTime,omitempty"`  // 创建时间
+	IsDelete    *int8      `json:"isDelete,omitempty"`    // 是否停用
+}
+
+// MockInterviewPersonaVO 创建模拟面试时可选的面试官风格
+type MockInterviewPersonaVO struct {
+	Persona     string `json:"persona"`               // 风格
+	Name        string `json:"name"`                  // 风格名称
+	Description string `json:"description,omitempty"` // 风格描述
+	Version     int    `json:"version"`               // 当前版本号，0 表示内置模板
+}
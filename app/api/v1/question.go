@@ -15,6 +15,27 @@ type Question struct {
 	Title      *string    `json:"title,omitempty"`      // 问题标题
 	UpdateTime *time.Time `json:"updateTime,omitempty"` // 更新时间
 	UserID     *string    `json:"userId,omitempty"`     // 用户 ID
+
+	ReviewStatus  *int       `json:"reviewStatus,omitempty"`  // 审核状态：0-待审核, 1-通过, 2-拒绝
+	ReviewMessage *string    `json:"reviewMessage,omitempty"` // 审核信息
+	ReviewerID    *string    `json:"reviewerId,omitempty"`    // 审核人 ID
+	ReviewTime    *time.Time `json:"reviewTime,omitempty"`    // 审核时间
+
+	Highlight *QuestionHighlight `json:"highlight,omitempty"` // 搜索命中的高亮片段
+}
+
+// QuestionHighlight 搜索命中的高亮片段，为已转义的 HTML：原文中的 HTML 特殊字符已转义，命中词使用 <em></em> 包裹，可直接作为 HTML 渲染
+type QuestionHighlight struct {
+	Title   []string `json:"title,omitempty"`
+	Content []string `json:"content,omitempty"`
+	Answer  []string `json:"answer,omitempty"`
+}
+
+// TagFacet 标签聚合结果
+type TagFacet struct {
+	Tag      string `json:"tag"`      // 标签
+	Count    int    `json:"count"`    // 符合当前搜索条件且带有该标签的题目数
+	Selected bool   `json:"selected"` // 是否已作为过滤条件
 }
 type QuestionRequest struct {
 	Answer         *string  `json:"answer,omitempty"`         // 回答内容
@@ -24,6 +45,7 @@ type QuestionRequest struct {
 	NotID          *int     `json:"notId,omitempty"`          // 排除的 ID
 	PageSize       *int     `json:"pageSize,omitempty"`       // 每页大小
 	QuestionBankID *string  `json:"questionBankId,omitempty"` // 题库 ID
+	ReviewStatus   *int     `json:"reviewStatus,omitempty"`   // 审核状态
 	SearchText     *string  `json:"searchText,omitempty"`     // 搜索文本
 	SortField      *string  `json:"sortField,omitempty"`      // 排序字段
 	SortOrder      *string  `json:"sortOrder,omitempty"`      // 排序顺序
@@ -42,10 +64,11 @@ type QuestionQueryResponseData[T any] struct {
 // 添加题目
 
 type AddQuestionRequest struct {
-	Answer  *string  `json:"answer,omitempty"`  // 回答内容
-	Content *string  `json:"content,omitempty"` // 内容
-	Tags    []string `json:"tags,omitempty"`    // 标签列表
-	Title   *string  `json:"title,omitempty"`   // 标题
+	Answer         *string  `json:"answer,omitempty"`         // 回答内容
+	Content        *string  `json:"content,omitempty"`        // 内容
+	Tags           []string `json:"tags,omitempty"`           // 标签列表
+	Title          *string  `json:"title,omitempty"`          // 标题
+	CheckDuplicate *bool    `json:"checkDuplicate,omitempty"` // 是否检查疑似重复题目，存在时不创建并返回相似题目
 }
 
 // 删除题目
@@ -79,6 +102,17 @@ type QuestionVO struct {
 	UpdateTime *time.Time `json:"updateTime,omitempty"` // 更新时间
 	User       *UserVO    `json:"user,omitempty"`       // 用户信息
 	UserID     *string    `json:"userId,omitempty"`     // 用户 ID
+
+	ReviewStatus  *int    `json:"reviewStatus,omitempty"`  // 审核状态
+	ReviewMessage *string `json:"reviewMessage,omitempty"` // 审核信息
+
+	ViewNum   *int  `json:"viewNum,omitempty"`   // 浏览量
+	ThumbNum  *int  `json:"thumbNum,omitempty"`  // 点赞数
+	FavourNum *int  `json:"favourNum,omitempty"` // 收藏数
+	HasThumb  *bool `json:"hasThumb,omitempty"`  // 当前用户是否已点赞
+	HasFavour *bool `json:"hasFavour,omitempty"` // 当前用户是否已收藏
+
+	Highlight *QuestionHighlight `json:"highlight,omitempty"` // 搜索命中的高亮片段
 }
 type PageQuestionVO struct {
 	CountId          *string      `json:"countId,omitempty"`          // 计数 ID
@@ -91,6 +125,9 @@ type PageQuestionVO struct {
 	SearchCount      *bool        `json:"searchCount,omitempty"`      // 是否搜索计数
 	Size             *int         `json:"size,omitempty"`             // 每页大小
 	Total            *int         `json:"total,omitempty"`            // 总记录数
+	TagFacets        []TagFacet   `json:"tagFacets,omitempty"`        // 标签聚合，仅搜索接口返回
+	SearchBackend    *string      `json:"searchBackend,omitempty"`    // 提供搜索结果的后端：elasticsearch 或 database
+	SearchID         *string      `json:"searchId,omitempty"`         // 搜索 ID，点击结果时回传用于统计点击率
 }
 
 type SearchResult struct {
@@ -109,8 +146,53 @@ type BatchDeleteQuestionRequest struct {
 	QuestionIdList []string `json:"questionIdList,omitempty"`
 }
 
-// AddQuestionByAIRequest 通过 AI 生成题目
+// QuestionSuggestRequest 搜索建议
+type QuestionSuggestRequest struct {
+	Prefix *string `form:"prefix,omitempty"` // 用户已输入的内容
+	Size   *int    `form:"size,omitempty"`   // 返回的标题数量，默认 5，最多 10
+}
+
+// QuestionSuggestVO 搜索建议结果
+type QuestionSuggestVO struct {
+	Questions []QuestionSuggestItem `json:"questions"` // 补全的题目标题
+	Tags      []QuestionSuggestTag  `json:"tags"`      // 以该前缀开头的热门标签
+}
+
+// QuestionSuggestItem 补全的题目
+type QuestionSuggestItem struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+// QuestionSuggestTag 补全的标签
+type QuestionSuggestTag struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"` // 带有该标签的题目数
+}
+
+// HotQuestionRequest 获取热门题目
+type HotQuestionRequest struct {
+	Size *int `form:"size,omitempty"` // 数量，默认 10，最多 50
+}
+
+// AddQuestionByAIRequest 提交 AI 生成题目任务
 type AddQuestionByAIRequest struct {
-	Number    int    `json:"number,omitempty"`    // 题目数量
-	Direction string `json:"direction,omitempty"` // 题目方向
+	Number         int     `json:"number,omitempty"`         // 题目数量，默认 10，最多 50
+	Direction      string  `json:"direction,omitempty"`      // 题目方向
+	QuestionBankID *string `json:"questionBankId,omitempty"` // 生成的题目加入的题库 ID，可选
+}
+
+// SimilarQuestionRequest 相似题目查询，题目 ID 和文本二选一
+type SimilarQuestionRequest struct {
+	QuestionID *string `json:"questionId,omitempty"` // 题目 ID
+	Text       *string `json:"text,omitempty"`       // 自由文本
+	Size       *int    `json:"size,omitempty"`       // 返回数量，默认 5，最多 20
+}
+
+// SimilarQuestionVO 相似题目
+type SimilarQuestionVO struct {
+	ID         string   `json:"id"`         // 题目 ID
+	Title      string   `json:"title"`      // 标题
+	TagList    []string `json:"tagList"`    // 标签列表
+	Similarity float64  `json:"similarity"` // 余弦相似度，越接近 1 越相似
 }
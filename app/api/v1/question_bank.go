@@ -14,6 +14,11 @@ type QuestionBank struct {
 	Title       *string    `json:"title,omitempty"`
 	UpdateTime  *time.Time `json:"updateTime,omitempty"`
 	UserID      *string    `json:"userId,omitempty"`
+
+	ReviewStatus  *int       `json:"reviewStatus,omitempty"`  // 审核状态：0-待审核, 1-通过, 2-拒绝
+	ReviewMessage *string    `json:"reviewMessage,omitempty"` // 审核信息
+	ReviewerID    *string    `json:"reviewerId,omitempty"`    // 审核人 ID
+	ReviewTime    *time.Time `json:"reviewTime,omitempty"`    // 审核时间
 }
 type QuestionBankRequest struct {
 	Current               *int    `json:"current,omitempty"`               // 当前页码
@@ -23,6 +28,7 @@ type QuestionBankRequest struct {
 	NotID                 *int    `json:"notId,omitempty"`                 // 排除的 ID
 	PageSize              *int    `json:"pageSize,omitempty"`              // 每页大小
 	Picture               *string `json:"picture,omitempty"`               // 图片链接
+	ReviewStatus          *int    `json:"reviewStatus,omitempty"`          // 审核状态
 	SearchText            *string `json:"searchText,omitempty"`            // 搜索文本
 	SortField             *string `json:"sortField,omitempty"`             // 排序字段
 	SortOrder             *string `json:"sortOrder,omitempty"`             // 排序顺序
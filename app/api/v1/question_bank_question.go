@@ -5,15 +5,17 @@ import "time"
 // 根据题库id查找题目
 
 type QuestionBankQuestionQueryRequest struct {
-	Current        *int    `json:"current,omitempty"`
-	ID             *string `json:"id,omitempty"`
-	NotID          *int    `json:"notId,omitempty"`
-	PageSize       *int    `json:"pageSize,omitempty"`
-	QuestionBankID *string `json:"questionBankId,omitempty"`
-	QuestionID     *string `json:"questionId,omitempty"`
-	SortField      *string `json:"sortField,omitempty"`
-	SortOrder      *string `json:"sortOrder,omitempty"`
-	UserID         *string `json:"userId,omitempty"`
+	Current         *int       `json:"current,omitempty"`
+	ID              *string    `json:"id,omitempty"`
+	NotID           *int       `json:"notId,omitempty"`
+	PageSize        *int       `json:"pageSize,omitempty"`
+	QuestionBankID  *string    `json:"questionBankId,omitempty"`
+	QuestionID      *string    `json:"questionId,omitempty"`
+	SortField       *string    `json:"sortField,omitempty"` // createTime 或 questionOrder，默认按题号
+	SortOrder       *string    `json:"sortOrder,omitempty"`
+	UserID          *string    `json:"userId,omitempty"`          // 添加人
+	CreateTimeAfter *time.Time `json:"createTimeAfter,omitempty"` // 只查询该时间之后添加的记录
+	ReviewStatus    *int       `json:"reviewStatus,omitempty"`    // 题目审核状态
 }
 type PageQuestionBankQuestionVO struct {
 	CountID          *string                  `json:"countId,omitempty"`
@@ -32,6 +34,9 @@ type QuestionBankQuestionVO struct {
 	ID             *string    `json:"id,omitempty"`
 	QuestionBankID *string    `json:"questionBankId,omitempty"`
 	QuestionID     *string    `json:"questionId,omitempty"`
+	QuestionOrder  *int       `json:"questionOrder,omitempty"` // 题号
+	QuestionTitle  *string    `json:"questionTitle,omitempty"`
+	ReviewStatus   *int       `json:"reviewStatus,omitempty"` // 题目审核状态
 	TagList        []string   `json:"tagList,omitempty"`
 	UpdateTime     *time.Time `json:"updateTime,omitempty"`
 	User           *UserVO    `json:"user,omitempty"`
@@ -55,3 +60,58 @@ type QuestionBankQuestionBatchRemoveRequest struct {
 	QuestionBankID *string  `json:"questionBankId,omitempty"`
 	QuestionIDList []string `json:"questionIdList,omitempty"`
 }
+
+// QuestionBankQuestionPositionRequest 将题目插入 / 移动到题库的指定位置
+type QuestionBankQuestionPositionRequest struct {
+	QuestionBankID *string `json:"questionBankId,omitempty"`
+	QuestionID     *string `json:"questionId,omitempty"`
+	Position       *int    `json:"position,omitempty"` // 目标题号，从 1 开始，超出范围时放到末尾
+}
+
+// QuestionBankQuestionReorderRequest 批量调整题目顺序（拖拽排序）
+type QuestionBankQuestionReorderRequest struct {
+	QuestionBankID *string  `json:"questionBankId,omitempty"`
+	QuestionIDList []string `json:"questionIdList,omitempty"` // 按新顺序排列的题目，未列出的题目保持相对顺序排在后面
+}
+
+// QuestionBankQuestionRenumberRequest 重新编号，消除题号空洞
+type QuestionBankQuestionRenumberRequest struct {
+	QuestionBankID *string `json:"questionBankId,omitempty"`
+}
+
+// QuestionBankNavigationRequest 获取题目在题库中的上一题 / 下一题
+type QuestionBankNavigationRequest struct {
+	QuestionBankID *string `form:"questionBankId,omitempty"`
+	QuestionID     *string `form:"questionId,omitempty"`
+}
+
+// QuestionBankNavigationItem 导航中的题目
+type QuestionBankNavigationItem struct {
+	QuestionID *string `json:"questionId,omitempty"`
+	Title      *string `json:"title,omitempty"`
+}
+
+// QuestionBankNavigationVO 题目在题库中的位置及上一题 / 下一题
+type QuestionBankNavigationVO struct {
+	Position *int                        `json:"position,omitempty"` // 当前是第几题
+	Total    *int                        `json:"total,omitempty"`    // 题库题目总数
+	Previous *QuestionBankNavigationItem `json:"previous,omitempty"` // 上一题，没有时为空
+	Next     *QuestionBankNavigationItem `json:"next,omitempty"`     // 下一题，没有时为空
+}
+
+// QuestionBelongBankRequest 查询题目所属的题库
+type QuestionBelongBankRequest struct {
+	QuestionIDList []string `json:"questionIdList,omitempty"`
+}
+
+// QuestionBankBriefVO 题库简要信息
+type QuestionBankBriefVO struct {
+	ID    *string `json:"id,omitempty"`
+	Title *string `json:"title,omitempty"`
+}
+
+// QuestionBelongBankVO 题目所属的题库列表
+type QuestionBelongBankVO struct {
+	QuestionID *string               `json:"questionId,omitempty"`
+	BankList   []QuestionBankBriefVO `json:"bankList,omitempty"`
+}
@@ -0,0 +1,12 @@
+package v1
+
+// QuestionFavourRequest 题目收藏 / 取消收藏请求
+type QuestionFavourRequest struct {
+	QuestionID *string `json:"questionId,omitempty"` // 题目 ID
+}
+
+// QuestionFavourQueryRequest 分页查询我的收藏请求
+type QuestionFavourQueryRequest struct {
+	Current  *int `json:"current,omitempty"`  // 当前页码
+	PageSize *int `json:"pageSize,omitempty"` // 每页大小
+}
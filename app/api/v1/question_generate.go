@@ -0,0 +1,31 @@
+package v1
+
+import "time"
+
+// QuestionGenerateJobGetRequest 获取 AI 生成题目任务
+type QuestionGenerateJobGetRequest struct {
+	ID *string `form:"id" json:"id,omitempty"` // 任务 ID
+}
+
+// QuestionGenerateJobQueryRequest 分页查询当前用户的 AI 生成题目任务
+type QuestionGenerateJobQueryRequest struct {
+	Current  *int `json:"current,omitempty"`  // 当前页码
+	PageSize *int `json:"pageSize,omitempty"` // 每页大小
+}
+
+// QuestionGenerateJobVO AI 生成题目任务
+type QuestionGenerateJobVO struct {
+	ID             string     `json:"id"`                       // 任务 ID
+	Direction      string     `json:"direction"`                // 题目方向
+	Number         int        `json:"number"`                   // 请求生成的题目数量
+	QuestionBankID *string    `json:"questionBankId,omitempty"` // 目标题库 ID
+	Status         int        `json:"status"`                   // 状态（0-排队中、1-生成中、2-已完成、3-失败）
+	Progress       int        `json:"progress"`                 // 进度百分比
+	Total          int        `json:"total"`                    // AI 返回的有效题目数
+	ProcessedNum   int        `json:"processedNum"`             // 已处理的题目数
+	CreatedNum     int        `json:"createdNum"`               // 新建的题目数（待审核）
+	SkippedNum     int        `json:"skippedNum"`               // 因重复跳过的题目数
+	ErrorMessage   string     `json:"errorMessage,omitempty"`   // 失败原因
+	CreateTime     time.Time  `json:"createTime"`               // 创建时间
+	FinishTime     *time.Time `json:"finishTime,omitempty"`     // 结束时间
+}
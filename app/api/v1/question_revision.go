@@ -0,0 +1,55 @@
+package v1
+
+import "time"
+
+// QuestionRevisionQueryRequest 分页查询题目修订记录
+type QuestionRevisionQueryRequest struct {
+	QuestionID *string `json:"questionId,omitempty"` // 题目 ID
+	Current    *int    `json:"current,omitempty"`    // 当前页码
+	PageSize   *int    `json:"pageSize,omitempty"`   // 每页大小
+}
+
+// QuestionRevisionVO 题目修订记录
+type QuestionRevisionVO struct {
+	ID              *string    `json:"id,omitempty"`              // 修订记录 ID
+	QuestionID      *string    `json:"questionId,omitempty"`      // 题目 ID
+	Version         *int       `json:"version,omitempty"`         // 版本号
+	Title           *string    `json:"title,omitempty"`           // 标题
+	Content         *string    `json:"content,omitempty"`         // 内容
+	Answer          *string    `json:"answer,omitempty"`          // 推荐答案
+	TagList         []string   `json:"tagList,omitempty"`         // 标签列表
+	EditorID        *string    `json:"editorId,omitempty"`        // 修改人 ID
+	Action          *string    `json:"action,omitempty"`          // 操作类型：create/update/rollback
+	RollbackVersion *int       `json:"rollbackVersion,omitempty"` // 回滚到的版本号
+	CreateTime      *time.Time `json:"createTime,omitempty"`      // 修改时间
+}
+
+// QuestionRevisionDiffRequest 对比两个版本
+type QuestionRevisionDiffRequest struct {
+	QuestionID  *string `form:"questionId,omitempty"`  // 题目 ID
+	FromVersion *int    `form:"fromVersion,omitempty"` // 旧版本号
+	ToVersion   *int    `form:"toVersion,omitempty"`   // 新版本号
+}
+
+// QuestionRevisionFieldDiff 单个字段的差异
+type QuestionRevisionFieldDiff struct {
+	Field string  `json:"field"`          // 字段名：title/content/answer/tags
+	From  *string `json:"from,omitempty"` // 旧值
+	To    *string `json:"to,omitempty"`   // 新值
+}
+
+// QuestionRevisionDiffVO 两个版本之间的差异，只包含有变化的字段
+type QuestionRevisionDiffVO struct {
+	QuestionID  *string                     `json:"questionId,omitempty"`  // 题目 ID
+	FromVersion *int                        `json:"fromVersion,omitempty"` // 旧版本号
+	ToVersion   *int                        `json:"toVersion,omitempty"`   // 新版本号
+	Fields      []QuestionRevisionFieldDiff `json:"fields"`                // 有变化的字段
+	AddedTags   []string                    `json:"addedTags,omitempty"`   // 新增的标签
+	RemovedTags []string                    `json:"removedTags,omitempty"` // 删除的标签
+}
+
+// QuestionRevisionRollbackRequest 回滚到指定版本
+type QuestionRevisionRollbackRequest struct {
+	QuestionID *string `json:"questionId,omitempty"` // 题目 ID
+	Version    *int    `json:"version,omitempty"`    // 要回滚到的版本号
+}
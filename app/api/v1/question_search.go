@@ -0,0 +1,49 @@
+package v1
+
+import "time"
+
+// QuestionSearchClickRequest 上报搜索结果点击
+type QuestionSearchClickRequest struct {
+	SearchID   *string `json:"searchId,omitempty"`   // 搜索接口返回的搜索 ID
+	QuestionID *string `json:"questionId,omitempty"` // 点击的题目 ID
+	Position   *int    `json:"position,omitempty"`   // 题目在结果中的位置，从 1 开始
+}
+
+// QuestionSearchStatsRequest 搜索统计查询
+type QuestionSearchStatsRequest struct {
+	Days  *int `form:"days,omitempty"`  // 统计最近多少天，默认 7 天，最多 90 天
+	Limit *int `form:"limit,omitempty"` // 返回条数，默认 20 条，最多 100 条
+}
+
+// QuestionSearchQueryStat 搜索词统计
+type QuestionSearchQueryStat struct {
+	Query           string  `json:"query"`           // 搜索词
+	SearchCount     int     `json:"searchCount"`     // 搜索次数
+	UserCount       int     `json:"userCount"`       // 搜索的登录用户数
+	ZeroResultCount int     `json:"zeroResultCount"` // 无结果次数
+	AvgResultCount  float64 `json:"avgResultCount"`  // 平均结果数
+	AvgLatencyMs    float64 `json:"avgLatencyMs"`    // 平均耗时（毫秒）
+}
+
+// QuestionZeroResultStat 无结果搜索词统计
+type QuestionZeroResultStat struct {
+	Query          string    `json:"query"`          // 搜索词
+	SearchCount    int       `json:"searchCount"`    // 无结果的搜索次数
+	LastSearchTime time.Time `json:"lastSearchTime"` // 最近一次搜索时间
+}
+
+// QuestionClickThroughStat 搜索词点击率统计
+type QuestionClickThroughStat struct {
+	Query              string  `json:"query"`              // 搜索词
+	SearchCount        int     `json:"searchCount"`        // 搜索次数
+	ClickedSearchCount int     `json:"clickedSearchCount"` // 有点击的搜索次数
+	ClickCount         int     `json:"clickCount"`         // 点击次数
+	ClickThroughRate   float64 `json:"clickThroughRate"`   // 点击率：有点击的搜索次数 / 搜索次数
+	AvgClickPosition   float64 `json:"avgClickPosition"`   // 平均点击位置
+}
+
+// QuestionTrendingSearch 热门搜索
+type QuestionTrendingSearch struct {
+	Query       string `json:"query"`       // 搜索词
+	SearchCount int    `json:"searchCount"` // 统计窗口内搜索过的登录用户数，同一用户只计一次
+}
@@ -0,0 +1,35 @@
+package v1
+
+import "time"
+
+// AddQuestionSynonymRequest 添加同义词规则
+type AddQuestionSynonymRequest struct {
+	Rule *string `json:"rule,omitempty"` // 同义词规则，如 "k8s, kubernetes" 或 "js => javascript"
+}
+
+// UpdateQuestionSynonymRequest 修改同义词规则
+type UpdateQuestionSynonymRequest struct {
+	ID   *string `json:"id,omitempty"`   // 同义词规则 ID
+	Rule *string `json:"rule,omitempty"` // 同义词规则
+}
+
+// DeleteQuestionSynonymRequest 删除同义词规则
+type DeleteQuestionSynonymRequest struct {
+	ID *string `json:"id,omitempty"` // 同义词规则 ID
+}
+
+// QuestionSynonymQueryRequest 分页查询同义词规则
+type QuestionSynonymQueryRequest struct {
+	SearchText *string `json:"searchText,omitempty"` // 搜索文本
+	Current    *int    `json:"current,omitempty"`    // 当前页码
+	PageSize   *int    `json:"pageSize,omitempty"`   // 每页大小
+}
+
+// QuestionSynonymVO 同义词规则
+type QuestionSynonymVO struct {
+	ID         *string    `json:"id,omitempty"`         // 同义词规则 ID
+	Rule       *string    `json:"rule,omitempty"`       // 同义词规则
+	UserID     *string    `json:"userId,omitempty"`     // 创建用户 ID
+	CreateTime *time.Time `json:"createTime,omitempty"` // 创建时间
+	UpdateTime *time.Time `json:"updateTime,omitempty"` // 更新时间
+}
@@ -0,0 +1,6 @@
+package v1
+
+// QuestionThumbRequest 题目点赞 / 取消点赞请求
+type QuestionThumbRequest struct {
+	QuestionID *string `json:"questionId,omitempty"` // 题目 ID
+}
@@ -0,0 +1,15 @@
+package v1
+
+// ReviewRequest 审核请求
+type ReviewRequest struct {
+	ID            *string `json:"id,omitempty"`            // 审核对象 ID
+	ReviewStatus  *int    `json:"reviewStatus,omitempty"`  // 审核状态：1-通过, 2-拒绝
+	ReviewMessage *string `json:"reviewMessage,omitempty"` // 审核信息，拒绝时必填
+}
+
+// BatchReviewRequest 批量审核请求
+type BatchReviewRequest struct {
+	IdList        []string `json:"idList,omitempty"`        // 审核对象 ID 列表
+	ReviewStatus  *int     `json:"reviewStatus,omitempty"`  // 审核状态：1-通过, 2-拒绝
+	ReviewMessage *string  `json:"reviewMessage,omitempty"` // 审核信息，拒绝时必填
+}
@@ -37,6 +37,16 @@ func HandleError(ctx *gin.Context, httpCode int, err error, data interface{}) {
 	ctx.JSON(httpCode, resp)
 }
 
+// HandleStreamError SSE 推送开始后无法再修改状态码，以 error 事件推送与 HandleError 相同结构的错误
+func HandleStreamError(ctx *gin.Context, err error) {
+	resp := Response{Code: errorCodeMap[err], Message: err.Error(), Data: map[string]string{}}
+	if _, ok := errorCodeMap[err]; !ok {
+		resp = Response{Code: 500, Message: "unknown error", Data: map[string]string{}}
+	}
+	ctx.SSEvent("error", resp)
+	ctx.Writer.Flush()
+}
+
 type Error struct {
 	Code    int
 	Message string
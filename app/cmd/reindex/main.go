@@ -0,0 +1,27 @@
+package main
+
+import (
+	"app/cmd/reindex/wire"
+	"app/pkg/config"
+	"app/pkg/log"
+	"context"
+	"flag"
+)
+
+// 全量重建题目索引：创建新版本索引，从 MySQL 批量导入，校验文档数后将 question 别名切换到新索引
+func main() {
+	var envConf = flag.String("conf", "config/local.yml", "config path, eg: -conf ./config/local.yml")
+	flag.Parse()
+	conf := config.NewConfig(*envConf)
+
+	logger := log.NewLog(conf)
+
+	app, cleanup, err := wire.NewWire(conf, logger)
+	defer cleanup()
+	if err != nil {
+		panic(err)
+	}
+	if err = app.Run(context.Background()); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,47 @@
+//go:build wireinject
+// +build wireinject
+
+package wire
+
+import (
+	"app/internal/repository"
+	"app/internal/server"
+	"app/pkg/app"
+	"app/pkg/embedding"
+	"app/pkg/log"
+	"github.com/google/wire"
+	"github.com/spf13/viper"
+)
+
+var repositorySet = wire.NewSet(
+	repository.NewDB,
+	repository.NewRedis,
+	repository.NewElasticsearch,
+	repository.NewRepository,
+	repository.NewQuestionRepository,
+	repository.NewQuestionIndexRepository,
+	repository.NewQuestionOutboxRepository,
+	repository.NewQuestionSynonymRepository,
+)
+var serverSet = wire.NewSet(
+	server.NewReindexServer,
+)
+
+// build App
+func newApp(
+	reindexServer *server.ReindexServer,
+) *app.App {
+	return app.NewApp(
+		app.WithServer(reindexServer),
+		app.WithName("demo-reindex"),
+	)
+}
+
+func NewWire(*viper.Viper, *log.Logger) (*app.App, func(), error) {
+	panic(wire.Build(
+		repositorySet,
+		serverSet,
+		embedding.NewProvider,
+		newApp,
+	))
+}
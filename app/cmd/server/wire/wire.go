@@ -10,7 +10,9 @@ import (
 	"app/internal/server"
 	"app/internal/service"
 	"app/pkg/app"
+	"app/pkg/embedding"
 	"app/pkg/jwt"
+	"app/pkg/llm"
 	"app/pkg/log"
 	"app/pkg/server/http"
 	"app/pkg/sid"
@@ -29,6 +31,19 @@ var repositorySet = wire.NewSet(
 	repository.NewQuestionRepository,
 	repository.NewQuestionBankQuestionRepository,
 	repository.NewMockInterviewRepository,
+	repository.NewMockInterviewReportRepository,
+	repository.NewMockInterviewQuestionRepository,
+	repository.NewMockInterviewMessageRepository,
+	repository.NewQuestionThumbRepository,
+	repository.NewQuestionFavourRepository,
+	repository.NewQuestionRevisionRepository,
+	repository.NewQuestionOutboxRepository,
+	repository.NewQuestionSearcher,
+	repository.NewQuestionSynonymRepository,
+	repository.NewQuestionIndexRepository,
+	repository.NewQuestionSearchLogRepository,
+	repository.NewPromptTemplateRepository,
+	repository.NewQuestionGenerateJobRepository,
 )
 
 var serviceSet = wire.NewSet(
@@ -38,6 +53,13 @@ var serviceSet = wire.NewSet(
 	service.NewQuestionService,
 	service.NewQuestionBankQuestionService,
 	service.NewMockInterviewService,
+	service.NewQuestionThumbService,
+	service.NewQuestionFavourService,
+	service.NewQuestionRevisionService,
+	service.NewQuestionSynonymService,
+	service.NewQuestionSearchService,
+	service.NewPromptTemplateService,
+	service.NewQuestionGenerateService,
 )
 
 var handlerSet = wire.NewSet(
@@ -47,12 +69,20 @@ var handlerSet = wire.NewSet(
 	handler.NewQuestionHandler,
 	handler.NewQuestionBankQuestionHandler,
 	handler.NewMockInterviewHandler,
+	handler.NewQuestionThumbHandler,
+	handler.NewQuestionFavourHandler,
+	handler.NewQuestionRevisionHandler,
+	handler.NewQuestionSynonymHandler,
+	handler.NewQuestionSearchHandler,
+	handler.NewPromptTemplateHandler,
+	handler.NewQuestionGenerateHandler,
 )
 
 var jobSet = wire.NewSet(
 	job.NewJob,
 	job.NewUserJob,
 	job.NewQuestionJob,
+	job.NewQuestionGenerateJob,
 )
 var serverSet = wire.NewSet(
 	server.NewHTTPServer,
@@ -80,6 +110,8 @@ func NewWire(*viper.Viper, *log.Logger) (*app.App, func(), error) {
 		serverSet,
 		sid.NewSid,
 		jwt.NewJwt,
+		embedding.NewProvider,
+		llm.NewClient,
 		newApp,
 	))
 }
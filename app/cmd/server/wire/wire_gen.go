@@ -13,7 +13,9 @@ import (
 	"app/internal/server"
 	"app/internal/service"
 	"app/pkg/app"
+	"app/pkg/embedding"
 	"app/pkg/jwt"
+	"app/pkg/llm"
 	"app/pkg/log"
 	"app/pkg/server/http"
 	"app/pkg/sid"
@@ -37,36 +39,67 @@ func NewWire(viperViper *viper.Viper, logger *log.Logger) (*app.App, func(), err
 	userService := service.NewUserService(serviceService, userRepository)
 	userHandler := handler.NewUserHandler(handlerHandler, userService)
 	questionRepository := repository.NewQuestionRepository(repositoryRepository)
-	questionService := service.NewQuestionService(serviceService, questionRepository)
+	questionThumbRepository := repository.NewQuestionThumbRepository(repositoryRepository)
+	questionFavourRepository := repository.NewQuestionFavourRepository(repositoryRepository)
+	questionRevisionRepository := repository.NewQuestionRevisionRepository(repositoryRepository)
+	questionOutboxRepository := repository.NewQuestionOutboxRepository(repositoryRepository)
+	questionSearcher := repository.NewQuestionSearcher(repositoryRepository, questionRepository)
+	questionSearchLogRepository, cleanup := repository.NewQuestionSearchLogRepository(repositoryRepository)
+	promptTemplateRepository := repository.NewPromptTemplateRepository(repositoryRepository)
+	provider := embedding.NewProvider(viperViper)
+	llmClient := llm.NewClient(viperViper, logger)
+	questionService := service.NewQuestionService(serviceService, questionRepository, questionThumbRepository, questionFavourRepository, questionRevisionRepository, questionOutboxRepository, questionSearcher, questionSearchLogRepository, provider, llmClient)
 	questionHandler := handler.NewQuestionHandler(handlerHandler, questionService)
 	questionBankRepository := repository.NewQuestionBankRepository(repositoryRepository)
 	questionBankService := service.NewQuestionBankService(serviceService, questionBankRepository)
 	questionBankHandler := handler.NewQuestionBankHandler(handlerHandler, questionBankService, questionService)
 	mockInterviewRepository := repository.NewMockInterviewRepository(repositoryRepository)
-	mockInterviewService := service.NewMockInterviewService(serviceService, mockInterviewRepository)
+	mockInterviewReportRepository := repository.NewMockInterviewReportRepository(repositoryRepository)
+	mockInterviewQuestionRepository := repository.NewMockInterviewQuestionRepository(repositoryRepository)
+	mockInterviewMessageRepository := repository.NewMockInterviewMessageRepository(repositoryRepository)
+	mockInterviewService := service.NewMockInterviewService(serviceService, mockInterviewRepository, mockInterviewReportRepository, mockInterviewQuestionRepository, mockInterviewMessageRepository, promptTemplateRepository, questionRepository, questionBankRepository, llmClient, provider)
 	mockInterviewHandler := handler.NewMockInterviewHandler(handlerHandler, mockInterviewService)
 	questionBankQuestionRepository := repository.NewQuestionBankQuestionRepository(repositoryRepository)
-	questionBankQuestionService := service.NewQuestionBankQuestionService(serviceService, questionBankQuestionRepository)
+	questionBankQuestionService := service.NewQuestionBankQuestionService(serviceService, questionBankQuestionRepository, questionBankRepository, questionRepository)
 	questionBankQuestionHandler := handler.NewQuestionBankQuestionHandler(handlerHandler, questionBankQuestionService)
-	httpServer := server.NewHTTPServer(logger, viperViper, jwtJWT, client, db, userHandler, questionHandler, questionBankHandler, mockInterviewHandler, questionBankQuestionHandler)
+	questionThumbService := service.NewQuestionThumbService(serviceService, questionThumbRepository, questionRepository)
+	questionThumbHandler := handler.NewQuestionThumbHandler(handlerHandler, questionThumbService)
+	questionFavourService := service.NewQuestionFavourService(serviceService, questionFavourRepository, questionThumbRepository, questionRepository)
+	questionFavourHandler := handler.NewQuestionFavourHandler(handlerHandler, questionFavourService)
+	questionRevisionService := service.NewQuestionRevisionService(serviceService, questionRevisionRepository, questionRepository, questionOutboxRepository)
+	questionRevisionHandler := handler.NewQuestionRevisionHandler(handlerHandler, questionRevisionService)
+	questionSynonymRepository := repository.NewQuestionSynonymRepository(repositoryRepository)
+	questionIndexRepository := repository.NewQuestionIndexRepository(repositoryRepository, viperViper, provider)
+	questionSynonymService := service.NewQuestionSynonymService(serviceService, questionSynonymRepository, questionIndexRepository)
+	questionSynonymHandler := handler.NewQuestionSynonymHandler(handlerHandler, questionSynonymService)
+	questionSearchService := service.NewQuestionSearchService(serviceService, questionSearchLogRepository)
+	questionSearchHandler := handler.NewQuestionSearchHandler(handlerHandler, questionSearchService)
+	promptTemplateService := service.NewPromptTemplateService(serviceService, promptTemplateRepository)
+	promptTemplateHandler := handler.NewPromptTemplateHandler(handlerHandler, promptTemplateService)
+	questionGenerateJobRepository := repository.NewQuestionGenerateJobRepository(repositoryRepository)
+	questionGenerateService := service.NewQuestionGenerateService(serviceService, questionGenerateJobRepository, questionRepository, questionRevisionRepository, questionOutboxRepository, questionBankRepository, questionBankQuestionRepository, promptTemplateRepository, llmClient)
+	questionGenerateHandler := handler.NewQuestionGenerateHandler(handlerHandler, questionGenerateService)
+	httpServer := server.NewHTTPServer(logger, viperViper, jwtJWT, client, db, userHandler, questionHandler, questionBankHandler, mockInterviewHandler, questionBankQuestionHandler, questionThumbHandler, questionFavourHandler, questionRevisionHandler, questionSynonymHandler, questionSearchHandler, promptTemplateHandler, questionGenerateHandler)
 	jobJob := job.NewJob(transaction, logger, sidSid)
 	userJob := job.NewUserJob(jobJob, userRepository)
-	questionJob := job.NewQuestionJob(jobJob, questionRepository)
-	jobServer := server.NewJobServer(logger, userJob, questionJob)
+	questionJob := job.NewQuestionJob(jobJob, questionRepository, questionOutboxRepository, provider)
+	questionGenerateJob := job.NewQuestionGenerateJob(jobJob, questionGenerateService)
+	jobServer := server.NewJobServer(logger, userJob, questionJob, questionGenerateJob)
 	appApp := newApp(httpServer, jobServer)
 	return appApp, func() {
+		cleanup()
 	}, nil
 }
 
 // wire.go:
 
-var repositorySet = wire.NewSet(repository.NewDB, repository.NewRedis, repository.NewElasticsearch, repository.NewRepository, repository.NewTransaction, repository.NewUserRepository, repository.NewQuestionBankRepository, repository.NewQuestionRepository, repository.NewQuestionBankQuestionRepository, repository.NewMockInterviewRepository)
+var repositorySet = wire.NewSet(repository.NewDB, repository.NewRedis, repository.NewElasticsearch, repository.NewRepository, repository.NewTransaction, repository.NewUserRepository, repository.NewQuestionBankRepository, repository.NewQuestionRepository, repository.NewQuestionBankQuestionRepository, repository.NewMockInterviewRepository, repository.NewMockInterviewReportRepository, repository.NewMockInterviewQuestionRepository, repository.NewMockInterviewMessageRepository, repository.NewQuestionThumbRepository, repository.NewQuestionFavourRepository, repository.NewQuestionRevisionRepository, repository.NewQuestionOutboxRepository, repository.NewQuestionSearcher, repository.NewQuestionSynonymRepository, repository.NewQuestionIndexRepository, repository.NewQuestionSearchLogRepository, repository.NewPromptTemplateRepository, repository.NewQuestionGenerateJobRepository)
 
-var serviceSet = wire.NewSet(service.NewService, service.NewUserService, service.NewQuestionBankService, service.NewQuestionService, service.NewQuestionBankQuestionService, service.NewMockInterviewService)
+var serviceSet = wire.NewSet(service.NewService, service.NewUserService, service.NewQuestionBankService, service.NewQuestionService, service.NewQuestionBankQuestionService, service.NewMockInterviewService, service.NewQuestionThumbService, service.NewQuestionFavourService, service.NewQuestionRevisionService, service.NewQuestionSynonymService, service.NewQuestionSearchService, service.NewPromptTemplateService, service.NewQuestionGenerateService)
 
-var handlerSet = wire.NewSet(handler.NewHandler, handler.NewUserHandler, handler.NewQuestionBankHandler, handler.NewQuestionHandler, handler.NewQuestionBankQuestionHandler, handler.NewMockInterviewHandler)
+var handlerSet = wire.NewSet(handler.NewHandler, handler.NewUserHandler, handler.NewQuestionBankHandler, handler.NewQuestionHandler, handler.NewQuestionBankQuestionHandler, handler.NewMockInterviewHandler, handler.NewQuestionThumbHandler, handler.NewQuestionFavourHandler, handler.NewQuestionRevisionHandler, handler.NewQuestionSynonymHandler, handler.NewQuestionSearchHandler, handler.NewPromptTemplateHandler, handler.NewQuestionGenerateHandler)
 
-var jobSet = wire.NewSet(job.NewJob, job.NewUserJob, job.NewQuestionJob)
+var jobSet = wire.NewSet(job.NewJob, job.NewUserJob, job.NewQuestionJob, job.NewQuestionGenerateJob)
 
 var serverSet = wire.NewSet(server.NewHTTPServer, server.NewJobServer)
 
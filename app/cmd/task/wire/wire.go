@@ -16,15 +16,25 @@ import (
 
 var repositorySet = wire.NewSet(
 	repository.NewDB,
-	//repository.NewRedis,
+	repository.NewRedis,
+	repository.NewElasticsearch,
 	repository.NewRepository,
 	repository.NewTransaction,
 	repository.NewUserRepository,
+	repository.NewQuestionRepository,
+	repository.NewQuestionBankRepository,
+	repository.NewQuestionThumbRepository,
+	repository.NewQuestionFavourRepository,
+	repository.NewQuestionOutboxRepository,
+	repository.NewQuestionSearchLogRepository,
+	repository.NewMockInterviewRepository,
 )
 
 var taskSet = wire.NewSet(
 	task.NewTask,
 	task.NewUserTask,
+	task.NewQuestionTask,
+	task.NewMockInterviewTask,
 )
 var serverSet = wire.NewSet(
 	server.NewTaskServer,
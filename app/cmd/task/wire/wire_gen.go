@@ -21,23 +21,35 @@ import (
 
 func NewWire(viperViper *viper.Viper, logger *log.Logger) (*app.App, func(), error) {
 	db := repository.NewDB(viperViper, logger)
-	repositoryRepository := repository.NewRepository(logger, db)
+	client := repository.NewRedis(viperViper)
+	elasticsearchClient := repository.NewElasticsearch(viperViper)
+	repositoryRepository := repository.NewRepository(logger, db, client, elasticsearchClient)
 	transaction := repository.NewTransaction(repositoryRepository)
 	sidSid := sid.NewSid()
 	taskTask := task.NewTask(transaction, logger, sidSid)
 	userRepository := repository.NewUserRepository(repositoryRepository)
 	userTask := task.NewUserTask(taskTask, userRepository)
-	taskServer := server.NewTaskServer(logger, userTask)
+	questionRepository := repository.NewQuestionRepository(repositoryRepository)
+	questionBankRepository := repository.NewQuestionBankRepository(repositoryRepository)
+	questionThumbRepository := repository.NewQuestionThumbRepository(repositoryRepository)
+	questionFavourRepository := repository.NewQuestionFavourRepository(repositoryRepository)
+	questionOutboxRepository := repository.NewQuestionOutboxRepository(repositoryRepository)
+	questionSearchLogRepository, cleanup := repository.NewQuestionSearchLogRepository(repositoryRepository)
+	questionTask := task.NewQuestionTask(taskTask, questionRepository, questionBankRepository, questionThumbRepository, questionFavourRepository, questionOutboxRepository, questionSearchLogRepository)
+	mockInterviewRepository := repository.NewMockInterviewRepository(repositoryRepository)
+	mockInterviewTask := task.NewMockInterviewTask(taskTask, viperViper, mockInterviewRepository)
+	taskServer := server.NewTaskServer(logger, userTask, questionTask, mockInterviewTask)
 	appApp := newApp(taskServer)
 	return appApp, func() {
+		cleanup()
 	}, nil
 }
 
 // wire.go:
 
-var repositorySet = wire.NewSet(repository.NewDB, repository.NewRepository, repository.NewTransaction, repository.NewUserRepository)
+var repositorySet = wire.NewSet(repository.NewDB, repository.NewRedis, repository.NewElasticsearch, repository.NewRepository, repository.NewTransaction, repository.NewUserRepository, repository.NewQuestionRepository, repository.NewQuestionBankRepository, repository.NewQuestionThumbRepository, repository.NewQuestionFavourRepository, repository.NewQuestionOutboxRepository, repository.NewQuestionSearchLogRepository, repository.NewMockInterviewRepository)
 
-var taskSet = wire.NewSet(task.NewTask, task.NewUserTask)
+var taskSet = wire.NewSet(task.NewTask, task.NewUserTask, task.NewQuestionTask, task.NewMockInterviewTask)
 
 var serverSet = wire.NewSet(server.NewTaskServer)
 
@@ -25,3 +25,12 @@ func GetUserIdFromCtx(ctx *gin.Context) string {
 	}
 	return utils.Uint64TOString(v.(*jwt.MyCustomClaims).User.ID)
 }
+
+// GetLoginUserFromCtx 获取当前登录用户，未登录时返回 nil
+func GetLoginUserFromCtx(ctx *gin.Context) *jwt.User {
+	v, exists := ctx.Get("claims")
+	if !exists {
+		return nil
+	}
+	return &v.(*jwt.MyCustomClaims).User
+}
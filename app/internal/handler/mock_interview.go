@@ -3,8 +3,9 @@ package handler
 import (
 	v1 "app/api/v1"
 	"app/internal/service"
-	"github.com/gin-contrib/sessions"
+	"errors"
 	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
 	"net/http"
 )
 
@@ -24,21 +25,13 @@ func NewMockInterviewHandler(
 }
 
 func (h *MockInterviewHandler) AddMockInterview(ctx *gin.Context) {
-	session := sessions.Default(ctx)
-	t := session.Get("user_login")
-	if t == nil {
-		v1.HandleError(ctx, http.StatusUnauthorized, v1.NotLoginError, nil)
-		return
-	}
-	token := t.(string)
-
 	var req v1.MockInterviewAddRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
 		return
 	}
 
-	ok, err := h.mockInterviewService.AddMockInterview(ctx, &req, token)
+	ok, err := h.mockInterviewService.AddMockInterview(ctx, &req, GetLoginUserFromCtx(ctx))
 	if err != nil {
 		v1.HandleError(ctx, http.StatusUnauthorized, err, nil)
 		return
@@ -54,7 +47,7 @@ func (h *MockInterviewHandler) GetMockInterview(ctx *gin.Context) {
 		return
 	}
 
-	ok, err := h.mockInterviewService.GetMockInterview(ctx, &req)
+	ok, err := h.mockInterviewService.GetMockInterview(ctx, &req, GetLoginUserFromCtx(ctx))
 	if err != nil {
 		v1.HandleError(ctx, http.StatusUnauthorized, err, nil)
 		return
@@ -70,7 +63,7 @@ func (h *MockInterviewHandler) MockInterview(ctx *gin.Context) {
 		return
 	}
 
-	ok, err := h.mockInterviewService.MockInterview(ctx, &req)
+	ok, err := h.mockInterviewService.MockInterview(ctx, &req, GetLoginUserFromCtx(ctx))
 	if err != nil {
 		v1.HandleError(ctx, http.StatusUnauthorized, err, nil)
 		return
@@ -79,22 +72,147 @@ func (h *MockInterviewHandler) MockInterview(ctx *gin.Context) {
 	v1.HandleSuccess(ctx, ok)
 }
 
-func (h *MockInterviewHandler) ListPage(ctx *gin.Context) {
-	session := sessions.Default(ctx)
-	t := session.Get("user_login")
-	if t == nil {
-		v1.HandleError(ctx, http.StatusUnauthorized, v1.NotLoginError, nil)
+// MockInterviewStream 以 SSE 流式推送面试官回复：message 事件为增量内容，done 事件为完整回复和面试状态，
+// 推送开始后出错时发送 error 事件；客户端断开时取消上游 AI 请求，本轮对话不会写入记录
+func (h *MockInterviewHandler) MockInterviewStream(ctx *gin.Context) {
+	var req v1.MockInterviewEventRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	started := false
+	result, err := h.mockInterviewService.MockInterviewStream(ctx.Request.Context(), &req, GetLoginUserFromCtx(ctx), func(delta string) error {
+		if !started {
+			started = true
+			ctx.Header("Content-Type", "text/event-stream")
+			ctx.Header("Cache-Control", "no-cache")
+			ctx.Header("Connection", "keep-alive")
+			ctx.Header("X-Accel-Buffering", "no")
+			ctx.Status(http.StatusOK)
+		}
+		ctx.SSEvent("message", gin.H{"content": delta})
+		ctx.Writer.Flush()
+		return ctx.Request.Context().Err()
+	})
+	if err != nil {
+		if ctx.Request.Context().Err() != nil {
+			// 客户端已断开，无需响应
+			return
+		}
+		h.logger.WithContext(ctx).Error("mockInterviewService.MockInterviewStream error", zap.Uint64("id", req.ID), zap.Error(err))
+		if !started {
+			if errors.Is(err, v1.ErrMockInterviewChat) {
+				v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
+				return
+			}
+			v1.HandleError(ctx, http.StatusBadRequest, err, nil)
+			return
+		}
+		v1.HandleStreamError(ctx, err)
+		return
+	}
+	ctx.SSEvent("done", result)
+	ctx.Writer.Flush()
+}
+
+// GetMockInterviewReport 获取模拟面试评估报告
+func (h *MockInterviewHandler) GetMockInterviewReport(ctx *gin.Context) {
+	var req v1.MockInterviewReportRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil || req.ID == 0 {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
 		return
 	}
-	token := t.(string)
 
+	report, err := h.mockInterviewService.GetMockInterviewReport(ctx, &req, GetLoginUserFromCtx(ctx))
+	if err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	v1.HandleSuccess(ctx, report)
+}
+
+// ListMessagePage 分页获取模拟面试的对话记录
+func (h *MockInterviewHandler) ListMessagePage(ctx *gin.Context) {
+	var req v1.MockInterviewMessageQueryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil || req.MockInterviewID == 0 {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	page, err := h.mockInterviewService.ListMockInterviewMessage(ctx, &req, GetLoginUserFromCtx(ctx))
+	if err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	v1.HandleSuccess(ctx, page)
+}
+
+// Export 导出已结束的模拟面试记录为 Markdown 或 HTML 文件
+func (h *MockInterviewHandler) Export(ctx *gin.Context) {
+	var req v1.MockInterviewExportRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil || req.ID == 0 {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	file, err := h.mockInterviewService.ExportMockInterview(ctx, &req, GetLoginUserFromCtx(ctx))
+	if err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	ctx.Header("Content-Disposition", `attachment; filename="`+file.FileName+`"`)
+	ctx.Data(http.StatusOK, file.ContentType, file.Content)
+}
+
+// Share 创建模拟面试的分享链接
+func (h *MockInterviewHandler) Share(ctx *gin.Context) {
+	var req v1.MockInterviewShareRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil || req.ID == 0 {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	share, err := h.mockInterviewService.ShareMockInterview(ctx, &req, GetLoginUserFromCtx(ctx))
+	if err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	v1.HandleSuccess(ctx, share)
+}
+
+// ViewShare 通过分享链接查看模拟面试记录，无需登录
+func (h *MockInterviewHandler) ViewShare(ctx *gin.Context) {
+	var req v1.MockInterviewShareViewRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil || req.Token == "" {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	file, err := h.mockInterviewService.ViewSharedMockInterview(ctx, &req)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	ctx.Header("Content-Disposition", `inline; filename="`+file.FileName+`"`)
+	ctx.Header("Cache-Control", "private, no-store")
+	ctx.Header("X-Robots-Tag", "noindex")
+	ctx.Data(http.StatusOK, file.ContentType, file.Content)
+}
+
+func (h *MockInterviewHandler) ListPage(ctx *gin.Context) {
 	var req v1.MockInterviewQueryRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
 		return
 	}
 
-	ok, err := h.mockInterviewService.ListMockInterview(ctx, &req, token)
+	ok, err := h.mockInterviewService.ListMockInterview(ctx, &req, GetLoginUserFromCtx(ctx))
 	if err != nil {
 		v1.HandleError(ctx, http.StatusUnauthorized, err, nil)
 		return
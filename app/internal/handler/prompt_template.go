@@ -0,0 +1,103 @@
+package handler
+
+import (
+	v1 "app/api/v1"
+	"app/internal/service"
+	"app/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type PromptTemplateHandler struct {
+	*Handler
+	promptTemplateService service.PromptTemplateService
+}
+
+func NewPromptTemplateHandler(
+	handler *Handler,
+	promptTemplateService service.PromptTemplateService,
+) *PromptTemplateHandler {
+	return &PromptTemplateHandler{
+		Handler:               handler,
+		promptTemplateService: promptTemplateService,
+	}
+}
+
+// SavePromptTemplate 保存 Prompt 模板，生成新版本
+func (h *PromptTemplateHandler) SavePromptTemplate(ctx *gin.Context) {
+	var req v1.SavePromptTemplateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	userId, err := utils.StringToUint64(GetUserIdFromCtx(ctx))
+	if err != nil {
+		v1.HandleError(ctx, http.StatusUnauthorized, v1.NotLoginError, nil)
+		return
+	}
+	id, err := h.promptTemplateService.SavePromptTemplate(ctx, &req, userId)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, id)
+}
+
+// GetPromptTemplate 获取 Prompt 模板版本
+func (h *PromptTemplateHandler) GetPromptTemplate(ctx *gin.Context) {
+	var req v1.GetPromptTemplateRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	template, err := h.promptTemplateService.GetPromptTemplate(ctx, &req)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, template)
+}
+
+// DeletePromptTemplate 停用场景、风格下的全部版本
+func (h *PromptTemplateHandler) DeletePromptTemplate(ctx *gin.Context) {
+	var req v1.DeletePromptTemplateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	ok, err := h.promptTemplateService.DeletePromptTemplate(ctx, &req)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, ok)
+}
+
+// ListPage 分页获取 Prompt 模板版本
+func (h *PromptTemplateHandler) ListPage(ctx *gin.Context) {
+	var req v1.PromptTemplateQueryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	page, err := h.promptTemplateService.ListPromptTemplateByPage(ctx, &req)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, page)
+}
+
+// ListMockInterviewPersona 获取创建模拟面试时可选的面试官风格
+func (h *PromptTemplateHandler) ListMockInterviewPersona(ctx *gin.Context) {
+	personas, err := h.promptTemplateService.ListMockInterviewPersona(ctx)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, personas)
+}
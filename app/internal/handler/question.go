@@ -3,7 +3,8 @@ package handler
 import (
 	v1 "app/api/v1"
 	"app/internal/service"
-	"github.com/gin-contrib/sessions"
+	"app/pkg/constant"
+	"app/pkg/utils"
 	"github.com/gin-gonic/gin"
 	"net/http"
 )
@@ -47,21 +48,26 @@ func (h *QuestionHandler) ListPage(ctx *gin.Context) {
 }
 
 func (h *QuestionHandler) AddQuestion(ctx *gin.Context) {
-	session := sessions.Default(ctx)
-	t := session.Get("user_login")
-	if t == nil {
-		v1.HandleError(ctx, http.StatusUnauthorized, v1.NotLoginError, nil)
-		return
-	}
-	token := t.(string)
-
 	var req v1.AddQuestionRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
 		return
 	}
 
-	id, err := h.questionService.AddQuestion(ctx, &req, token)
+	// 疑似重复时不创建，返回相似题目，由用户确认后不带检查参数重新提交
+	if req.CheckDuplicate != nil && *req.CheckDuplicate {
+		duplicates, err := h.questionService.ListNearDuplicateQuestion(ctx, &req)
+		if err != nil {
+			v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
+			return
+		}
+		if len(duplicates) > 0 {
+			v1.HandleError(ctx, http.StatusBadRequest, v1.ErrQuestionNearDuplicate, duplicates)
+			return
+		}
+	}
+
+	id, err := h.questionService.AddQuestion(ctx, &req, GetLoginUserFromCtx(ctx))
 	if err != nil {
 		v1.HandleError(ctx, http.StatusUnauthorized, err, nil)
 		return
@@ -91,7 +97,7 @@ func (h *QuestionHandler) UpdateQuestion(ctx *gin.Context) {
 		return
 	}
 
-	ok, err := h.questionService.UpdateQuestion(ctx, &req)
+	ok, err := h.questionService.UpdateQuestion(ctx, &req, GetLoginUserFromCtx(ctx))
 	if err != nil {
 		v1.HandleError(ctx, http.StatusUnauthorized, err, nil)
 		return
@@ -106,11 +112,22 @@ func (h *QuestionHandler) GetQuestion(ctx *gin.Context) {
 		return
 	}
 
+	// 命中热点缓存时只需补充当前用户的点赞、收藏状态
+	if cached, ok := ctx.Get("cachedQuestion"); ok {
+		question := cached.(v1.QuestionVO)
+		if err := h.questionService.FillQuestionUserState(ctx, &question, GetLoginUserFromCtx(ctx)); err != nil {
+			v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
+			return
+		}
+		v1.HandleSuccess(ctx, question)
+		return
+	}
+
 	// 获取题目热度信息
 	isHot, _ := ctx.Get("isHot")
 	cacheKey, _ := ctx.Get("cacheKey")
 
-	question, err := h.questionService.GetQuestionById(ctx, &req, isHot, cacheKey)
+	question, err := h.questionService.GetQuestionById(ctx, &req, isHot, cacheKey, GetLoginUserFromCtx(ctx))
 	if err != nil {
 		v1.HandleError(ctx, http.StatusUnauthorized, err, nil)
 		return
@@ -126,7 +143,11 @@ func (h *QuestionHandler) ListPageVo(ctx *gin.Context) {
 		return
 	}
 
-	question, err := h.questionService.ListQuestionVoByPage(ctx, &req)
+	// 公开接口只返回审核通过的题目
+	reviewStatus := constant.ReviewStatusPass
+	req.ReviewStatus = &reviewStatus
+
+	question, err := h.questionService.ListQuestionVoByPage(ctx, &req, GetLoginUserFromCtx(ctx))
 	if err != nil {
 		v1.HandleError(ctx, http.StatusUnauthorized, err, nil)
 		return
@@ -142,7 +163,11 @@ func (h *QuestionHandler) SearchPageVo(ctx *gin.Context) {
 		return
 	}
 
-	question, err := h.questionService.SearchQuestionVoByPage(ctx, &req)
+	// 公开接口只返回审核通过的题目
+	reviewStatus := constant.ReviewStatusPass
+	req.ReviewStatus = &reviewStatus
+
+	question, err := h.questionService.SearchQuestionVoByPage(ctx, &req, GetLoginUserFromCtx(ctx))
 	if err != nil {
 		v1.HandleError(ctx, http.StatusUnauthorized, err, nil)
 		return
@@ -151,6 +176,38 @@ func (h *QuestionHandler) SearchPageVo(ctx *gin.Context) {
 	v1.HandleSuccess(ctx, question)
 }
 
+func (h *QuestionHandler) ListSimilarQuestion(ctx *gin.Context) {
+	var req v1.SimilarQuestionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	similar, err := h.questionService.ListSimilarQuestion(ctx, &req)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	v1.HandleSuccess(ctx, similar)
+}
+
+func (h *QuestionHandler) SuggestQuestion(ctx *gin.Context) {
+	var req v1.QuestionSuggestRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	suggest, err := h.questionService.SuggestQuestion(ctx, &req)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	v1.HandleSuccess(ctx, suggest)
+}
+
 func (h *QuestionHandler) DeleteBatchQuestion(ctx *gin.Context) {
 	var req v1.BatchDeleteQuestionRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
@@ -167,26 +224,76 @@ func (h *QuestionHandler) DeleteBatchQuestion(ctx *gin.Context) {
 	v1.HandleSuccess(ctx, ok)
 }
 
-func (h *QuestionHandler) AiGenerateQuestion(ctx *gin.Context) {
-	session := sessions.Default(ctx)
-	t := session.Get("user_login")
-	if t == nil {
+func (h *QuestionHandler) ListMyPage(ctx *gin.Context) {
+	var req v1.QuestionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	page, err := h.questionService.ListMyQuestionByPage(ctx, &req, GetLoginUserFromCtx(ctx))
+	if err != nil {
+		v1.HandleError(ctx, http.StatusUnauthorized, err, nil)
+		return
+	}
+
+	v1.HandleSuccess(ctx, page)
+}
+
+func (h *QuestionHandler) ReviewQuestion(ctx *gin.Context) {
+	var req v1.ReviewRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	reviewerId, err := utils.StringToUint64(GetUserIdFromCtx(ctx))
+	if err != nil {
 		v1.HandleError(ctx, http.StatusUnauthorized, v1.NotLoginError, nil)
 		return
 	}
-	token := t.(string)
+	ok, err := h.questionService.ReviewQuestion(ctx, &req, reviewerId)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, err, nil)
+		return
+	}
 
-	var req v1.AddQuestionByAIRequest
+	v1.HandleSuccess(ctx, ok)
+}
+
+func (h *QuestionHandler) BatchReviewQuestion(ctx *gin.Context) {
+	var req v1.BatchReviewRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
 		return
 	}
 
-	ok, err := h.questionService.AddQuestionByAI(ctx, &req, token)
+	reviewerId, err := utils.StringToUint64(GetUserIdFromCtx(ctx))
 	if err != nil {
-		v1.HandleError(ctx, http.StatusUnauthorized, err, nil)
+		v1.HandleError(ctx, http.StatusUnauthorized, v1.NotLoginError, nil)
+		return
+	}
+	ok, err := h.questionService.BatchReviewQuestion(ctx, &req, reviewerId)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, err, nil)
 		return
 	}
 
 	v1.HandleSuccess(ctx, ok)
 }
+
+func (h *QuestionHandler) ListHotQuestion(ctx *gin.Context) {
+	var req v1.HotQuestionRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	questions, err := h.questionService.ListHotQuestion(ctx, &req, GetLoginUserFromCtx(ctx))
+	if err != nil {
+		v1.HandleError(ctx, http.StatusUnauthorized, err, nil)
+		return
+	}
+
+	v1.HandleSuccess(ctx, questions)
+}
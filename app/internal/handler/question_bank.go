@@ -3,6 +3,8 @@ package handler
 import (
 	v1 "app/api/v1"
 	"app/internal/service"
+	"app/pkg/constant"
+	"app/pkg/utils"
 	"github.com/gin-gonic/gin"
 	"net/http"
 	"strconv"
@@ -48,13 +50,36 @@ func (h *QuestionBankHandler) ListPage(ctx *gin.Context) {
 	})
 }
 
+// ListPublicPage 分页获取审核通过的题库
+func (h *QuestionBankHandler) ListPublicPage(ctx *gin.Context) {
+	var req v1.QuestionBankRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+	reviewStatus := constant.ReviewStatusPass
+	req.ReviewStatus = &reviewStatus
+
+	page, err := h.questionBankService.ListBankByPage(ctx, &req)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusUnauthorized, err, nil)
+		return
+	}
+
+	v1.HandleSuccess(ctx, page)
+}
+
 func (h *QuestionBankHandler) GetQuestionBank(ctx *gin.Context) {
 	var req v1.GetQuestionBankRequest
 	if err := ctx.ShouldBindQuery(&req); err != nil {
 		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
 		return
 	}
-	bank, err := h.questionBankService.GetQuestionBankById(ctx, &req)
+	bank, err := h.questionBankService.GetQuestionBankById(ctx, &req, GetLoginUserFromCtx(ctx))
+	if err != nil {
+		v1.HandleError(ctx, http.StatusUnauthorized, err, nil)
+		return
+	}
 	id, err := strconv.ParseUint(*req.ID, 10, 64)
 	if err != nil {
 		v1.HandleError(ctx, http.StatusUnauthorized, err, nil)
@@ -142,3 +167,43 @@ func (h *QuestionBankHandler) ListPageVO(ctx *gin.Context) {
 	bank.QuestionPage = &questions
 	v1.HandleSuccess(ctx, bank)
 }
+
+func (h *QuestionBankHandler) ReviewQuestionBank(ctx *gin.Context) {
+	var req v1.ReviewRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	reviewerId, err := utils.StringToUint64(GetUserIdFromCtx(ctx))
+	if err != nil {
+		v1.HandleError(ctx, http.StatusUnauthorized, v1.NotLoginError, nil)
+		return
+	}
+	ok, err := h.questionBankService.ReviewQuestionBank(ctx, &req, reviewerId)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, ok)
+}
+
+func (h *QuestionBankHandler) BatchReviewQuestionBank(ctx *gin.Context) {
+	var req v1.BatchReviewRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	reviewerId, err := utils.StringToUint64(GetUserIdFromCtx(ctx))
+	if err != nil {
+		v1.HandleError(ctx, http.StatusUnauthorized, v1.NotLoginError, nil)
+		return
+	}
+	ok, err := h.questionBankService.BatchReviewQuestionBank(ctx, &req, reviewerId)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, ok)
+}
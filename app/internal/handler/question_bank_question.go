@@ -3,6 +3,8 @@ package handler
 import (
 	v1 "app/api/v1"
 	"app/internal/service"
+	"app/pkg/constant"
+	"app/pkg/utils"
 	"github.com/gin-gonic/gin"
 	"net/http"
 )
@@ -29,6 +31,10 @@ func (h *QuestionBankQuestionHandler) GetQuestionBankQuestion(ctx *gin.Context)
 		return
 	}
 
+	// 公开接口只返回审核通过的题目
+	reviewStatus := constant.ReviewStatusPass
+	req.ReviewStatus = &reviewStatus
+
 	questionBankQuestion, err := h.questionBankQuestionService.ListQuestionBankQuestion(ctx, &req)
 	if err != nil {
 		v1.HandleError(ctx, http.StatusUnauthorized, err, nil)
@@ -45,7 +51,12 @@ func (h *QuestionBankQuestionHandler) AddQuestionBankQuestion(ctx *gin.Context)
 		return
 	}
 
-	questionBankQuestionId, err := h.questionBankQuestionService.AddQuestionBankQuestion(ctx, &req)
+	userId, err := utils.StringToUint64(GetUserIdFromCtx(ctx))
+	if err != nil {
+		v1.HandleError(ctx, http.StatusUnauthorized, v1.NotLoginError, nil)
+		return
+	}
+	questionBankQuestionId, err := h.questionBankQuestionService.AddQuestionBankQuestion(ctx, &req, userId)
 	if err != nil {
 		v1.HandleError(ctx, http.StatusUnauthorized, err, nil)
 		return
@@ -77,7 +88,12 @@ func (h *QuestionBankQuestionHandler) BatchAddQuestionBankQuestion(ctx *gin.Cont
 		return
 	}
 
-	ok, err := h.questionBankQuestionService.BatchAddQuestionBankQuestion(ctx, &req)
+	userId, err := utils.StringToUint64(GetUserIdFromCtx(ctx))
+	if err != nil {
+		v1.HandleError(ctx, http.StatusUnauthorized, v1.NotLoginError, nil)
+		return
+	}
+	ok, err := h.questionBankQuestionService.BatchAddQuestionBankQuestion(ctx, &req, userId)
 	if err != nil {
 		v1.HandleError(ctx, http.StatusUnauthorized, err, nil)
 		return
@@ -100,3 +116,120 @@ func (h *QuestionBankQuestionHandler) BatchRemoveQuestionBankQuestion(ctx *gin.C
 	}
 	v1.HandleSuccess(ctx, ok)
 }
+
+func (h *QuestionBankQuestionHandler) InsertQuestionBankQuestion(ctx *gin.Context) {
+	var req v1.QuestionBankQuestionPositionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	userId, err := utils.StringToUint64(GetUserIdFromCtx(ctx))
+	if err != nil {
+		v1.HandleError(ctx, http.StatusUnauthorized, v1.NotLoginError, nil)
+		return
+	}
+	questionBankQuestionId, err := h.questionBankQuestionService.InsertQuestionBankQuestion(ctx, &req, userId)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusUnauthorized, err, nil)
+		return
+	}
+
+	v1.HandleSuccess(ctx, questionBankQuestionId)
+}
+
+func (h *QuestionBankQuestionHandler) MoveQuestionBankQuestion(ctx *gin.Context) {
+	var req v1.QuestionBankQuestionPositionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	ok, err := h.questionBankQuestionService.MoveQuestionBankQuestion(ctx, &req)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusUnauthorized, err, nil)
+		return
+	}
+
+	v1.HandleSuccess(ctx, ok)
+}
+
+func (h *QuestionBankQuestionHandler) ReorderQuestionBankQuestion(ctx *gin.Context) {
+	var req v1.QuestionBankQuestionReorderRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	ok, err := h.questionBankQuestionService.ReorderQuestionBankQuestion(ctx, &req)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusUnauthorized, err, nil)
+		return
+	}
+
+	v1.HandleSuccess(ctx, ok)
+}
+
+func (h *QuestionBankQuestionHandler) RenumberQuestionBankQuestion(ctx *gin.Context) {
+	var req v1.QuestionBankQuestionRenumberRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	changed, err := h.questionBankQuestionService.RenumberQuestionBankQuestion(ctx, &req)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusUnauthorized, err, nil)
+		return
+	}
+
+	v1.HandleSuccess(ctx, changed)
+}
+
+func (h *QuestionBankQuestionHandler) GetQuestionBankNavigation(ctx *gin.Context) {
+	var req v1.QuestionBankNavigationRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	navigation, err := h.questionBankQuestionService.GetQuestionBankNavigation(ctx, &req, GetLoginUserFromCtx(ctx))
+	if err != nil {
+		v1.HandleError(ctx, http.StatusUnauthorized, err, nil)
+		return
+	}
+
+	v1.HandleSuccess(ctx, navigation)
+}
+
+func (h *QuestionBankQuestionHandler) ListQuestionBankQuestion(ctx *gin.Context) {
+	var req v1.QuestionBankQuestionQueryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	page, err := h.questionBankQuestionService.ListQuestionBankQuestion(ctx, &req)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusUnauthorized, err, nil)
+		return
+	}
+
+	v1.HandleSuccess(ctx, page)
+}
+
+func (h *QuestionBankQuestionHandler) ListQuestionBelongBank(ctx *gin.Context) {
+	var req v1.QuestionBelongBankRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	belongs, err := h.questionBankQuestionService.ListQuestionBelongBank(ctx, &req)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusUnauthorized, err, nil)
+		return
+	}
+
+	v1.HandleSuccess(ctx, belongs)
+}
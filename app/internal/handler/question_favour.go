@@ -0,0 +1,55 @@
+package handler
+
+import (
+	v1 "app/api/v1"
+	"app/internal/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type QuestionFavourHandler struct {
+	*Handler
+	questionFavourService service.QuestionFavourService
+}
+
+func NewQuestionFavourHandler(
+	handler *Handler,
+	questionFavourService service.QuestionFavourService,
+) *QuestionFavourHandler {
+	return &QuestionFavourHandler{
+		Handler:               handler,
+		questionFavourService: questionFavourService,
+	}
+}
+
+// DoFavour 收藏 / 取消收藏
+func (h *QuestionFavourHandler) DoFavour(ctx *gin.Context) {
+	var req v1.QuestionFavourRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	hasFavour, err := h.questionFavourService.DoQuestionFavour(ctx, &req, GetLoginUserFromCtx(ctx))
+	if err != nil {
+		v1.HandleError(ctx, http.StatusUnauthorized, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, hasFavour)
+}
+
+// ListMyFavourPage 分页获取我收藏的题目
+func (h *QuestionFavourHandler) ListMyFavourPage(ctx *gin.Context) {
+	var req v1.QuestionFavourQueryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	page, err := h.questionFavourService.ListMyFavourQuestionByPage(ctx, &req, GetLoginUserFromCtx(ctx))
+	if err != nil {
+		v1.HandleError(ctx, http.StatusUnauthorized, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, page)
+}
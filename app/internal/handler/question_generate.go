@@ -0,0 +1,71 @@
+package handler
+
+import (
+	v1 "app/api/v1"
+	"app/internal/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type QuestionGenerateHandler struct {
+	*Handler
+	questionGenerateService service.QuestionGenerateService
+}
+
+func NewQuestionGenerateHandler(
+	handler *Handler,
+	questionGenerateService service.QuestionGenerateService,
+) *QuestionGenerateHandler {
+	return &QuestionGenerateHandler{
+		Handler:                 handler,
+		questionGenerateService: questionGenerateService,
+	}
+}
+
+// AiGenerateQuestion 提交 AI 生成题目任务，立即返回任务 ID 和状态
+func (h *QuestionGenerateHandler) AiGenerateQuestion(ctx *gin.Context) {
+	var req v1.AddQuestionByAIRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	job, err := h.questionGenerateService.SubmitQuestionGenerateJob(ctx, &req, GetLoginUserFromCtx(ctx))
+	if err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, job)
+}
+
+// GetJob 获取 AI 生成题目任务的状态和进度
+func (h *QuestionGenerateHandler) GetJob(ctx *gin.Context) {
+	var req v1.QuestionGenerateJobGetRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	job, err := h.questionGenerateService.GetQuestionGenerateJob(ctx, &req, GetLoginUserFromCtx(ctx))
+	if err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, job)
+}
+
+// ListMyJobPage 分页获取当前用户的 AI 生成题目任务
+func (h *QuestionGenerateHandler) ListMyJobPage(ctx *gin.Context) {
+	var req v1.QuestionGenerateJobQueryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	page, err := h.questionGenerateService.ListMyQuestionGenerateJobByPage(ctx, &req, GetLoginUserFromCtx(ctx))
+	if err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, page)
+}
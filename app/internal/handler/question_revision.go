@@ -0,0 +1,71 @@
+package handler
+
+import (
+	v1 "app/api/v1"
+	"app/internal/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type QuestionRevisionHandler struct {
+	*Handler
+	questionRevisionService service.QuestionRevisionService
+}
+
+func NewQuestionRevisionHandler(
+	handler *Handler,
+	questionRevisionService service.QuestionRevisionService,
+) *QuestionRevisionHandler {
+	return &QuestionRevisionHandler{
+		Handler:                 handler,
+		questionRevisionService: questionRevisionService,
+	}
+}
+
+// ListPage 分页获取题目的修订记录
+func (h *QuestionRevisionHandler) ListPage(ctx *gin.Context) {
+	var req v1.QuestionRevisionQueryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	page, err := h.questionRevisionService.ListRevisionByPage(ctx, &req, GetLoginUserFromCtx(ctx))
+	if err != nil {
+		v1.HandleError(ctx, http.StatusUnauthorized, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, page)
+}
+
+// Diff 对比两个版本的差异
+func (h *QuestionRevisionHandler) Diff(ctx *gin.Context) {
+	var req v1.QuestionRevisionDiffRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	diff, err := h.questionRevisionService.DiffRevision(ctx, &req, GetLoginUserFromCtx(ctx))
+	if err != nil {
+		v1.HandleError(ctx, http.StatusUnauthorized, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, diff)
+}
+
+// Rollback 回滚到指定版本
+func (h *QuestionRevisionHandler) Rollback(ctx *gin.Context) {
+	var req v1.QuestionRevisionRollbackRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	ok, err := h.questionRevisionService.RollbackRevision(ctx, &req, GetLoginUserFromCtx(ctx))
+	if err != nil {
+		v1.HandleError(ctx, http.StatusUnauthorized, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, ok)
+}
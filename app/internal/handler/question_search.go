@@ -0,0 +1,97 @@
+package handler
+
+import (
+	v1 "app/api/v1"
+	"app/internal/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type QuestionSearchHandler struct {
+	*Handler
+	questionSearchService service.QuestionSearchService
+}
+
+func NewQuestionSearchHandler(
+	handler *Handler,
+	questionSearchService service.QuestionSearchService,
+) *QuestionSearchHandler {
+	return &QuestionSearchHandler{
+		Handler:               handler,
+		questionSearchService: questionSearchService,
+	}
+}
+
+// Click 上报搜索结果点击
+func (h *QuestionSearchHandler) Click(ctx *gin.Context) {
+	var req v1.QuestionSearchClickRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	ok, err := h.questionSearchService.RecordClick(ctx, &req, GetLoginUserFromCtx(ctx))
+	if err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, ok)
+}
+
+// ListTrending 获取热门搜索
+func (h *QuestionSearchHandler) ListTrending(ctx *gin.Context) {
+	trending, err := h.questionSearchService.ListTrending(ctx)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, trending)
+}
+
+// ListTopQuery 统计搜索次数最多的搜索词
+func (h *QuestionSearchHandler) ListTopQuery(ctx *gin.Context) {
+	var req v1.QuestionSearchStatsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	stats, err := h.questionSearchService.ListTopQuery(ctx, &req)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, stats)
+}
+
+// ListZeroResultQuery 统计无结果次数最多的搜索词
+func (h *QuestionSearchHandler) ListZeroResultQuery(ctx *gin.Context) {
+	var req v1.QuestionSearchStatsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	stats, err := h.questionSearchService.ListZeroResultQuery(ctx, &req)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, stats)
+}
+
+// ListClickThrough 统计搜索词的点击率
+func (h *QuestionSearchHandler) ListClickThrough(ctx *gin.Context) {
+	var req v1.QuestionSearchStatsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	stats, err := h.questionSearchService.ListClickThrough(ctx, &req)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, stats)
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	v1 "app/api/v1"
+	"app/internal/service"
+	"app/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type QuestionSynonymHandler struct {
+	*Handler
+	questionSynonymService service.QuestionSynonymService
+}
+
+func NewQuestionSynonymHandler(
+	handler *Handler,
+	questionSynonymService service.QuestionSynonymService,
+) *QuestionSynonymHandler {
+	return &QuestionSynonymHandler{
+		Handler:                handler,
+		questionSynonymService: questionSynonymService,
+	}
+}
+
+// AddQuestionSynonym 添加同义词规则
+func (h *QuestionSynonymHandler) AddQuestionSynonym(ctx *gin.Context) {
+	var req v1.AddQuestionSynonymRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	userId, err := utils.StringToUint64(GetUserIdFromCtx(ctx))
+	if err != nil {
+		v1.HandleError(ctx, http.StatusUnauthorized, v1.NotLoginError, nil)
+		return
+	}
+	id, err := h.questionSynonymService.AddQuestionSynonym(ctx, &req, userId)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, id)
+}
+
+// UpdateQuestionSynonym 修改同义词规则
+func (h *QuestionSynonymHandler) UpdateQuestionSynonym(ctx *gin.Context) {
+	var req v1.UpdateQuestionSynonymRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	ok, err := h.questionSynonymService.UpdateQuestionSynonym(ctx, &req)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, ok)
+}
+
+// DeleteQuestionSynonym 删除同义词规则
+func (h *QuestionSynonymHandler) DeleteQuestionSynonym(ctx *gin.Context) {
+	var req v1.DeleteQuestionSynonymRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	ok, err := h.questionSynonymService.DeleteQuestionSynonym(ctx, &req)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, ok)
+}
+
+// ListPage 分页获取同义词规则
+func (h *QuestionSynonymHandler) ListPage(ctx *gin.Context) {
+	var req v1.QuestionSynonymQueryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	page, err := h.questionSynonymService.ListQuestionSynonymByPage(ctx, &req)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, page)
+}
+
+// Push 将全部同义词规则推送到 ES
+func (h *QuestionSynonymHandler) Push(ctx *gin.Context) {
+	ok, err := h.questionSynonymService.PushQuestionSynonym(ctx)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, ok)
+}
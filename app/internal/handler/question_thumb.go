@@ -0,0 +1,39 @@
+package handler
+
+import (
+	v1 "app/api/v1"
+	"app/internal/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type QuestionThumbHandler struct {
+	*Handler
+	questionThumbService service.QuestionThumbService
+}
+
+func NewQuestionThumbHandler(
+	handler *Handler,
+	questionThumbService service.QuestionThumbService,
+) *QuestionThumbHandler {
+	return &QuestionThumbHandler{
+		Handler:              handler,
+		questionThumbService: questionThumbService,
+	}
+}
+
+// DoThumb 点赞 / 取消点赞
+func (h *QuestionThumbHandler) DoThumb(ctx *gin.Context) {
+	var req v1.QuestionThumbRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	hasThumb, err := h.questionThumbService.DoQuestionThumb(ctx, &req, GetLoginUserFromCtx(ctx))
+	if err != nil {
+		v1.HandleError(ctx, http.StatusUnauthorized, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, hasThumb)
+}
@@ -279,19 +279,7 @@ func (h *UserHandler) UpdateUser(ctx *gin.Context) {
 // @Router /add/sign_in [post]
 func (h *UserHandler) AddUserSignIn(ctx *gin.Context) {
 	// 必须要登录才能签到
-	session := sessions.Default(ctx)
-	t := session.Get("user_login")
-	if t == nil {
-		v1.HandleError(ctx, http.StatusUnauthorized, v1.NotLoginError, nil)
-		return
-	}
-	token := t.(string)
-	if token == "" {
-		v1.HandleError(ctx, http.StatusUnauthorized, v1.NotLoginError, nil)
-		return
-	}
-
-	result, err := h.userService.AddUserSignIn(ctx, token)
+	result, err := h.userService.AddUserSignIn(ctx, GetLoginUserFromCtx(ctx))
 	if err != nil {
 		v1.HandleError(ctx, http.StatusUnauthorized, err, nil)
 		return
@@ -309,17 +297,6 @@ func (h *UserHandler) AddUserSignIn(ctx *gin.Context) {
 // @Router /get/sign_in [get]
 func (h *UserHandler) GetUserSignIn(ctx *gin.Context) {
 	// 必须要登录才能获取签到记录
-	session := sessions.Default(ctx)
-	t := session.Get("user_login")
-	if t == nil {
-		v1.HandleError(ctx, http.StatusUnauthorized, v1.NotLoginError, nil)
-		return
-	}
-	token := t.(string)
-	if token == "" {
-		v1.HandleError(ctx, http.StatusUnauthorized, v1.NotLoginError, nil)
-		return
-	}
 	var req v1.GetUserSignInRequest
 	if err := ctx.ShouldBindQuery(&req); err != nil {
 		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
@@ -333,7 +310,7 @@ func (h *UserHandler) GetUserSignIn(ctx *gin.Context) {
 		year = *req.Year
 	}
 	var dayList []int
-	dayList, err := h.userService.GetUserSignIn(ctx, token, year)
+	dayList, err := h.userService.GetUserSignIn(ctx, GetLoginUserFromCtx(ctx), year)
 	if err != nil {
 		v1.HandleError(ctx, http.StatusUnauthorized, err, nil)
 		return
@@ -1,12 +1,14 @@
 package job
 
 import (
-	"app/internal/model"
 	"app/internal/repository"
+	"app/pkg/constant"
+	"app/pkg/embedding"
 	"app/pkg/rabbmit"
 	"context"
 	"encoding/json"
-	"log"
+	"github.com/streadway/amqp"
+	"go.uber.org/zap"
 	"time"
 )
 
@@ -17,124 +19,197 @@ type QuestionJob interface {
 func NewQuestionJob(
 	job *Job,
 	questionRepo repository.QuestionRepository,
+	questionOutboxRepo repository.QuestionOutboxRepository,
+	embeddingProvider embedding.Provider,
 ) QuestionJob {
 	return &questionJob{
-		questionRepo: questionRepo,
-		Job:          job,
+		questionRepo:       questionRepo,
+		questionOutboxRepo: questionOutboxRepo,
+		embeddingProvider:  embeddingProvider,
+		outboxPublisher:    rabbmit.NewPublisher(),
+		Job:                job,
 	}
 }
 
 type questionJob struct {
-	questionRepo repository.QuestionRepository
+	questionRepo       repository.QuestionRepository
+	questionOutboxRepo repository.QuestionOutboxRepository
+	embeddingProvider  embedding.Provider
+	// outboxPublisher 变更事件投递专用的发送者，独占一个开启发布确认的 channel
+	outboxPublisher *rabbmit.Publisher
 	*Job
 }
 
+// questionSyncMessage 题目同步消息，EventID 同时作为 ES 文档的版本号
+type questionSyncMessage struct {
+	EventID    uint64 `json:"eventId"`
+	QuestionID uint64 `json:"questionId"`
+	EventType  string `json:"eventType"`
+}
+
+// DataToElasticsearch 启动消费者，并定时将变更事件表中的事件投递到消息队列
 func (t questionJob) DataToElasticsearch(ctx context.Context) error {
 	// 消费消息
-	ConsumeMessage(ctx, t)
+	go t.consumeMessage(ctx)
 
-	// 每过一分钟向消息队列发送一次消息
-	ticker := time.NewTicker(1 * time.Minute)
+	ticker := time.NewTicker(5 * time.Second)
 	defer ticker.Stop()
 
 	for {
 		select {
+		case <-ctx.Done():
+			return nil
 		case <-ticker.C:
-			err := syncDataToElasticsearch(ctx, t)
-			if err != nil {
-				t.logger.Error("Failed to sync data: %v")
+			// 一次投递一批，积压时连续投递直到没有待投递事件
+			for {
+				n, err := t.relayOutbox(ctx)
+				if err != nil {
+					t.logger.Error("relay question outbox error", zap.Error(err))
+					break
+				}
+				if n < constant.QuestionOutboxRelayBatchSize {
+					break
+				}
 			}
-			t.logger.Info("Synced data to elasticsearch")
 		}
 	}
 }
 
-func syncDataToElasticsearch(ctx context.Context, t questionJob) error {
-	fiveMinutesAgo := time.Now().Add(-5 * time.Minute)
-	question, err := t.questionRepo.GetAllQuestion(ctx, fiveMinutesAgo)
-	if err != nil {
-		return err
-	}
-
-	err = rabbmit.SendMessage(question)
-	if err != nil {
-		return err
-	}
-
-	return nil
+// relayOutbox 投递一批待投递事件，返回本批事件数；只有收到 broker 确认的事件才标记为已投递，被拒绝或确认超时的事件等待重试
+func (t questionJob) relayOutbox(ctx context.Context) (int, error) {
+	var n int
+	err := t.tm.Transaction(ctx, func(ctx context.Context) error {
+		events, err := t.questionOutboxRepo.ListPending(ctx, constant.QuestionOutboxRelayBatchSize)
+		if err != nil {
+			return err
+		}
+		n = len(events)
+		sentIds := make([]uint64, 0, len(events))
+		for i := range events {
+			body, _ := json.Marshal(questionSyncMessage{
+				EventID:    events[i].ID,
+				QuestionID: events[i].QuestionID,
+				EventType:  events[i].EventType,
+			})
+			if err := t.outboxPublisher.Publish(rabbmit.QuestionSyncQueue, body, nil); err != nil {
+				t.logger.Warn("publish question outbox event error", zap.Uint64("eventId", events[i].ID), zap.Error(err))
+				if err := t.questionOutboxRepo.MarkRetry(ctx, &events[i], err.Error()); err != nil {
+					return err
+				}
+				continue
+			}
+			sentIds = append(sentIds, events[i].ID)
+		}
+		return t.questionOutboxRepo.MarkSent(ctx, sentIds)
+	})
+	return n, err
 }
 
-func ConsumeMessage(ctx context.Context, t questionJob) {
-
-	go func() {
-		// 消费消息
-		msgs, err := rabbmit.Ch.Consume(
-			"my_queue", // 队列名称
-			"",         // 消费者名称
-			false,      // 手动确认
-			false,      // 非排他性
-			false,      // 不等待服务器确认
-			false,      // 不阻塞
-			nil,        // 无额外参数
-		)
+// consumeMessage 消费同步消息，连接断开或注册消费者失败时等待后重试，直到 ctx 结束
+func (t questionJob) consumeMessage(ctx context.Context) {
+	for {
+		// 消费者独占一个 channel，channel 被关闭时消息通道随之结束，之后重新打开 channel
+		if ch, err := rabbmit.Channel(); err != nil {
+			t.logger.Error("open rabbitmq channel error", zap.Error(err))
+		} else {
+			if msgs, err := ch.Consume(
+				rabbmit.QuestionSyncQueue, // 队列名称
+				"",                        // 消费者名称
+				false,                     // 手动确认
+				false,                     // 非排他性
+				false,                     // 不等待服务器确认
+				false,                     // 不阻塞
+				nil,                       // 无额外参数
+			); err != nil {
+				t.logger.Error("register question sync consumer error", zap.Error(err))
+			} else {
+				for d := range msgs {
+					t.handleMessage(ctx, d)
+				}
+				t.logger.Warn("question sync consumer channel closed")
+			}
+			_ = ch.Close()
+		}
 
-		if err != nil {
-			log.Fatalf("Failed to register a consumer: %s", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(5 * time.Second):
 		}
+	}
+}
 
-		// 用于阻塞协程
-		forever := make(chan bool)
-
-		go func() {
-			for d := range msgs {
-				var msg []model.Question
-				// 反序列化 JSON 数据为结构体
-				if err = json.Unmarshal(d.Body, &msg); err != nil {
-					log.Printf("Failed to unmarshal message: %s", err)
-					err = d.Nack(false, false)
-					if err != nil {
-						return
-					}
-					continue
-				}
+// handleMessage 处理一条同步消息，失败时重新入队重试，超过重试次数或无法解析时放入停放队列
+func (t questionJob) handleMessage(ctx context.Context, d amqp.Delivery) {
+	var msg questionSyncMessage
+	if err := json.Unmarshal(d.Body, &msg); err != nil || msg.EventID == 0 || msg.QuestionID == 0 {
+		t.logger.Error("invalid question sync message", zap.ByteString("body", d.Body), zap.Error(err))
+		t.parkMessage(d)
+		return
+	}
 
-				var data []model.QuestionEs
-				for _, q := range msg {
-					es := model.QuestionEs{
-						Id:         int64(q.ID),
-						UserId:     int64(q.UserID),
-						EditTime:   q.EditTime,
-						CreateTime: q.CreateTime,
-						UpdateTime: q.UpdateTime,
-						IsDelete:   q.IsDelete,
-					}
-					if q.Title != nil {
-						es.Title = *q.Title
-					}
-					if q.Content != nil {
-						es.Content = *q.Content
-					}
-					if q.Tags != nil {
-						es.Tags = *q.Tags
-					}
-					if q.Answer != nil {
-						es.Answer = *q.Answer
-					}
-					data = append(data, es)
-				}
+	err := t.applyMessage(ctx, msg)
+	if err == nil {
+		_ = d.Ack(false)
+		return
+	}
 
-				err = t.questionRepo.AddDataToEs(ctx, data)
-				if err != nil {
-					return
-				}
+	retryCount := getRetryCount(d.Headers) + 1
+	t.logger.Warn("apply question sync message error",
+		zap.Uint64("eventId", msg.EventID), zap.Int("retryCount", retryCount), zap.Error(err))
+	if retryCount > constant.QuestionSyncConsumeMaxRetry {
+		t.parkMessage(d)
+		return
+	}
+	// 等待一段时间后重新入队，避免 ES 不可用时快速空转
+	time.Sleep(time.Duration(retryCount) * time.Second)
+	if err = rabbmit.Publish(rabbmit.QuestionSyncQueue, d.Body, amqp.Table{rabbmit.RetryCountHeader: int32(retryCount)}); err != nil {
+		// 重新入队失败时交还给 RabbitMQ 重新投递
+		_ = d.Nack(false, true)
+		return
+	}
+	_ = d.Ack(false)
+}
 
-				err = d.Ack(false)
-				if err != nil {
-					return
-				} // 手动确认消息
-			}
-		}()
+// applyMessage 按数据库中的最新状态写入或删除 ES 文档，使用事件 ID 作为版本号保证乱序和重复消费时结果一致
+func (t questionJob) applyMessage(ctx context.Context, msg questionSyncMessage) error {
+	if msg.EventType == constant.QuestionOutboxEventDelete {
+		return t.questionRepo.DeleteEsQuestion(ctx, msg.QuestionID, msg.EventID)
+	}
+	question, err := t.questionRepo.GetByIDUnscoped(ctx, msg.QuestionID)
+	if err != nil {
+		return err
+	}
+	// 题目已被删除时，迟到的创建和修改事件也按删除处理
+	if question == nil || question.DeletedAt.Valid {
+		return t.questionRepo.DeleteEsQuestion(ctx, msg.QuestionID, msg.EventID)
+	}
+	doc := question.ToEs()
+	if doc.Embedding, err = t.embeddingProvider.Embed(ctx, question.EmbeddingText()); err != nil {
+		return err
+	}
+	return t.questionRepo.IndexEsQuestion(ctx, doc, msg.EventID)
+}
+
+// parkMessage 将消息放入停放队列
+func (t questionJob) parkMessage(d amqp.Delivery) {
+	if err := rabbmit.Publish(rabbmit.QuestionSyncParkingQueue, d.Body, d.Headers); err != nil {
+		t.logger.Error("park question sync message error", zap.Error(err))
+		_ = d.Nack(false, true)
+		return
+	}
+	_ = d.Ack(false)
+}
 
-		<-forever
-	}()
+// getRetryCount 获取消息已重试次数
+func getRetryCount(headers amqp.Table) int {
+	switch v := headers[rabbmit.RetryCountHeader].(type) {
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	case int:
+		return v
+	}
+	return 0
 }
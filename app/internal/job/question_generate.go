@@ -0,0 +1,100 @@
+package job
+
+import (
+	"app/internal/service"
+	"app/pkg/constant"
+	"app/pkg/rabbmit"
+	"context"
+	"github.com/streadway/amqp"
+	"go.uber.org/zap"
+	"strconv"
+	"time"
+)
+
+type QuestionGenerateJob interface {
+	ConsumeQuestionGenerate(ctx context.Context) error
+}
+
+func NewQuestionGenerateJob(
+	job *Job,
+	questionGenerateService service.QuestionGenerateService,
+) QuestionGenerateJob {
+	return &questionGenerateJob{
+		questionGenerateService: questionGenerateService,
+		Job:                     job,
+	}
+}
+
+type questionGenerateJob struct {
+	questionGenerateService service.QuestionGenerateService
+	*Job
+}
+
+// ConsumeQuestionGenerate 消费 AI 生成题目任务，连接断开或注册消费者失败时等待后重试，直到 ctx 结束
+func (t questionGenerateJob) ConsumeQuestionGenerate(ctx context.Context) error {
+	for {
+		// 消费者独占一个 channel，channel 被关闭时消息通道随之结束，之后重新打开 channel
+		if ch, err := rabbmit.Channel(); err != nil {
+			t.logger.Error("open rabbitmq channel error", zap.Error(err))
+		} else {
+			if msgs, err := ch.Consume(
+				rabbmit.QuestionGenerateQueue, // 队列名称
+				"",                            // 消费者名称
+				false,                         // 手动确认
+				false,                         // 非排他性
+				false,                         // 不等待服务器确认
+				false,                         // 不阻塞
+				nil,                           // 无额外参数
+			); err != nil {
+				t.logger.Error("register question generate consumer error", zap.Error(err))
+			} else {
+				for d := range msgs {
+					t.handleMessage(ctx, d)
+				}
+				t.logger.Warn("question generate consumer channel closed")
+			}
+			_ = ch.Close()
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
+
+// handleMessage 执行一个任务，失败时重新入队重试，超过重试次数时将任务标记为失败；无法解析的消息直接丢弃
+func (t questionGenerateJob) handleMessage(ctx context.Context, d amqp.Delivery) {
+	jobId, err := strconv.ParseUint(string(d.Body), 10, 64)
+	if err != nil {
+		t.logger.Error("invalid question generate message", zap.ByteString("body", d.Body), zap.Error(err))
+		_ = d.Ack(false)
+		return
+	}
+
+	err = t.questionGenerateService.ExecuteQuestionGenerateJob(ctx, jobId)
+	if err == nil {
+		_ = d.Ack(false)
+		return
+	}
+
+	retryCount := getRetryCount(d.Headers) + 1
+	t.logger.Warn("execute question generate job error",
+		zap.Uint64("jobId", jobId), zap.Int("retryCount", retryCount), zap.Error(err))
+	if retryCount > constant.QuestionGenerateConsumeMaxRetry {
+		if err = t.questionGenerateService.FailQuestionGenerateJob(ctx, jobId, "任务执行失败，请稍后重新提交"); err != nil {
+			t.logger.Error("fail question generate job error", zap.Uint64("jobId", jobId), zap.Error(err))
+			_ = d.Nack(false, true)
+			return
+		}
+		_ = d.Ack(false)
+		return
+	}
+	time.Sleep(time.Duration(retryCount) * time.Second)
+	if err = rabbmit.Publish(rabbmit.QuestionGenerateQueue, d.Body, amqp.Table{rabbmit.RetryCountHeader: int32(retryCount)}); err != nil {
+		_ = d.Nack(false, true)
+		return
+	}
+	_ = d.Ack(false)
+}
@@ -2,53 +2,84 @@ package middleware
 
 import (
 	v1 "app/api/v1"
+	"app/pkg/constant"
 	"encoding/json"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 func CacheByRedis(rdb *redis.Client) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		var req v1.GetQuestionRequest
-		if err := ctx.ShouldBindQuery(&req); err != nil {
+		if err := ctx.ShouldBindQuery(&req); err != nil || req.ID == nil {
 			v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+			ctx.Abort()
+			return
+		}
+		if _, err := strconv.ParseUint(*req.ID, 10, 64); err != nil {
+			v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+			ctx.Abort()
 			return
 		}
 
 		questionID := *req.ID
 		cacheKey := fmt.Sprintf("question:cache:%s", questionID)
-		countKey := fmt.Sprintf("question:count:%s", questionID)
-		hotThreshold := 100
 
-		// 访问次数加一
-		rdb.Incr(ctx, countKey)
+		// 访问次数加一：分钟桶计数用于判断热点，排行榜用于热门题目，增量用于刷回浏览量
+		bucket := constant.GetViewBucket(time.Now())
+		expiration := time.Duration(constant.HotQuestionWindowMinutes+1) * time.Minute
+		countKey := constant.GetQuestionViewCountRedisKey(questionID, bucket)
+		rankKey := constant.GetHotQuestionRankRedisKey(bucket)
+		pipe := rdb.TxPipeline()
+		pipe.Incr(ctx, countKey)
+		pipe.Expire(ctx, countKey, expiration)
+		pipe.ZIncrBy(ctx, rankKey, 1, questionID)
+		pipe.Expire(ctx, rankKey, expiration)
+		pipe.HIncrBy(ctx, constant.QuestionViewNumRedisKey, questionID, 1)
+		_, _ = pipe.Exec(ctx)
 
-		// 判断是否是热点数据
-		count, _ := rdb.Get(ctx, countKey).Int()
-		isHot := count >= hotThreshold
+		// 判断是否是热点数据，统计滑动窗口内的访问次数
+		isHot := getWindowViewCount(ctx, rdb, questionID, bucket) >= constant.HotQuestionThreshold
 
 		// 将热点判定结果存到context，方便handler使用
 		ctx.Set("isHot", isHot)
 		ctx.Set("cacheKey", cacheKey)
 
 		if isHot {
-			// 判断是否命中缓存
+			// 判断是否命中缓存，命中时交给 handler 补充当前用户的点赞、收藏状态后返回
 			cached, err := rdb.Get(ctx, cacheKey).Result()
-			question := v1.QuestionVO{}
-			err = json.Unmarshal([]byte(cached), &question)
-			if err != nil {
-				ctx.Next()
-				return
-			}
 			if err == nil && cached != "" {
-				v1.HandleSuccess(ctx, question)
-				ctx.Abort()
-				return
+				question := v1.QuestionVO{}
+				if err = json.Unmarshal([]byte(cached), &question); err == nil {
+					ctx.Set("cachedQuestion", question)
+				}
 			}
 		}
 
 		ctx.Next()
 	}
 }
+
+// getWindowViewCount 获取题目最近 HotQuestionWindowMinutes 分钟内的访问次数
+func getWindowViewCount(ctx *gin.Context, rdb *redis.Client, questionID string, bucket int64) int {
+	keys := make([]string, 0, constant.HotQuestionWindowMinutes)
+	for i := int64(0); i < constant.HotQuestionWindowMinutes; i++ {
+		keys = append(keys, constant.GetQuestionViewCountRedisKey(questionID, bucket-i))
+	}
+	values, err := rdb.MGet(ctx, keys...).Result()
+	if err != nil {
+		return 0
+	}
+	count := 0
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			n, _ := strconv.Atoi(s)
+			count += n
+		}
+	}
+	return count
+}
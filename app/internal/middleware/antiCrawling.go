@@ -2,6 +2,7 @@ package middleware
 
 import (
 	v1 "app/api/v1"
+	"app/pkg/constant"
 	"app/pkg/jwt"
 	"app/pkg/utils"
 	"fmt"
@@ -33,11 +34,6 @@ func AntiCrawling(j *jwt.JWT, rdb *redis.Client, db *gorm.DB) gin.HandlerFunc {
 			ctx.Abort()
 			return
 		}
-		// 获取 User-Agent
-		userAgent := ctx.GetHeader("User-Agent")
-		// 解析 User-Agent
-		deviceType := utils.GetDeviceType(userAgent)
-
 		// 获取用户id
 		id := claims.User.ID
 		// 获取当前的小时和分钟
@@ -68,12 +64,12 @@ func AntiCrawling(j *jwt.JWT, rdb *redis.Client, db *gorm.DB) gin.HandlerFunc {
 		// 检查访问频率
 		if count > 20 {
 			// 封禁用户
-			if err = db.Table("users").Where("id = ?", id).Update("user_role", "ban").Error; err != nil {
+			if err = db.Table("users").Where("id = ?", id).Update("user_role", constant.BanRole).Error; err != nil {
 				v1.HandleError(ctx, http.StatusUnauthorized, err, nil)
 			}
-			// 删除用户登录端的Token
+			// 删除用户所有登录端的Token，封禁对已签发的 token 立即生效
 			idStr := utils.Uint64TOString(id)
-			if err = rdb.HDel(ctx, "user_tokens:"+idStr, deviceType).Err(); err != nil {
+			if err = rdb.Del(ctx, "user_tokens:"+idStr).Err(); err != nil {
 				v1.HandleError(ctx, http.StatusUnauthorized, err, nil)
 			}
 			ctx.Abort()
@@ -2,34 +2,46 @@ package middleware
 
 import (
 	"app/api/v1"
+	"app/pkg/constant"
 	"app/pkg/jwt"
 	"app/pkg/log"
 	"app/pkg/utils"
+	"errors"
+	"github.com/gin-contrib/sessions"
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 	"net/http"
+	"strings"
 )
 
-func StrictAuth(j *jwt.JWT, logger *log.Logger) gin.HandlerFunc {
+// StrictAuth 严格鉴权，必须登录才能访问
+func StrictAuth(j *jwt.JWT, rdb *redis.Client, logger *log.Logger) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		tokenString := ctx.Request.Header.Get("Authorization")
+		tokenString := getToken(ctx)
 		if tokenString == "" {
 			logger.WithContext(ctx).Warn("No token", zap.Any("data", map[string]interface{}{
 				"url":    ctx.Request.URL,
 				"params": ctx.Params,
 			}))
-			v1.HandleError(ctx, http.StatusUnauthorized, v1.ErrUnauthorized, nil)
+			v1.HandleError(ctx, http.StatusUnauthorized, v1.NotLoginError, nil)
 			ctx.Abort()
 			return
 		}
 
-		claims, err := j.ParseToken(tokenString)
+		claims, err := parseLoginToken(ctx, j, rdb, tokenString)
 		if err != nil {
 			logger.WithContext(ctx).Error("token error", zap.Any("data", map[string]interface{}{
 				"url":    ctx.Request.URL,
 				"params": ctx.Params,
 			}), zap.Error(err))
-			v1.HandleError(ctx, http.StatusUnauthorized, v1.ErrUnauthorized, nil)
+			v1.HandleError(ctx, http.StatusUnauthorized, v1.NotLoginError, nil)
+			ctx.Abort()
+			return
+		}
+		// 被封禁的用户禁止访问
+		if claims.User.UserRole == constant.BanRole {
+			v1.HandleError(ctx, http.StatusForbidden, v1.ErrBanRole, nil)
 			ctx.Abort()
 			return
 		}
@@ -40,22 +52,17 @@ func StrictAuth(j *jwt.JWT, logger *log.Logger) gin.HandlerFunc {
 	}
 }
 
-func NoStrictAuth(j *jwt.JWT, logger *log.Logger) gin.HandlerFunc {
+// NoStrictAuth 非严格鉴权，已登录时解析出用户信息，未登录也可以访问
+func NoStrictAuth(j *jwt.JWT, rdb *redis.Client, logger *log.Logger) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		tokenString := ctx.Request.Header.Get("Authorization")
-		if tokenString == "" {
-			tokenString, _ = ctx.Cookie("accessToken")
-		}
-		if tokenString == "" {
-			tokenString = ctx.Query("accessToken")
-		}
+		tokenString := getToken(ctx)
 		if tokenString == "" {
 			ctx.Next()
 			return
 		}
 
-		claims, err := j.ParseToken(tokenString)
-		if err != nil {
+		claims, err := parseLoginToken(ctx, j, rdb, tokenString)
+		if err != nil || claims.User.UserRole == constant.BanRole {
 			ctx.Next()
 			return
 		}
@@ -66,6 +73,41 @@ func NoStrictAuth(j *jwt.JWT, logger *log.Logger) gin.HandlerFunc {
 	}
 }
 
+// getToken 获取请求携带的 token，优先使用 session 中的登录态
+func getToken(ctx *gin.Context) string {
+	session := sessions.Default(ctx)
+	if t, ok := session.Get("user_login").(string); ok && t != "" {
+		return t
+	}
+	tokenString := ctx.Request.Header.Get("Authorization")
+	if tokenString == "" {
+		tokenString, _ = ctx.Cookie("accessToken")
+	}
+	if tokenString == "" {
+		tokenString = ctx.Query("accessToken")
+	}
+	return strings.TrimPrefix(tokenString, "Bearer ")
+}
+
+// parseLoginToken 解析 token 并校验其是否为当前设备的有效登录态
+func parseLoginToken(ctx *gin.Context, j *jwt.JWT, rdb *redis.Client, tokenString string) (*jwt.MyCustomClaims, error) {
+	claims, err := j.ParseToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	// 同一设备类型只允许一个有效登录，已注销或被顶替的 token 视为未登录
+	deviceType := utils.GetDeviceType(ctx.GetHeader("User-Agent"))
+	idStr := utils.Uint64TOString(claims.User.ID)
+	nowToken, err := rdb.HGet(ctx, "user_tokens:"+idStr, deviceType).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+	if nowToken == "" || nowToken != tokenString {
+		return nil, v1.NotLoginError
+	}
+	return claims, nil
+}
+
 func recoveryLoggerFunc(ctx *gin.Context, logger *log.Logger) {
 	if userInfo, ok := ctx.MustGet("claims").(*jwt.MyCustomClaims); ok {
 		logger.WithValue(ctx, zap.String("UserId", utils.Uint64TOString(userInfo.User.ID)))
@@ -0,0 +1,148 @@
+package middleware
+
+import (
+	v1 "app/api/v1"
+	"app/pkg/constant"
+	"app/pkg/jwt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+)
+
+// roleLevel 角色等级，等级高的角色拥有等级低的角色的全部权限
+var roleLevel = map[string]int{
+	constant.NotLoginRole: 0,
+	constant.DefaultRole:  1,
+	constant.VipRole:      2,
+	constant.AdminRole:    3,
+}
+
+// routePermissions 接口权限表，key 与 Sentinel 资源名一致（请求方法:完整路由），value 为访问所需的最低角色
+// 即使接口被误注册到权限较低的路由组，也会按此表校验
+var routePermissions = map[string]string{
+	// 用户模块
+	"POST:/api/user/list/page":   constant.AdminRole,
+	"POST:/api/user/add":         constant.AdminRole,
+	"POST:/api/user/delete":      constant.AdminRole,
+	"POST:/api/user/update":      constant.AdminRole,
+	"POST:/api/user/add/sign_in": constant.DefaultRole,
+	"GET:/api/user/get/sign_in":  constant.DefaultRole,
+
+	// 题库模块
+	"POST:/api/questionBank/list/page":    constant.AdminRole,
+	"POST:/api/questionBank/add":          constant.AdminRole,
+	"POST:/api/questionBank/delete":       constant.AdminRole,
+	"POST:/api/questionBank/update":       constant.AdminRole,
+	"POST:/api/questionBank/review":       constant.AdminRole,
+	"POST:/api/questionBank/review/batch": constant.AdminRole,
+
+	// 题目模块
+	"POST:/api/question/list/page":                 constant.AdminRole,
+	"POST:/api/question/add":                       constant.DefaultRole,
+	"POST:/api/question/update":                    constant.DefaultRole,
+	"POST:/api/question/delete":                    constant.AdminRole,
+	"POST:/api/question/delete/batch":              constant.AdminRole,
+	"GET:/api/question/get/vo/test":                constant.AdminRole,
+	"POST:/api/question/review":                    constant.AdminRole,
+	"POST:/api/question/review/batch":              constant.AdminRole,
+	"POST:/api/question/my/list/page":              constant.DefaultRole,
+	"POST:/api/question/ai/generate/question":      constant.VipRole,
+	"GET:/api/question/ai/generate/job/get":        constant.VipRole,
+	"POST:/api/question/ai/generate/job/list/page": constant.VipRole,
+
+	// 题目点赞、收藏模块
+	"POST:/api/questionThumb/do":            constant.DefaultRole,
+	"POST:/api/questionFavour/do":           constant.DefaultRole,
+	"POST:/api/questionFavour/my/list/page": constant.DefaultRole,
+
+	// 题目修订记录模块
+	"POST:/api/questionRevision/list/page": constant.DefaultRole,
+	"GET:/api/questionRevision/diff":       constant.DefaultRole,
+	"POST:/api/questionRevision/rollback":  constant.DefaultRole,
+
+	// 模拟面试模块
+	"POST:/api/mockInterview/add":                constant.DefaultRole,
+	"GET:/api/mockInterview/get":                 constant.DefaultRole,
+	"POST:/api/mockInterview/handleEvent":        constant.DefaultRole,
+	"POST:/api/mockInterview/handleEvent/stream": constant.DefaultRole,
+	"POST:/api/mockInterview/my/list/page/vo":    constant.DefaultRole,
+	"GET:/api/mockInterview/report":              constant.DefaultRole,
+	"POST:/api/mockInterview/message/list/page":  constant.DefaultRole,
+	"GET:/api/mockInterview/export":              constant.DefaultRole,
+	"POST:/api/mockInterview/share":              constant.DefaultRole,
+	"GET:/api/mockInterview/persona/list":        constant.DefaultRole,
+
+	// 题目题库模块
+	"POST:/api/questionBankQuestion/add":          constant.AdminRole,
+	"POST:/api/questionBankQuestion/remove":       constant.AdminRole,
+	"POST:/api/questionBankQuestion/add/batch":    constant.AdminRole,
+	"POST:/api/questionBankQuestion/remove/batch": constant.AdminRole,
+	"POST:/api/questionBankQuestion/insert":       constant.AdminRole,
+	"POST:/api/questionBankQuestion/move":         constant.AdminRole,
+	"POST:/api/questionBankQuestion/reorder":      constant.AdminRole,
+	"POST:/api/questionBankQuestion/renumber":     constant.AdminRole,
+	"POST:/api/questionBankQuestion/list/page":    constant.AdminRole,
+	"POST:/api/questionBankQuestion/list/bank":    constant.AdminRole,
+
+	// 搜索同义词模块
+	"POST:/api/questionSynonym/add":       constant.AdminRole,
+	"POST:/api/questionSynonym/update":    constant.AdminRole,
+	"POST:/api/questionSynonym/delete":    constant.AdminRole,
+	"POST:/api/questionSynonym/list/page": constant.AdminRole,
+	"POST:/api/questionSynonym/push":      constant.AdminRole,
+
+	// 搜索统计模块
+	"GET:/api/questionSearch/stats/top":   constant.AdminRole,
+	"GET:/api/questionSearch/stats/zero":  constant.AdminRole,
+	"GET:/api/questionSearch/stats/click": constant.AdminRole,
+
+	// Prompt 模板模块
+	"POST:/api/promptTemplate/save":      constant.AdminRole,
+	"GET:/api/promptTemplate/get":        constant.AdminRole,
+	"POST:/api/promptTemplate/list/page": constant.AdminRole,
+	"POST:/api/promptTemplate/delete":    constant.AdminRole,
+}
+
+// Permission 校验当前用户是否拥有访问接口的权限
+// role 为所在路由组要求的最低角色，权限表中登记了更高要求时以权限表为准
+func Permission(role string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		needRole := role
+		if r, ok := routePermissions[ctx.Request.Method+":"+ctx.FullPath()]; ok && roleLevel[r] > roleLevel[needRole] {
+			needRole = r
+		}
+		if needRole == constant.NotLoginRole {
+			ctx.Next()
+			return
+		}
+
+		v, exists := ctx.Get("claims")
+		if !exists {
+			v1.HandleError(ctx, http.StatusUnauthorized, v1.NotLoginError, nil)
+			ctx.Abort()
+			return
+		}
+		if !CheckAccess(v.(*jwt.MyCustomClaims).User, needRole) {
+			v1.HandleError(ctx, http.StatusForbidden, v1.ErrUnauthorized, nil)
+			ctx.Abort()
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// CheckAccess 判断用户是否拥有某个角色的权限
+func CheckAccess(user jwt.User, needRole string) bool {
+	return roleLevel[GetUserRole(user)] >= roleLevel[needRole]
+}
+
+// GetUserRole 获取用户的有效角色，会员未过期的普通用户视为会员
+func GetUserRole(user jwt.User) string {
+	if user.UserRole == constant.DefaultRole && user.VipExpireTime != nil && user.VipExpireTime.After(time.Now()) {
+		return constant.VipRole
+	}
+	if _, ok := roleLevel[user.UserRole]; !ok {
+		return constant.NotLoginRole
+	}
+	return user.UserRole
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"app/pkg/constant"
 	"github.com/alibaba/sentinel-golang/api"
 	"github.com/alibaba/sentinel-golang/core/circuitbreaker"
 	"github.com/alibaba/sentinel-golang/core/flow"
@@ -37,6 +38,13 @@ func InitSentinel() {
 			TokenCalculateStrategy: flow.Direct,
 			ControlBehavior:        flow.Reject,
 		},
+		// 对搜索建议限流，输入时请求频繁，与搜索接口分开统计
+		{
+			Resource:               "GET:/api/question/suggest",
+			Threshold:              2000,
+			TokenCalculateStrategy: flow.Direct,
+			ControlBehavior:        flow.Reject,
+		},
 		// 防止暴力破解
 		// 对登录限流
 		{
@@ -87,6 +95,24 @@ func InitSentinel() {
 			StatIntervalMs:   1000,  // 统计时间窗口
 			RetryTimeoutMs:   60000, // 熔断持续时间
 		},
+		// 对搜索建议熔断
+		{
+			Resource:         "GET:/api/question/suggest",
+			Strategy:         circuitbreaker.ErrorRatio,
+			Threshold:        0.2,   // 异常比例阈值
+			MinRequestAmount: 10,    // 最小请求数
+			StatIntervalMs:   1000,  // 统计时间窗口
+			RetryTimeoutMs:   60000, // 熔断持续时间
+		},
+		// ES 搜索出错比例过高时熔断，熔断期间搜索直接查询数据库
+		{
+			Resource:         constant.QuestionSearchEsResource,
+			Strategy:         circuitbreaker.ErrorRatio,
+			Threshold:        0.5,   // 异常比例阈值
+			MinRequestAmount: 5,     // 最小请求数
+			StatIntervalMs:   10000, // 统计时间窗口
+			RetryTimeoutMs:   30000, // 熔断持续时间
+		},
 	}
 
 	if _, err = circuitbreaker.LoadRules(rule); err != nil {
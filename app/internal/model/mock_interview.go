@@ -6,26 +6,79 @@ import (
 
 // MockInterview 模拟面试表
 type MockInterview struct {
-	ID             uint64    `gorm:"primaryKey;autoIncrement;comment:'id'"`                                 // 主键ID
-	WorkExperience string    `gorm:"type:varchar(256);not null;comment:'工作年限'"`                             // 工作年限
-	JobPosition    string    `gorm:"type:varchar(256);not null;comment:'工作岗位'"`                             // 工作岗位
-	Difficulty     string    `gorm:"type:varchar(50);not null;comment:'面试难度'"`                              // 面试难度
-	Messages       string    `gorm:"type:mediumtext;comment:'消息列表（JSON 对象数组字段，同时包括了总结）'"`                   // 消息列表
-	Status         int       `gorm:"type:int;default:0;not null;comment:'状态（0-待开始、1-进行中、2-已结束）'"`           // 状态
-	UserID         uint64    `gorm:"type:bigint;not null;comment:'创建人（用户 id）';index:idx_userId"`            // 创建人用户ID
-	CreateTime     time.Time `gorm:"type:datetime;default:CURRENT_TIMESTAMP;comment:'创建时间'"`                // 创建时间
-	UpdateTime     time.Time `gorm:"type:datetime;default:CURRENT_TIMESTAMP;autoUpdateTime;comment:'更新时间'"` // 更新时间
-	IsDelete       int8      `gorm:"type:tinyint;default:0;not null;comment:'是否删除（逻辑删除）'"`                  // 是否删除
+	ID             uint64    `gorm:"primaryKey;autoIncrement;comment:'id'"`                                                                          // 主键ID
+	WorkExperience string    `gorm:"type:varchar(256);not null;comment:'工作年限'"`                                                                      // 工作年限
+	JobPosition    string    `gorm:"type:varchar(256);not null;comment:'工作岗位'"`                                                                      // 工作岗位
+	Difficulty     string    `gorm:"type:varchar(50);not null;comment:'面试难度'"`                                                                       // 面试难度
+	Messages       string    `gorm:"type:mediumtext;comment:'消息列表（已由 mock_interview_message 表替代，仅保留历史数据）'"`                                          // 消息列表
+	Status         int       `gorm:"type:int;default:0;not null;comment:'状态（0-待开始、1-进行中、2-已结束、3-已暂停、4-已放弃）';index:idx_status_updateTime,priority:1"` // 状态
+	UserID         uint64    `gorm:"type:bigint;not null;comment:'创建人（用户 id）';index:idx_userId"`                                                     // 创建人用户ID
+	CreateTime     time.Time `gorm:"type:datetime;default:CURRENT_TIMESTAMP;comment:'创建时间'"`                                                         // 创建时间
+	UpdateTime     time.Time `gorm:"type:datetime;default:CURRENT_TIMESTAMP;autoUpdateTime;comment:'更新时间';index:idx_status_updateTime,priority:2"`   // 更新时间
+	IsDelete       int8      `gorm:"type:tinyint;default:0;not null;comment:'是否删除（逻辑删除）'"`                                                           // 是否删除
+
+	// 题库模式字段，不指定题库时由 AI 自由出题
+	QuestionBankID *uint64 `gorm:"type:bigint;comment:'出题题库 id'"`              // 出题题库ID
+	QuestionNum    int     `gorm:"type:int;default:0;not null;comment:'题目数量'"` // 从题库抽取的题目数量
+
+	// 上下文管理字段，Messages 保存完整记录，Context 保存实际发送给 AI 的压缩上下文
+	Context       string `gorm:"type:mediumtext;comment:'压缩后的上下文（JSON 对象数组，系统 Prompt、滚动摘要和最近几轮对话）'"` // 压缩后的上下文
+	SummarizedNum int    `gorm:"type:int;default:0;not null;comment:'已合并进摘要的消息数'"`                   // 已合并进摘要的消息数
+
+	// 面试官风格字段，记录创建面试时使用的模板版本，模板后续修改不影响已创建的面试
+	Persona               string  `gorm:"type:varchar(64);default:'strict';not null;comment:'面试官风格'"`  // 面试官风格
+	PromptTemplateID      *uint64 `gorm:"type:bigint;comment:'Prompt 模板版本 id，为空表示使用内置模板'"`             // Prompt 模板版本ID
+	PromptTemplateVersion int     `gorm:"type:int;default:0;not null;comment:'Prompt 模板版本号，0 表示内置模板'"` // Prompt 模板版本号
 }
 
 func (m *MockInterview) TableName() string {
 	return "mock_interview"
 }
 
-// MockInterviewMessage 模拟面试消息记录
-type MockInterviewMessage struct {
+// MockInterviewContextMessage 压缩上下文中的消息，序列化后保存在 MockInterview.Context
+type MockInterviewContextMessage struct {
 	// 角色
 	Role string `json:"role"` // 角色
 	// 消息内容
 	Content string `json:"content"` // 消息内容
+	// 题库模式下本轮对话对应的题目 ID
+	QuestionID *uint64 `json:"questionId,omitempty"` // 题目 ID
+	// 估算的 token 数
+	Tokens int `json:"tokens,omitempty"` // token 数
+	// 是否为压缩上下文中的滚动摘要
+	Summary bool `json:"summary,omitempty"` // 是否为摘要
+}
+
+// MockInterviewQuestion 题库模式下模拟面试抽取的题目，同时用于排除用户近期练习过的题目
+type MockInterviewQuestion struct {
+	ID              uint64    `gorm:"primaryKey;autoIncrement;comment:'id'"`                                                         // 主键ID
+	MockInterviewID uint64    `gorm:"type:bigint;not null;comment:'模拟面试 id';index:idx_mockInterviewId"`                              // 模拟面试ID
+	UserID          uint64    `gorm:"type:bigint;not null;comment:'面试用户 id';index:idx_userId_createTime,priority:1"`                 // 面试用户ID
+	QuestionID      uint64    `gorm:"type:bigint;not null;comment:'题目 id'"`                                                          // 题目ID
+	Sequence        int       `gorm:"type:int;not null;comment:'提问顺序，从 1 开始'"`                                                       // 提问顺序
+	CreateTime      time.Time `gorm:"type:datetime;default:CURRENT_TIMESTAMP;comment:'创建时间';index:idx_userId_createTime,priority:2"` // 创建时间
+}
+
+func (m *MockInterviewQuestion) TableName() string {
+	return "mock_interview_question"
+}
+
+// MockInterviewMessage 模拟面试消息表，按顺序号只追加写入
+type MockInterviewMessage struct {
+	ID               uint64    `gorm:"primaryKey;autoIncrement;comment:'id'"`                                                // 主键ID
+	MockInterviewID  uint64    `gorm:"type:bigint;not null;comment:'模拟面试 id';uniqueIndex:uk_mockInterviewId_seq,priority:1"` // 模拟面试ID
+	Seq              int       `gorm:"type:int;not null;comment:'顺序号，从 1 开始';uniqueIndex:uk_mockInterviewId_seq,priority:2"` // 顺序号
+	Role             string    `gorm:"type:varchar(32);not null;comment:'角色（system/user/assistant）'"`                        // 角色
+	Content          string    `gorm:"type:mediumtext;comment:'消息内容'"`                                                       // 消息内容
+	QuestionID       *uint64   `gorm:"type:bigint;comment:'题库模式下对应的题目 id'"`                                                  // 题目ID
+	Tokens           int       `gorm:"type:int;default:0;not null;comment:'消息 token 数'"`                                     // 消息 token 数
+	PromptTokens     int       `gorm:"type:int;default:0;not null;comment:'生成该回复时的输入 token 数'"`                              // 输入 token 数
+	CompletionTokens int       `gorm:"type:int;default:0;not null;comment:'生成该回复时的输出 token 数'"`                              // 输出 token 数
+	LatencyMs        int64     `gorm:"type:bigint;default:0;not null;comment:'生成该回复的耗时（毫秒）'"`                                // 耗时
+	ModelName        string    `gorm:"type:varchar(128);comment:'生成该回复的模型'"`                                                 // 模型名称
+	CreateTime       time.Time `gorm:"type:datetime;default:CURRENT_TIMESTAMP;comment:'创建时间'"`                               // 创建时间
+}
+
+func (m *MockInterviewMessage) TableName() string {
+	return "mock_interview_message"
 }
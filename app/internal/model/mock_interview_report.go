@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+)
+
+// MockInterviewReport 模拟面试评估报告表，每场面试一份，面试结束后生成
+type MockInterviewReport struct {
+	ID                   uint64    `gorm:"primaryKey;autoIncrement;comment:'id'"`                                 // 主键ID
+	MockInterviewID      uint64    `gorm:"type:bigint;not null;uniqueIndex:uk_mockInterviewId;comment:'模拟面试 id'"` // 模拟面试ID
+	UserID               uint64    `gorm:"type:bigint;not null;index:idx_report_userId;comment:'面试用户 id'"`        // 面试用户ID
+	OverallScore         int       `gorm:"type:int;not null;comment:'总分（0-100）'"`                                 // 总分
+	TechnicalDepth       int       `gorm:"type:int;not null;comment:'技术深度得分（0-100）'"`                             // 技术深度得分
+	Communication        int       `gorm:"type:int;not null;comment:'沟通表达得分（0-100）'"`                             // 沟通表达得分
+	ProblemSolving       int       `gorm:"type:int;not null;comment:'问题解决得分（0-100）'"`                             // 问题解决得分
+	Detail               string    `gorm:"type:mediumtext;comment:'完整评估内容（JSON，含各维度评语、逐题评估、优缺点和总结）'"`             // 完整评估内容
+	RecommendedQuestions string    `gorm:"type:text;comment:'推荐练习的题目（JSON 数组）'"`                                  // 推荐练习的题目
+	ModelName            string    `gorm:"type:varchar(128);comment:'生成报告的模型'"`                                   // 生成报告的模型
+	CreateTime           time.Time `gorm:"type:datetime;default:CURRENT_TIMESTAMP;comment:'创建时间'"`                // 创建时间
+	UpdateTime           time.Time `gorm:"type:datetime;default:CURRENT_TIMESTAMP;autoUpdateTime;comment:'更新时间'"` // 更新时间
+}
+
+func (m *MockInterviewReport) TableName() string {
+	return "mock_interview_report"
+}
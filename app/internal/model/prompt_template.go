@@ -0,0 +1,23 @@
+package model
+
+import (
+	"time"
+)
+
+// PromptTemplate Prompt 模板表，同一场景、风格的每次修改保存为一个新版本，已有版本不再修改
+type PromptTemplate struct {
+	ID          uint64    `gorm:"primaryKey;autoIncrement;comment:'id'"`                                                  // 主键ID
+	Scene       string    `gorm:"type:varchar(64);not null;comment:'场景';uniqueIndex:uk_scene_persona_version,priority:1"` // 场景
+	Persona     string    `gorm:"type:varchar(64);not null;comment:'风格';uniqueIndex:uk_scene_persona_version,priority:2"` // 风格
+	Version     int       `gorm:"type:int;not null;comment:'版本号，从 1 开始';uniqueIndex:uk_scene_persona_version,priority:3"` // 版本号
+	Name        string    `gorm:"type:varchar(128);not null;comment:'风格名称'"`                                              // 风格名称
+	Description string    `gorm:"type:varchar(512);comment:'风格描述'"`                                                       // 风格描述
+	Content     string    `gorm:"type:text;not null;comment:'模板内容（Go text/template 语法）'"`                                 // 模板内容
+	EditorID    uint64    `gorm:"type:bigint;not null;comment:'修改人 id'"`                                                  // 修改人ID
+	CreateTime  time.Time `gorm:"type:datetime;default:CURRENT_TIMESTAMP;comment:'创建时间'"`                                 // 创建时间
+	IsDelete    int8      `gorm:"type:tinyint;default:0;not null;comment:'是否停用'"`                                         // 是否停用
+}
+
+func (m *PromptTemplate) TableName() string {
+	return "prompt_template"
+}
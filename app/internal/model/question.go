@@ -1,7 +1,9 @@
 package model
 
 import (
+	"app/pkg/utils"
 	"gorm.io/gorm"
+	"strings"
 	"time"
 )
 
@@ -42,15 +44,72 @@ func (m *Question) TableName() string {
 	return "question"
 }
 
+// ToEs 转换为 ES 中的题目文档
+func (m *Question) ToEs() QuestionEs {
+	es := QuestionEs{
+		Id:         int64(m.ID),
+		UserId:     int64(m.UserID),
+		EditTime:   m.EditTime,
+		CreateTime: m.CreateTime,
+		UpdateTime: m.UpdateTime,
+		IsDelete:   m.IsDelete,
+
+		ReviewStatus: m.ReviewStatus,
+	}
+	if m.Title != nil {
+		es.Title = *m.Title
+		es.TitleSuggest = *m.Title
+	}
+	if m.Content != nil {
+		es.Content = *m.Content
+	}
+	if m.Tags != nil {
+		// 标签以数组形式写入，便于按单个标签过滤
+		es.Tags, _ = utils.StringToStrings(*m.Tags)
+	}
+	if m.Answer != nil {
+		es.Answer = *m.Answer
+	}
+	return es
+}
+
 type QuestionEs struct {
-	Id         int64     `json:"id"`
-	Title      string    `json:"title"`
-	Content    string    `json:"content"`
-	Tags       string    `json:"tags"`
-	Answer     string    `json:"answer"`
-	UserId     int64     `json:"user_id"`
-	EditTime   time.Time `json:"edit_time"`
-	CreateTime time.Time `json:"create_time"`
-	UpdateTime time.Time `json:"update_time"`
-	IsDelete   int8      `json:"is_delete"`
+	Id    int64  `json:"id"`
+	Title string `json:"title"`
+	// 标题的边输入边搜索字段，用于搜索建议
+	TitleSuggest string    `json:"title_suggest"`
+	Content      string    `json:"content"`
+	Tags         []string  `json:"tags"`
+	Answer       string    `json:"answer"`
+	UserId       int64     `json:"user_id"`
+	EditTime     time.Time `json:"edit_time"`
+	CreateTime   time.Time `json:"create_time"`
+	UpdateTime   time.Time `json:"update_time"`
+	IsDelete     int8      `json:"is_delete"`
+
+	ReviewStatus int `json:"review_status"`
+
+	// 标题、标签和内容的文本向量，用于相似题目检索
+	Embedding []float32 `json:"embedding,omitempty"`
+}
+
+// EmbeddingText 计算文本向量使用的文本，标题重复一次以提高权重
+func (m *Question) EmbeddingText() string {
+	var title, content string
+	var tags []string
+	if m.Title != nil {
+		title = *m.Title
+	}
+	if m.Content != nil {
+		content = *m.Content
+	}
+	if m.Tags != nil {
+		tags, _ = utils.StringToStrings(*m.Tags)
+	}
+	return QuestionEmbeddingText(title, content, tags)
+}
+
+// QuestionEmbeddingText 拼接计算文本向量使用的文本
+func QuestionEmbeddingText(title string, content string, tags []string) string {
+	return strings.Join([]string{title, title, strings.Join(tags, " "), content}, "\n")
 }
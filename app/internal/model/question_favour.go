@@ -0,0 +1,18 @@
+package model
+
+import (
+	"time"
+)
+
+// QuestionFavour 题目收藏表
+type QuestionFavour struct {
+	ID         uint64    `gorm:"primaryKey;autoIncrement;comment:'id'"`                                                       // 主键ID
+	QuestionID uint64    `gorm:"type:bigint;not null;comment:'题目 id';uniqueIndex:question_favour_unique"`                     // 题目ID
+	UserID     uint64    `gorm:"type:bigint;not null;comment:'创建用户 id';uniqueIndex:question_favour_unique;index:idx_user_id"` // 创建用户ID
+	CreateTime time.Time `gorm:"type:datetime;default:CURRENT_TIMESTAMP;comment:'创建时间'"`                                      // 创建时间
+	UpdateTime time.Time `gorm:"type:datetime;default:CURRENT_TIMESTAMP;autoUpdateTime;comment:'更新时间'"`                       // 更新时间
+}
+
+func (m *QuestionFavour) TableName() string {
+	return "question_favour"
+}
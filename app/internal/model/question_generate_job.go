@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+)
+
+// QuestionGenerateJob AI 生成题目任务表，任务通过 RabbitMQ 异步执行
+type QuestionGenerateJob struct {
+	ID             uint64     `gorm:"primaryKey;autoIncrement;comment:'id'"`                                 // 主键ID
+	UserID         uint64     `gorm:"type:bigint;not null;comment:'创建用户 id';index:idx_userId"`               // 创建用户ID
+	Direction      string     `gorm:"type:varchar(256);not null;comment:'题目方向'"`                             // 题目方向
+	Number         int        `gorm:"type:int;not null;comment:'请求生成的题目数量'"`                                 // 请求生成的题目数量
+	QuestionBankID *uint64    `gorm:"type:bigint;comment:'生成的题目加入的题库 id'"`                                   // 目标题库ID
+	Status         int        `gorm:"type:int;default:0;not null;comment:'状态（0-排队中、1-生成中、2-已完成、3-失败）'"`      // 状态
+	Titles         *string    `gorm:"type:text;comment:'AI 返回的题目标题（JSON 数组）'"`                               // AI 返回的题目标题（JSON数组）
+	Total          int        `gorm:"type:int;default:0;not null;comment:'AI 返回的有效题目数'"`                     // 有效题目数
+	ProcessedNum   int        `gorm:"type:int;default:0;not null;comment:'已处理的题目数'"`                         // 已处理的题目数
+	CreatedNum     int        `gorm:"type:int;default:0;not null;comment:'新建的题目数'"`                          // 新建的题目数
+	SkippedNum     int        `gorm:"type:int;default:0;not null;comment:'因重复跳过的题目数'"`                       // 跳过的题目数
+	ErrorMessage   string     `gorm:"type:varchar(512);comment:'失败原因'"`                                      // 失败原因
+	CreateTime     time.Time  `gorm:"type:datetime;default:CURRENT_TIMESTAMP;comment:'创建时间'"`                // 创建时间
+	UpdateTime     time.Time  `gorm:"type:datetime;default:CURRENT_TIMESTAMP;autoUpdateTime;comment:'更新时间'"` // 更新时间
+	FinishTime     *time.Time `gorm:"type:datetime;comment:'结束时间'"`                                          // 结束时间
+}
+
+func (m *QuestionGenerateJob) TableName() string {
+	return "question_generate_job"
+}
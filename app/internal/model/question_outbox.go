@@ -0,0 +1,22 @@
+package model
+
+import (
+	"time"
+)
+
+// QuestionOutbox 题目变更事件表，与题目修改在同一事务中写入，由中继任务投递到消息队列同步到 ES
+type QuestionOutbox struct {
+	ID            uint64    `gorm:"primaryKey;autoIncrement;comment:'id，同时作为 ES 文档的版本号'"`                                 // 主键ID
+	QuestionID    uint64    `gorm:"type:bigint;not null;comment:'题目 id';index:idx_questionId"`                            // 题目ID
+	EventType     string    `gorm:"type:varchar(16);not null;comment:'事件类型：create/update/delete'"`                        // 事件类型
+	Status        int       `gorm:"type:int;default:0;not null;comment:'状态：0-待投递, 1-已投递, 2-投递失败';index:idx_status_retry"` // 投递状态
+	RetryCount    int       `gorm:"type:int;default:0;not null;comment:'投递失败次数'"`                                         // 投递失败次数
+	NextRetryTime time.Time `gorm:"type:datetime;default:CURRENT_TIMESTAMP;comment:'下次投递时间';index:idx_status_retry"`      // 下次投递时间
+	LastError     *string   `gorm:"type:varchar(512);comment:'最近一次投递失败原因'"`                                               // 最近一次投递失败原因
+	CreateTime    time.Time `gorm:"type:datetime;default:CURRENT_TIMESTAMP;comment:'创建时间'"`                               // 创建时间
+	UpdateTime    time.Time `gorm:"type:datetime;default:CURRENT_TIMESTAMP;autoUpdateTime;comment:'更新时间'"`                // 更新时间
+}
+
+func (m *QuestionOutbox) TableName() string {
+	return "question_outbox"
+}
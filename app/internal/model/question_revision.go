@@ -0,0 +1,24 @@
+package model
+
+import (
+	"time"
+)
+
+// QuestionRevision 题目修订记录表，每次修改题目都会保存一份完整快照
+type QuestionRevision struct {
+	ID              uint64    `gorm:"primaryKey;autoIncrement;comment:'id'"`                                         // 主键ID
+	QuestionID      uint64    `gorm:"type:bigint;not null;comment:'题目 id';uniqueIndex:question_revision_unique"`     // 题目ID
+	Version         int       `gorm:"type:int;not null;comment:'版本号，从 1 开始递增';uniqueIndex:question_revision_unique"` // 版本号
+	Title           *string   `gorm:"type:varchar(256);comment:'标题'"`                                                // 标题
+	Content         *string   `gorm:"type:text;comment:'内容'"`                                                        // 内容
+	Tags            *string   `gorm:"type:varchar(1024);comment:'标签列表（json 数组）'"`                                    // 标签列表（JSON数组）
+	Answer          *string   `gorm:"type:text;comment:'推荐答案'"`                                                      // 推荐答案
+	EditorID        uint64    `gorm:"type:bigint;not null;comment:'修改人 id'"`                                         // 修改人ID
+	Action          string    `gorm:"type:varchar(32);not null;comment:'操作类型：create/update/rollback'"`               // 操作类型
+	RollbackVersion *int      `gorm:"type:int;comment:'回滚到的版本号'"`                                                    // 回滚到的版本号
+	CreateTime      time.Time `gorm:"type:datetime;default:CURRENT_TIMESTAMP;comment:'创建时间'"`                        // 创建时间
+}
+
+func (m *QuestionRevision) TableName() string {
+	return "question_revision"
+}
@@ -0,0 +1,36 @@
+package model
+
+import (
+	"time"
+)
+
+// QuestionSearchLog 题目搜索日志，由缓冲队列异步批量写入
+type QuestionSearchLog struct {
+	ID          uint64    `gorm:"primaryKey;autoIncrement;comment:'id'"`                                       // 主键ID
+	SearchID    string    `gorm:"type:varchar(32);not null;comment:'搜索 id，点击结果时回传';uniqueIndex:uk_searchId"`   // 搜索ID
+	Query       string    `gorm:"type:varchar(256);not null;default:'';comment:'规范化后的搜索词'"`                    // 搜索词
+	Filters     *string   `gorm:"type:varchar(1024);comment:'过滤条件（json）'"`                                     // 过滤条件
+	ResultCount int       `gorm:"type:int;default:0;not null;comment:'结果数'"`                                   // 结果数
+	LatencyMs   int       `gorm:"type:int;default:0;not null;comment:'耗时（毫秒）'"`                                // 耗时
+	Backend     string    `gorm:"type:varchar(16);not null;default:'';comment:'搜索后端：elasticsearch/database'"`  // 搜索后端
+	UserID      *uint64   `gorm:"type:bigint;comment:'用户 id，未登录为空'"`                                           // 用户ID
+	CreateTime  time.Time `gorm:"type:datetime;default:CURRENT_TIMESTAMP;comment:'搜索时间';index:idx_createTime"` // 搜索时间
+}
+
+func (m *QuestionSearchLog) TableName() string {
+	return "question_search_log"
+}
+
+// QuestionSearchClick 搜索结果点击日志，通过搜索 ID 关联搜索日志
+type QuestionSearchClick struct {
+	ID         uint64    `gorm:"primaryKey;autoIncrement;comment:'id'"`                                             // 主键ID
+	SearchID   string    `gorm:"type:varchar(32);not null;comment:'搜索 id';index:idx_searchId"`                      // 搜索ID
+	QuestionID uint64    `gorm:"type:bigint;not null;comment:'题目 id'"`                                              // 题目ID
+	Position   int       `gorm:"type:int;default:0;not null;comment:'题目在结果中的位置，从 1 开始'"`                            // 结果位置
+	UserID     *uint64   `gorm:"type:bigint;comment:'用户 id，未登录为空'"`                                                 // 用户ID
+	CreateTime time.Time `gorm:"type:datetime;default:CURRENT_TIMESTAMP;comment:'点击时间';index:idx_click_createTime"` // 点击时间
+}
+
+func (m *QuestionSearchClick) TableName() string {
+	return "question_search_click"
+}
@@ -0,0 +1,20 @@
+package model
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+// QuestionSynonym 搜索同义词表，每行一条 Solr 格式规则，修改后推送到 ES 同义词集合
+type QuestionSynonym struct {
+	ID         uint64         `gorm:"primaryKey;autoIncrement;comment:'id'"`                                 // 主键ID
+	Rule       string         `gorm:"type:varchar(512);not null;comment:'同义词规则，如 k8s, kubernetes'"`          // 同义词规则
+	UserID     uint64         `gorm:"type:bigint;not null;comment:'创建用户id'"`                                 // 创建用户ID
+	CreateTime time.Time      `gorm:"type:datetime;default:CURRENT_TIMESTAMP;comment:'创建时间'"`                // 创建时间
+	UpdateTime time.Time      `gorm:"type:datetime;default:CURRENT_TIMESTAMP;autoUpdateTime;comment:'更新时间'"` // 更新时间
+	DeletedAt  gorm.DeletedAt `gorm:"index;comment:'删除时间'"`
+}
+
+func (m *QuestionSynonym) TableName() string {
+	return "question_synonym"
+}
@@ -0,0 +1,17 @@
+package model
+
+import (
+	"time"
+)
+
+// QuestionThumb 题目点赞表
+type QuestionThumb struct {
+	ID         uint64    `gorm:"primaryKey;autoIncrement;comment:'id'"`                                    // 主键ID
+	QuestionID uint64    `gorm:"type:bigint;not null;comment:'题目 id';uniqueIndex:question_thumb_unique"`   // 题目ID
+	UserID     uint64    `gorm:"type:bigint;not null;comment:'创建用户 id';uniqueIndex:question_thumb_unique"` // 创建用户ID
+	CreateTime time.Time `gorm:"type:datetime;default:CURRENT_TIMESTAMP;comment:'创建时间'"`                   // 创建时间
+}
+
+func (m *QuestionThumb) TableName() string {
+	return "question_thumb"
+}
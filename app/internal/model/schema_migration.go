@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// SchemaMigration 已执行的一次性数据迁移记录，迁移名称唯一，存在记录时不再重复执行
+type SchemaMigration struct {
+	Name       string    `gorm:"primaryKey;type:varchar(128);comment:'迁移名称'"`            // 迁移名称
+	CreateTime time.Time `gorm:"type:datetime;default:CURRENT_TIMESTAMP;comment:'执行时间'"` // 执行时间
+}
+
+func (m *SchemaMigration) TableName() string {
+	return "schema_migration"
+}
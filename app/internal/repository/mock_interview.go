@@ -1,15 +1,19 @@
 package repository
 
 import (
-	v1 "app/api/v1"
 	"app/internal/model"
 	"context"
+	"time"
 )
 
 type MockInterviewRepository interface {
 	GetMockInterview(ctx context.Context, id uint64) (*model.MockInterview, error)
-	AddMockInterview(ctx context.Context, interview v1.MockInterview) (uint64, error)
+	AddMockInterview(ctx context.Context, interview *model.MockInterview) (uint64, error)
 	UpdateMockInterview(ctx context.Context, interview *model.MockInterview) error
+	// 仅当面试仍处于 fromStatus 时更新状态和压缩上下文，返回是否更新成功
+	UpdateMockInterviewByStatus(ctx context.Context, interview *model.MockInterview, fromStatus int) (bool, error)
+	// 将处于 status 状态且在 before 之前未更新的面试置为 toStatus，返回更新的数量
+	UpdateIdleMockInterviewStatus(ctx context.Context, status int, before time.Time, toStatus int) (int64, error)
 	ListMockInterview(ctx context.Context, userId uint64) ([]model.MockInterview, error)
 }
 
@@ -40,21 +44,39 @@ func (r *mockInterviewRepository) UpdateMockInterview(ctx context.Context, inter
 	return nil
 }
 
-// AddMockInterview 添加面试
-func (r *mockInterviewRepository) AddMockInterview(ctx context.Context, interview v1.MockInterview) (uint64, error) {
-	mockInterview := &model.MockInterview{
-		CreateTime:     interview.CreateTime,
-		Difficulty:     interview.Difficulty,
-		JobPosition:    interview.JobPosition,
-		UpdateTime:     interview.UpdateTime,
-		UserID:         interview.UserID,
-		WorkExperience: interview.WorkExperience,
-		Status:         interview.Status, // 待开始模拟面试
+// UpdateMockInterviewByStatus 以当前状态为条件更新面试，避免并发请求或超时任务覆盖彼此的状态
+func (r *mockInterviewRepository) UpdateMockInterviewByStatus(ctx context.Context, interview *model.MockInterview, fromStatus int) (bool, error) {
+	result := r.DB(ctx).Model(&model.MockInterview{}).
+		Where("id = ? AND status = ?", interview.ID, fromStatus).
+		Updates(map[string]interface{}{
+			"status":         interview.Status,
+			"context":        interview.Context,
+			"summarized_num": interview.SummarizedNum,
+			"update_time":    time.Now(),
+		})
+	if result.Error != nil {
+		return false, result.Error
 	}
-	if err := r.DB(ctx).Create(mockInterview).Error; err != nil {
+	return result.RowsAffected > 0, nil
+}
+
+// UpdateIdleMockInterviewStatus 批量更新长时间未活动的面试状态
+func (r *mockInterviewRepository) UpdateIdleMockInterviewStatus(ctx context.Context, status int, before time.Time, toStatus int) (int64, error) {
+	result := r.DB(ctx).Model(&model.MockInterview{}).
+		Where("status = ? AND update_time < ? AND is_delete = 0", status, before).
+		Updates(map[string]interface{}{
+			"status":      toStatus,
+			"update_time": time.Now(),
+		})
+	return result.RowsAffected, result.Error
+}
+
+// AddMockInterview 添加面试
+func (r *mockInterviewRepository) AddMockInterview(ctx context.Context, interview *model.MockInterview) (uint64, error) {
+	if err := r.DB(ctx).Create(interview).Error; err != nil {
 		return 0, err
 	}
-	return mockInterview.ID, nil
+	return interview.ID, nil
 }
 
 func (r *mockInterviewRepository) GetMockInterview(ctx context.Context, id uint64) (*model.MockInterview, error) {
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"app/internal/model"
+	"app/pkg/llm"
+	"context"
+)
+
+// MockInterviewMessageRepository 定义了模拟面试消息仓库接口
+type MockInterviewMessageRepository interface {
+	// 按顺序追加消息，顺序号接在已有消息之后
+	Append(ctx context.Context, mockInterviewId uint64, messages []model.MockInterviewMessage) error
+	// 获取面试的全部消息，按顺序号升序
+	ListByMockInterviewId(ctx context.Context, mockInterviewId uint64) ([]model.MockInterviewMessage, error)
+	// 分页获取面试的对话消息，不含系统 Prompt，按顺序号升序
+	ListDialogueByPage(ctx context.Context, mockInterviewId uint64, current int, pageSize int) ([]model.MockInterviewMessage, int, error)
+}
+
+// NewMockInterviewMessageRepository 创建模拟面试消息仓库
+func NewMockInterviewMessageRepository(
+	repository *Repository,
+) MockInterviewMessageRepository {
+	return &mockInterviewMessageRepository{
+		Repository: repository,
+	}
+}
+
+type mockInterviewMessageRepository struct {
+	*Repository
+}
+
+// Append 追加消息，并发追加时 (mock_interview_id, seq) 唯一索引冲突会返回错误，不会覆盖已有消息
+func (r *mockInterviewMessageRepository) Append(ctx context.Context, mockInterviewId uint64, messages []model.MockInterviewMessage) error {
+	if len(messages) == 0 {
+		return nil
+	}
+	var seq int
+	if err := r.DB(ctx).Model(&model.MockInterviewMessage{}).
+		Where("mock_interview_id = ?", mockInterviewId).
+		Select("COALESCE(MAX(seq), 0)").
+		Scan(&seq).Error; err != nil {
+		return err
+	}
+	for i := range messages {
+		messages[i].MockInterviewID = mockInterviewId
+		messages[i].Seq = seq + i + 1
+	}
+	return r.DB(ctx).Create(&messages).Error
+}
+
+// ListByMockInterviewId 获取面试的全部消息
+func (r *mockInterviewMessageRepository) ListByMockInterviewId(ctx context.Context, mockInterviewId uint64) ([]model.MockInterviewMessage, error) {
+	var messages []model.MockInterviewMessage
+	if err := r.DB(ctx).Where("mock_interview_id = ?", mockInterviewId).Order("seq ASC").Find(&messages).Error; err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// ListDialogueByPage 分页获取面试官和候选人的对话
+func (r *mockInterviewMessageRepository) ListDialogueByPage(ctx context.Context, mockInterviewId uint64, current int, pageSize int) ([]model.MockInterviewMessage, int, error) {
+	var messages []model.MockInterviewMessage
+	var total int64
+	db := r.DB(ctx).Model(&model.MockInterviewMessage{}).Where("mock_interview_id = ? AND role <> ?", mockInterviewId, llm.RoleSystem)
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (current - 1) * pageSize
+	if err := db.Order("seq ASC").Offset(offset).Limit(pageSize).Find(&messages).Error; err != nil {
+		return nil, 0, err
+	}
+	return messages, int(total), nil
+}
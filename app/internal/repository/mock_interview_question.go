@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"app/internal/model"
+	"app/pkg/constant"
+	"context"
+	"time"
+)
+
+// MockInterviewQuestionCandidate 可抽取的题库题目
+type MockInterviewQuestionCandidate struct {
+	QuestionID uint64
+	Priority   int
+}
+
+// MockInterviewQuestionDetail 模拟面试抽取的题目及题目内容
+type MockInterviewQuestionDetail struct {
+	model.MockInterviewQuestion
+	Title   *string
+	Content *string
+	Answer  *string
+}
+
+// MockInterviewQuestionRepository 定义了模拟面试抽题仓库接口
+type MockInterviewQuestionRepository interface {
+	// 获取题库中审核通过且未删除的题目
+	ListCandidate(ctx context.Context, questionBankId uint64) ([]MockInterviewQuestionCandidate, error)
+	// 获取用户在指定时间之后的模拟面试中练习过的题目ID
+	ListPracticedQuestionId(ctx context.Context, userId uint64, since time.Time) ([]uint64, error)
+	// 批量保存抽取的题目
+	BatchCreate(ctx context.Context, questions []model.MockInterviewQuestion) error
+	// 按提问顺序获取面试抽取的题目
+	ListByMockInterviewId(ctx context.Context, mockInterviewId uint64) ([]MockInterviewQuestionDetail, error)
+}
+
+// NewMockInterviewQuestionRepository 创建模拟面试抽题仓库
+func NewMockInterviewQuestionRepository(
+	repository *Repository,
+) MockInterviewQuestionRepository {
+	return &mockInterviewQuestionRepository{
+		Repository: repository,
+	}
+}
+
+type mockInterviewQuestionRepository struct {
+	*Repository
+}
+
+// ListCandidate 获取题库中审核通过且未删除的题目及优先级
+func (r *mockInterviewQuestionRepository) ListCandidate(ctx context.Context, questionBankId uint64) ([]MockInterviewQuestionCandidate, error) {
+	var candidates []MockInterviewQuestionCandidate
+	err := r.DB(ctx).Table("question_bank_question AS qbq").
+		Select("q.id AS question_id, q.priority").
+		Joins("JOIN question AS q ON q.id = qbq.question_id").
+		Where("qbq.question_bank_id = ? AND q.is_delete = 0 AND q.deleted_at IS NULL AND q.review_status = ?",
+			questionBankId, constant.ReviewStatusPass).
+		Scan(&candidates).Error
+	return candidates, err
+}
+
+// ListPracticedQuestionId 获取用户在指定时间之后练习过的题目ID
+func (r *mockInterviewQuestionRepository) ListPracticedQuestionId(ctx context.Context, userId uint64, since time.Time) ([]uint64, error) {
+	var ids []uint64
+	err := r.DB(ctx).Model(&model.MockInterviewQuestion{}).
+		Distinct("question_id").
+		Where("user_id = ? AND create_time >= ?", userId, since).
+		Pluck("question_id", &ids).Error
+	return ids, err
+}
+
+// BatchCreate 批量保存抽取的题目
+func (r *mockInterviewQuestionRepository) BatchCreate(ctx context.Context, questions []model.MockInterviewQuestion) error {
+	if len(questions) == 0 {
+		return nil
+	}
+	return r.DB(ctx).Create(&questions).Error
+}
+
+// ListByMockInterviewId 按提问顺序获取面试抽取的题目，包含题目标题、内容和参考答案
+func (r *mockInterviewQuestionRepository) ListByMockInterviewId(ctx context.Context, mockInterviewId uint64) ([]MockInterviewQuestionDetail, error) {
+	var questions []MockInterviewQuestionDetail
+	err := r.DB(ctx).Table("mock_interview_question AS miq").
+		Select("miq.*, q.title, q.content, q.answer").
+		Joins("LEFT JOIN question AS q ON q.id = miq.question_id").
+		Where("miq.mock_interview_id = ?", mockInterviewId).
+		Order("miq.sequence ASC").
+		Scan(&questions).Error
+	return questions, err
+}
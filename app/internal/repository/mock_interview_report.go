@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"app/internal/model"
+	"app/pkg/constant"
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// MockInterviewReportRepository 定义了模拟面试评估报告仓库接口
+type MockInterviewReportRepository interface {
+	// 保存评估报告，同一场面试重复生成时覆盖
+	Save(ctx context.Context, report *model.MockInterviewReport) error
+	// 根据面试ID获取评估报告，不存在时返回 nil
+	GetByMockInterviewId(ctx context.Context, mockInterviewId uint64) (*model.MockInterviewReport, error)
+	// 设置生成中标记，已有标记时返回 false
+	MarkGenerating(ctx context.Context, mockInterviewId uint64, ttl time.Duration) (bool, error)
+	// 清除生成中标记
+	ClearGenerating(ctx context.Context, mockInterviewId uint64) error
+}
+
+// NewMockInterviewReportRepository 创建模拟面试评估报告仓库
+func NewMockInterviewReportRepository(
+	repository *Repository,
+) MockInterviewReportRepository {
+	return &mockInterviewReportRepository{
+		Repository: repository,
+	}
+}
+
+type mockInterviewReportRepository struct {
+	*Repository
+}
+
+// Save 保存评估报告，按面试ID唯一
+func (r *mockInterviewReportRepository) Save(ctx context.Context, report *model.MockInterviewReport) error {
+	return r.DB(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "mock_interview_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"overall_score", "technical_depth", "communication", "problem_solving",
+			"detail", "recommended_questions", "model_name", "update_time",
+		}),
+	}).Create(report).Error
+}
+
+// GetByMockInterviewId 根据面试ID获取评估报告
+func (r *mockInterviewReportRepository) GetByMockInterviewId(ctx context.Context, mockInterviewId uint64) (*model.MockInterviewReport, error) {
+	var report model.MockInterviewReport
+	if err := r.DB(ctx).Where("mock_interview_id = ?", mockInterviewId).First(&report).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &report, nil
+}
+
+// MarkGenerating 使用 SETNX 设置生成中标记，过期时间兜底进程异常退出时标记无法清除
+func (r *mockInterviewReportRepository) MarkGenerating(ctx context.Context, mockInterviewId uint64, ttl time.Duration) (bool, error) {
+	return r.rdb.SetNX(ctx, constant.GetMockInterviewReportGeneratingRedisKey(mockInterviewId), 1, ttl).Result()
+}
+
+// ClearGenerating 清除生成中标记
+func (r *mockInterviewReportRepository) ClearGenerating(ctx context.Context, mockInterviewId uint64) error {
+	return r.rdb.Del(ctx, constant.GetMockInterviewReportGeneratingRedisKey(mockInterviewId)).Err()
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"app/internal/model"
+	"context"
+	"errors"
+	"gorm.io/gorm"
+)
+
+// PromptTemplateRepository 定义了 Prompt 模板仓库接口
+type PromptTemplateRepository interface {
+	// 创建模板版本
+	Create(ctx context.Context, template *model.PromptTemplate) error
+	// 根据ID获取模板版本，已停用的也可以获取，用于复现历史记录
+	GetByID(ctx context.Context, id uint64) (*model.PromptTemplate, error)
+	// 获取场景、风格下最新的启用版本，不存在时返回 nil
+	GetLatest(ctx context.Context, scene string, persona string) (*model.PromptTemplate, error)
+	// 获取场景、风格下的最大版本号（包括已停用的版本），没有版本时返回 0
+	GetLatestVersion(ctx context.Context, scene string, persona string) (int, error)
+	// 获取场景下每种风格最新的启用版本
+	ListLatest(ctx context.Context, scene string) ([]model.PromptTemplate, error)
+	// 分页获取模板版本，scene、persona 为空时不过滤
+	ListByPage(ctx context.Context, scene string, persona string, current int, pageSize int) ([]model.PromptTemplate, int, error)
+	// 停用场景、风格下的全部版本，返回停用的版本数
+	Disable(ctx context.Context, scene string, persona string) (int64, error)
+}
+
+// NewPromptTemplateRepository 创建 Prompt 模板仓库
+func NewPromptTemplateRepository(
+	repository *Repository,
+) PromptTemplateRepository {
+	return &promptTemplateRepository{
+		Repository: repository,
+	}
+}
+
+type promptTemplateRepository struct {
+	*Repository
+}
+
+// Create 创建模板版本
+func (r *promptTemplateRepository) Create(ctx context.Context, template *model.PromptTemplate) error {
+	return r.DB(ctx).Create(template).Error
+}
+
+// GetByID 根据ID获取模板版本
+func (r *promptTemplateRepository) GetByID(ctx context.Context, id uint64) (*model.PromptTemplate, error) {
+	var template model.PromptTemplate
+	if err := r.DB(ctx).Where("id = ?", id).First(&template).Error; err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// GetLatest 获取最新的启用版本
+func (r *promptTemplateRepository) GetLatest(ctx context.Context, scene string, persona string) (*model.PromptTemplate, error) {
+	var template model.PromptTemplate
+	if err := r.DB(ctx).Where("scene = ? AND persona = ? AND is_delete = 0", scene, persona).
+		Order("version DESC").First(&template).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &template, nil
+}
+
+// GetLatestVersion 获取最大版本号
+func (r *promptTemplateRepository) GetLatestVersion(ctx context.Context, scene string, persona string) (int, error) {
+	var version int
+	if err := r.DB(ctx).Model(&model.PromptTemplate{}).
+		Where("scene = ? AND persona = ?", scene, persona).
+		Select("COALESCE(MAX(version), 0)").
+		Scan(&version).Error; err != nil {
+		return 0, err
+	}
+	return version, nil
+}
+
+// ListLatest 获取每种风格最新的启用版本，按风格排序
+func (r *promptTemplateRepository) ListLatest(ctx context.Context, scene string) ([]model.PromptTemplate, error) {
+	var templates []model.PromptTemplate
+	latest := r.DB(ctx).Model(&model.PromptTemplate{}).
+		Select("persona, MAX(version) AS version").
+		Where("scene = ? AND is_delete = 0", scene).
+		Group("persona")
+	if err := r.DB(ctx).Table("prompt_template AS t").
+		Select("t.*").
+		Joins("JOIN (?) AS latest ON latest.persona = t.persona AND latest.version = t.version", latest).
+		Where("t.scene = ?", scene).
+		Order("t.persona ASC").
+		Find(&templates).Error; err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// ListByPage 分页获取模板版本，按场景、风格排序，同一风格的新版本在前
+func (r *promptTemplateRepository) ListByPage(ctx context.Context, scene string, persona string, current int, pageSize int) ([]model.PromptTemplate, int, error) {
+	var templates []model.PromptTemplate
+	var total int64
+	db := r.DB(ctx).Model(&model.PromptTemplate{})
+	if scene != "" {
+		db = db.Where("scene = ?", scene)
+	}
+	if persona != "" {
+		db = db.Where("persona = ?", persona)
+	}
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (current - 1) * pageSize
+	if err := db.Order("scene ASC, persona ASC, version DESC").Offset(offset).Limit(pageSize).Find(&templates).Error; err != nil {
+		return nil, 0, err
+	}
+	return templates, int(total), nil
+}
+
+// Disable 停用全部版本
+func (r *promptTemplateRepository) Disable(ctx context.Context, scene string, persona string) (int64, error) {
+	result := r.DB(ctx).Model(&model.PromptTemplate{}).
+		Where("scene = ? AND persona = ? AND is_delete = 0", scene, persona).
+		Update("is_delete", 1)
+	return result.RowsAffected, result.Error
+}
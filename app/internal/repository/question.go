@@ -3,6 +3,7 @@ package repository
 import (
 	v1 "app/api/v1"
 	"app/internal/model"
+	"app/pkg/constant"
 	"app/pkg/utils"
 	"bytes"
 	"context"
@@ -10,7 +11,10 @@ import (
 	"errors"
 	"fmt"
 	"github.com/elastic/go-elasticsearch/v8/esapi"
+	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
+	"net/http"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -20,8 +24,14 @@ import (
 type QuestionRepository interface {
 	// 向ES中添加数据
 	AddDataToEs(ctx context.Context, data []model.QuestionEs) error
-	// 获取所有问题
-	GetAllQuestion(ctx context.Context, time time.Time) ([]model.Question, error)
+	// 按版本号写入ES文档，旧版本会被忽略
+	IndexEsQuestion(ctx context.Context, data model.QuestionEs, version uint64) error
+	// 按版本号删除ES文档，旧版本会被忽略
+	DeleteEsQuestion(ctx context.Context, id uint64, version uint64) error
+	// 根据ID获取问题，包括已删除的问题，不存在时返回 nil
+	GetByIDUnscoped(ctx context.Context, id uint64) (*model.Question, error)
+	// 按ID顺序分批获取问题，用于重建索引
+	ListQuestionAfterId(ctx context.Context, afterId uint64, limit int) ([]model.Question, error)
 	// 根据请求获取问题
 	GetQuestion(ctx context.Context, req *v1.QuestionRequest) ([]model.Question, int, error)
 	// 获取问题总数
@@ -38,12 +48,29 @@ type QuestionRepository interface {
 	Update(ctx context.Context, question *model.Question) error
 	// 根据题库ID获取问题
 	GetQuestionByBankId(ctx context.Context, bankId uint64) ([]model.Question, int64, error)
+	// 获取标题补全和热门标签，结果短暂缓存
+	SuggestQuestion(ctx context.Context, prefix string, size int) (*v1.QuestionSuggestVO, error)
+	// 按文本向量检索相似题目
+	SearchSimilarQuestion(ctx context.Context, vector []float32, size int, excludeId uint64, onlyPass bool) ([]v1.SimilarQuestionVO, error)
 	// 根据请求获取ES中的问题
-	GetEsQuestion(ctx context.Context, req *v1.QuestionRequest) ([]v1.Question, int, error)
+	GetEsQuestion(ctx context.Context, req *v1.QuestionRequest) ([]v1.Question, int, []v1.TagFacet, error)
 	// 批量删除问题
-	DeleteBatchQuestion(ctx context.Context, questions []string) error
+	DeleteBatchQuestion(ctx context.Context, ids []uint64) error
+	// 批量更新问题审核状态
+	UpdateReviewStatus(ctx context.Context, ids []uint64, reviewStatus int, reviewMessage *string, reviewerId uint64) error
+	// 删除问题详情缓存
+	DeleteQuestionCache(ctx context.Context, ids ...uint64) error
+	// 将浏览量增量刷回数据库
+	FlushViewNum(ctx context.Context) error
+	// 获取统计窗口内浏览次数最多的问题ID
+	GetHotQuestionIds(ctx context.Context, limit int) ([]uint64, error)
+	// 根据ID列表获取审核通过的问题
+	GetPassQuestionByIds(ctx context.Context, ids []uint64) ([]model.Question, error)
 }
 
+// hotQuestionRankUnionKey 滑动窗口内合并后的热门题目排行，每分钟重新计算一次
+const hotQuestionRankUnionKey = "question:hot_rank:window:%d"
+
 // NewQuestionRepository 创建一个问题仓库实例
 func NewQuestionRepository(
 	repository *Repository,
@@ -58,50 +85,134 @@ type questionRepository struct {
 	*Repository
 }
 
-// DeleteBatchQuestion 批量删除问题
-func (r *questionRepository) DeleteBatchQuestion(ctx context.Context, questions []string) error {
-	tx := r.DB(ctx).Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
+// UpdateReviewStatus 批量更新问题审核状态
+func (r *questionRepository) UpdateReviewStatus(ctx context.Context, ids []uint64, reviewStatus int, reviewMessage *string, reviewerId uint64) error {
+	if err := r.DB(ctx).Model(&model.Question{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+		"review_status":  reviewStatus,
+		"review_message": reviewMessage,
+		"reviewer_id":    reviewerId,
+		"review_time":    time.Now(),
+	}).Error; err != nil {
+		return err
+	}
+	// 审核状态变化后缓存中的题目详情可能已不可公开访问
+	return r.DeleteQuestionCache(ctx, ids...)
+}
+
+// FlushViewNum 将浏览量增量刷回数据库
+func (r *questionRepository) FlushViewNum(ctx context.Context) error {
+	return flushCounter(ctx, r.Repository, constant.QuestionViewNumRedisKey, &model.Question{}, "view_num")
+}
+
+// GetHotQuestionIds 获取统计窗口内浏览次数最多的问题ID，按浏览次数倒序
+func (r *questionRepository) GetHotQuestionIds(ctx context.Context, limit int) ([]uint64, error) {
+	bucket := constant.GetViewBucket(time.Now())
+	unionKey := fmt.Sprintf(hotQuestionRankUnionKey, bucket)
+	exists, err := r.rdb.Exists(ctx, unionKey).Result()
+	if err != nil {
+		return nil, err
+	}
+	if exists == 0 {
+		keys := make([]string, 0, constant.HotQuestionWindowMinutes)
+		for i := int64(0); i < constant.HotQuestionWindowMinutes; i++ {
+			keys = append(keys, constant.GetHotQuestionRankRedisKey(bucket-i))
+		}
+		pipe := r.rdb.TxPipeline()
+		pipe.ZUnionStore(ctx, unionKey, &redis.ZStore{Keys: keys, Aggregate: "SUM"})
+		pipe.Expire(ctx, unionKey, time.Minute)
+		if _, err = pipe.Exec(ctx); err != nil {
+			return nil, err
+		}
+	}
+	members, err := r.rdb.ZRevRange(ctx, unionKey, 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uint64, 0, len(members))
+	for _, member := range members {
+		id, err := strconv.ParseUint(member, 10, 64)
+		if err != nil {
+			continue
 		}
-	}()
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
 
+// GetPassQuestionByIds 根据ID列表获取审核通过的问题
+func (r *questionRepository) GetPassQuestionByIds(ctx context.Context, ids []uint64) ([]model.Question, error) {
+	var questions []model.Question
+	if len(ids) == 0 {
+		return questions, nil
+	}
+	if err := r.DB(ctx).Where("id IN ? AND review_status = ?", ids, constant.ReviewStatusPass).Find(&questions).Error; err != nil {
+		return nil, err
+	}
+	return questions, nil
+}
+
+// DeleteQuestionCache 删除问题详情缓存
+func (r *questionRepository) DeleteQuestionCache(ctx context.Context, ids ...uint64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, fmt.Sprintf("question:cache:%d", id))
+	}
+	return r.rdb.Del(ctx, keys...).Err()
+}
+
+// DeleteBatchQuestion 批量删除问题，需要在事务中调用
+func (r *questionRepository) DeleteBatchQuestion(ctx context.Context, ids []uint64) error {
 	// 每次删除1000条
-	for i := 0; i < len(questions); i += 1000 {
-		for j := i; j < i+1000 && j < len(questions); j++ {
-			// 逻辑删除
-			if err := tx.Where("id = ?", questions[j]).Update("is_delete", "1").Error; err != nil {
-				tx.Rollback()
-				return err
-			}
-			if err := tx.Where("id = ?", questions[j]).Delete(&model.Question{}).Error; err != nil {
-				tx.Rollback()
-				return err
-			}
-			// 删除与题库的关联
-			if err := tx.Where("question_id = ?", questions[j]).Delete(&model.QuestionBankQuestion{}).Error; err != nil {
-				tx.Rollback()
-				return err
-			}
+	for i := 0; i < len(ids); i += 1000 {
+		batch := ids[i:min(i+1000, len(ids))]
+		// 逻辑删除
+		if err := r.DB(ctx).Model(&model.Question{}).Where("id IN ?", batch).
+			UpdateColumn("is_delete", 1).Error; err != nil {
+			return err
+		}
+		if err := r.DB(ctx).Where("id IN ?", batch).Delete(&model.Question{}).Error; err != nil {
+			return err
+		}
+		// 删除与题库的关联
+		if err := r.DB(ctx).Where("question_id IN ?", batch).Delete(&model.QuestionBankQuestion{}).Error; err != nil {
+			return err
 		}
 	}
-
-	// 提交事务
-	tx.Commit()
 	return nil
 }
 
-// GetEsQuestion 根据请求获取ES中的问题
-func (r *questionRepository) GetEsQuestion(ctx context.Context, req *v1.QuestionRequest) ([]v1.Question, int, error) {
+// esSearchResponse ES 搜索返回结果
+type esSearchResponse struct {
+	Hits struct {
+		Total struct {
+			Value int `json:"value"`
+		} `json:"total"`
+		Hits []struct {
+			Source    model.QuestionEs    `json:"_source"`
+			Highlight map[string][]string `json:"highlight"`
+		} `json:"hits"`
+	} `json:"hits"`
+	Aggregations struct {
+		Tags struct {
+			Buckets []struct {
+				Key      string `json:"key"`
+				DocCount int    `json:"doc_count"`
+			} `json:"buckets"`
+		} `json:"tags"`
+	} `json:"aggregations"`
+}
+
+// GetEsQuestion 根据请求获取ES中的问题，同时返回命中的高亮片段和标签聚合
+func (r *questionRepository) GetEsQuestion(ctx context.Context, req *v1.QuestionRequest) ([]v1.Question, int, []v1.TagFacet, error) {
 	var buf bytes.Buffer
 	query := map[string]interface{}{
 		// 排序字段
 		"sort": []map[string]interface{}{},
 		"query": map[string]interface{}{
 			"bool": map[string]interface{}{
-				// 匹配条件,满足其中之一即可
-				"should": []map[string]interface{}{},
 				// 过滤条件
 				"filter": []map[string]interface{}{
 					{"term": map[string]interface{}{"is_delete": "0"}},
@@ -137,19 +248,19 @@ func (r *questionRepository) GetEsQuestion(ctx context.Context, req *v1.Question
 		}
 	}
 	if req.SearchText != nil && *req.SearchText != "" {
-		query["query"].(map[string]interface{})["bool"].(map[string]interface{})["minimum_should_match"] = 1
-		query["query"].(map[string]interface{})["bool"].(map[string]interface{})["should"] = append(
-			query["query"].(map[string]interface{})["bool"].(map[string]interface{})["should"].([]map[string]interface{}),
-			map[string]interface{}{
-				"match": map[string]interface{}{"title": req.SearchText},
-			},
-			map[string]interface{}{
-				"match": map[string]interface{}{"content": req.SearchText},
+		// 高亮命中的关键词，标题返回完整内容，正文和答案返回片段
+		// 高亮片段由前端作为 HTML 渲染，使用 html 编码器先转义用户输入的原文，再包裹高亮标签
+		query["highlight"] = map[string]interface{}{
+			"encoder":   "html",
+			"pre_tags":  []string{"<em>"},
+			"post_tags": []string{"</em>"},
+			"fields": map[string]interface{}{
+				"title":   map[string]interface{}{"number_of_fragments": 0},
+				"content": map[string]interface{}{"fragment_size": 100, "number_of_fragments": 3},
+				"answer":  map[string]interface{}{"fragment_size": 100, "number_of_fragments": 3},
 			},
-			map[string]interface{}{
-				"match": map[string]interface{}{"answer": req.SearchText},
-			},
-		)
+		}
+		query["query"].(map[string]interface{})["bool"].(map[string]interface{})["must"] = questionTextQuery(*req.SearchText)
 	}
 	if req.Tags != nil && len(req.Tags) > 0 {
 		for _, tag := range req.Tags {
@@ -185,8 +296,22 @@ func (r *questionRepository) GetEsQuestion(ctx context.Context, req *v1.Question
 			},
 		)
 	}
+	if req.ReviewStatus != nil {
+		query["query"].(map[string]interface{})["bool"].(map[string]interface{})["filter"] = append(
+			query["query"].(map[string]interface{})["bool"].(map[string]interface{})["filter"].([]map[string]interface{}),
+			map[string]interface{}{
+				"term": map[string]interface{}{"review_status": *req.ReviewStatus},
+			},
+		)
+	}
+	// 统计当前条件下各标签的题目数，前端据此展示标签过滤项
+	query["aggs"] = map[string]interface{}{
+		"tags": map[string]interface{}{
+			"terms": map[string]interface{}{"field": "tags", "size": constant.QuestionTagFacetSize},
+		},
+	}
 	if err := json.NewEncoder(&buf).Encode(query); err != nil {
-		return nil, 0, err
+		return nil, 0, nil, err
 	}
 	var size, current int
 	size = 12   // 默认值
@@ -198,7 +323,7 @@ func (r *questionRepository) GetEsQuestion(ctx context.Context, req *v1.Question
 	}
 	res, err := r.es.Search(
 		r.es.Search.WithContext(ctx),
-		r.es.Search.WithIndex("question"),
+		r.es.Search.WithIndex(constant.QuestionIndexAlias),
 		r.es.Search.WithBody(&buf),
 		r.es.Search.WithTrackTotalHits(true),
 		r.es.Search.WithPretty(),
@@ -206,46 +331,110 @@ func (r *questionRepository) GetEsQuestion(ctx context.Context, req *v1.Question
 		// 分页字段
 		r.es.Search.WithFrom(size*(current-1)),
 	)
-	if err != nil || res.IsError() {
-		return nil, 0, err
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return nil, 0, nil, fmt.Errorf("search question: %s", res.String())
 	}
-	var rr map[string]interface{}
+	var rr esSearchResponse
 	if err = json.NewDecoder(res.Body).Decode(&rr); err != nil {
-		return nil, 0, err
+		return nil, 0, nil, err
 	}
-	var total int
-	var questions []v1.Question
-	total = int(rr["hits"].(map[string]interface{})["total"].(map[string]interface{})["value"].(float64))
-	for _, hit := range rr["hits"].(map[string]interface{})["hits"].([]interface{}) {
-		if _, v := hit.(map[string]interface{})["_source"]; v {
-			body, err := json.Marshal(hit.(map[string]interface{})["_source"])
-			if err != nil {
-				fmt.Println(err)
-				continue
-			}
-			var q model.QuestionEs
-			if err := json.Unmarshal(body, &q); err != nil {
-				fmt.Println(err)
-				continue
+
+	questions := make([]v1.Question, 0, len(rr.Hits.Hits))
+	for _, hit := range rr.Hits.Hits {
+		q := hit.Source
+		id := utils.Int64TOString(q.Id)
+		userId := utils.Int64TOString(q.UserId)
+		tags := utils.StringsToString(q.Tags)
+		question := v1.Question{
+			Answer:       &q.Answer,
+			Content:      &q.Content,
+			CreateTime:   &q.CreateTime,
+			EditTime:     &q.EditTime,
+			ID:           &id,
+			IsDelete:     &q.IsDelete,
+			Tags:         &tags,
+			Title:        &q.Title,
+			UpdateTime:   &q.UpdateTime,
+			UserID:       &userId,
+			ReviewStatus: &q.ReviewStatus,
+		}
+		if len(hit.Highlight) > 0 {
+			question.Highlight = &v1.QuestionHighlight{
+				Title:   hit.Highlight["title"],
+				Content: hit.Highlight["content"],
+				Answer:  hit.Highlight["answer"],
 			}
+		}
+		questions = append(questions, question)
+	}
+
+	selected := make(map[string]bool, len(req.Tags))
+	for _, tag := range req.Tags {
+		selected[tag] = true
+	}
+	facets := make([]v1.TagFacet, 0, len(rr.Aggregations.Tags.Buckets))
+	for _, bucket := range rr.Aggregations.Tags.Buckets {
+		facets = append(facets, v1.TagFacet{
+			Tag:      bucket.Key,
+			Count:    bucket.DocCount,
+			Selected: selected[bucket.Key],
+		})
+	}
+	return questions, rr.Hits.Total.Value, facets, nil
+}
+
+// questionPhrasePattern 搜索文本中用双引号包裹的短语
+var questionPhrasePattern = regexp.MustCompile(`"([^"]+)"`)
 
-			id := utils.Int64TOString(q.Id)
-			userId := utils.Int64TOString(q.UserId)
-			questions = append(questions, v1.Question{
-				Answer:     &q.Answer,
-				Content:    &q.Content,
-				CreateTime: &q.CreateTime,
-				EditTime:   &q.EditTime,
-				ID:         &id,
-				IsDelete:   &q.IsDelete,
-				Tags:       &q.Tags,
-				Title:      &q.Title,
-				UpdateTime: &q.UpdateTime,
-				UserID:     &userId,
+// questionTextQuery 构造关键词查询：按字段权重匹配，词语连续出现时额外加分；
+// 双引号包裹的短语必须完整出现
+func questionTextQuery(searchText string) map[string]interface{} {
+	var must, should []map[string]interface{}
+	for _, m := range questionPhrasePattern.FindAllStringSubmatch(searchText, -1) {
+		if phrase := strings.TrimSpace(m[1]); phrase != "" {
+			must = append(must, map[string]interface{}{
+				"multi_match": map[string]interface{}{
+					"query":  phrase,
+					"type":   "phrase",
+					"fields": constant.QuestionSearchFields,
+				},
 			})
 		}
 	}
-	return questions, total, nil
+	text := strings.TrimSpace(strings.ReplaceAll(questionPhrasePattern.ReplaceAllString(searchText, " "), `"`, " "))
+	if text != "" {
+		// 拼音子字段未开启时不存在，multi_match 会忽略不存在的字段
+		must = append(must, map[string]interface{}{
+			"multi_match": map[string]interface{}{
+				"query":  text,
+				"type":   "best_fields",
+				"fields": append(append([]string{}, constant.QuestionSearchFields...), "title.pinyin"),
+			},
+		})
+		should = append(should, map[string]interface{}{
+			"multi_match": map[string]interface{}{
+				"query":  text,
+				"type":   "phrase",
+				"slop":   2,
+				"fields": constant.QuestionSearchFields,
+				"boost":  2,
+			},
+		})
+	}
+	if len(must) == 0 {
+		// 只输入了引号时匹配不到任何内容
+		return map[string]interface{}{"match_none": map[string]interface{}{}}
+	}
+	return map[string]interface{}{
+		"bool": map[string]interface{}{
+			"must":   must,
+			"should": should,
+		},
+	}
 }
 
 // AddDataToEs 向ES中添加数据
@@ -260,7 +449,7 @@ func (r *questionRepository) AddDataToEs(ctx context.Context, data []model.Quest
 			return err
 		}
 		req := esapi.UpdateRequest{
-			Index:      "question",
+			Index:      constant.QuestionIndexAlias,
 			DocumentID: strconv.FormatInt(question.Id, 10),
 			Body:       bytes.NewReader(marshal),
 		}
@@ -271,10 +460,67 @@ func (r *questionRepository) AddDataToEs(ctx context.Context, data []model.Quest
 	return nil
 }
 
-// GetAllQuestion 获取所有问题
-func (r *questionRepository) GetAllQuestion(ctx context.Context, time time.Time) ([]model.Question, error) {
+// IndexEsQuestion 使用外部版本号写入ES文档，版本号不大于已有文档时ES返回冲突，视为已处理
+func (r *questionRepository) IndexEsQuestion(ctx context.Context, data model.QuestionEs, version uint64) error {
+	marshal, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	v := int(version)
+	req := esapi.IndexRequest{
+		Index:       constant.QuestionIndexAlias,
+		DocumentID:  strconv.FormatInt(data.Id, 10),
+		Body:        bytes.NewReader(marshal),
+		Version:     &v,
+		VersionType: "external",
+	}
+	res, err := req.Do(ctx, r.es)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() && res.StatusCode != http.StatusConflict {
+		return fmt.Errorf("index question %d: %s", data.Id, res.String())
+	}
+	return nil
+}
+
+// DeleteEsQuestion 使用外部版本号删除ES文档，文档不存在或版本冲突时视为已处理
+func (r *questionRepository) DeleteEsQuestion(ctx context.Context, id uint64, version uint64) error {
+	v := int(version)
+	req := esapi.DeleteRequest{
+		Index:       constant.QuestionIndexAlias,
+		DocumentID:  strconv.FormatUint(id, 10),
+		Version:     &v,
+		VersionType: "external",
+	}
+	res, err := req.Do(ctx, r.es)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() && res.StatusCode != http.StatusConflict && res.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("delete question %d: %s", id, res.String())
+	}
+	return nil
+}
+
+// GetByIDUnscoped 根据ID获取问题，包括已删除的问题，不存在时返回 nil
+func (r *questionRepository) GetByIDUnscoped(ctx context.Context, id uint64) (*model.Question, error) {
+	var question model.Question
+	if err := r.DB(ctx).Unscoped().Where("id = ?", id).First(&question).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &question, nil
+}
+
+// ListQuestionAfterId 获取ID大于 afterId 的一批问题，按ID升序
+func (r *questionRepository) ListQuestionAfterId(ctx context.Context, afterId uint64, limit int) ([]model.Question, error) {
 	var questions []model.Question
-	if err := r.DB(ctx).Unscoped().Where("update_time >= ?", time).Find(&questions).Error; err != nil {
+	if err := r.DB(ctx).Where("id > ?", afterId).Order("id ASC").Limit(limit).Find(&questions).Error; err != nil {
 		return nil, err
 	}
 	return questions, nil
@@ -284,45 +530,38 @@ func (r *questionRepository) GetAllQuestion(ctx context.Context, time time.Time)
 func (r *questionRepository) GetQuestionByBankId(ctx context.Context, bankId uint64) ([]model.Question, int64, error) {
 	var questions []model.Question
 	var total int64
+	// 只返回审核通过的题目
 	if err := r.DB(ctx).Joins("INNER JOIN question_bank_question ON question.id = question_bank_question.question_id").
-		Where("question_bank_question.question_bank_id = ?", bankId).
-		Find(&questions).Count(&total).Error; err != nil {
+		Where("question_bank_question.question_bank_id = ? AND question.review_status = ?", bankId, constant.ReviewStatusPass).
+		Order("question_bank_question.question_order ASC, question_bank_question.id ASC").
+		Find(&questions).Error; err != nil {
 		return nil, 0, err
 	}
+	total = int64(len(questions))
 	return questions, total, nil
 }
 
 // Update 更新问题
 func (r *questionRepository) Update(ctx context.Context, question *model.Question) error {
-	if err := r.DB(ctx).Save(question).Error; err != nil {
+	// 计数字段由定时任务累加，更新题目时不能用旧值覆盖
+	if err := r.DB(ctx).Omit("view_num", "thumb_num", "favour_num").Save(question).Error; err != nil {
 		return err
 	}
 	return nil
 }
 
-// DeleteById 根据ID删除问题
+// DeleteById 根据ID删除问题，需要在事务中调用
 func (r *questionRepository) DeleteById(ctx context.Context, question *model.Question, id uint64) error {
-	tx := r.DB(ctx).Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
-		}
-	}()
-
-	if err := tx.Save(question).Error; err != nil {
-		tx.Rollback()
+	if err := r.DB(ctx).Model(&model.Question{}).Where("id = ?", id).
+		UpdateColumn("is_delete", question.IsDelete).Error; err != nil {
 		return err
 	}
-	if err := tx.Where("id = ?", id).Delete(question).Error; err != nil {
-		tx.Rollback()
+	if err := r.DB(ctx).Where("id = ?", id).Delete(&model.Question{}).Error; err != nil {
 		return err
 	}
-	if err := tx.Where("question_id = ?", id).Delete(&model.QuestionBankQuestion{}).Error; err != nil {
-		tx.Rollback()
+	if err := r.DB(ctx).Where("question_id = ?", id).Delete(&model.QuestionBankQuestion{}).Error; err != nil {
 		return err
 	}
-
-	tx.Commit()
 	return nil
 }
 
@@ -336,10 +575,10 @@ func (r *questionRepository) GetByID(ctx context.Context, id uint64, isHot any,
 		return nil, err
 	}
 
-	// 判断是否为热点数据
-	if isHot != nil && cacheKey != nil {
+	// 判断是否为热点数据，只缓存审核通过的题目
+	if isHot != nil && cacheKey != nil && question.ReviewStatus == constant.ReviewStatusPass {
 		if isHot.(bool) {
-			// 写入缓存（序列化为JSON字符串）
+			// 写入缓存（序列化为JSON字符串），缓存为所有用户共享，不写入点赞、收藏等当前用户的状态
 			qid := utils.Uint64TOString(question.ID)
 			tags, _ := utils.StringToStrings(*question.Tags)
 			uid := utils.Uint64TOString(question.UserID)
@@ -442,6 +681,10 @@ func (r *questionRepository) GetQuestion(ctx context.Context, req *v1.QuestionRe
 		conditions = append(conditions, "tags LIKE ?")
 		params = append(params, "%"+tags+"%")
 	}
+	if req.ReviewStatus != nil {
+		conditions = append(conditions, "question.review_status = ?")
+		params = append(params, *req.ReviewStatus)
+	}
 
 	// 构造完整的查询条件
 	query = strings.Join(conditions, " AND ")
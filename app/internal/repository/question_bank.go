@@ -3,10 +3,12 @@ package repository
 import (
 	v1 "app/api/v1"
 	"app/internal/model"
+	"app/pkg/constant"
 	"context"
 	"errors"
 	"gorm.io/gorm"
 	"strings"
+	"time"
 )
 
 // QuestionBankRepository 定义了一个问题库仓库接口
@@ -18,6 +20,9 @@ type QuestionBankRepository interface {
 	GetByID(ctx context.Context, id uint64) (*model.QuestionBank, error)
 	DeleteById(ctx context.Context, bank *model.QuestionBank, id uint64) error
 	Update(ctx context.Context, bank *model.QuestionBank) error
+	UpdateReviewStatus(ctx context.Context, ids []uint64, reviewStatus int, reviewMessage *string, reviewerId uint64) error
+	IncrViewNum(ctx context.Context, id uint64) error
+	FlushViewNum(ctx context.Context) error
 }
 
 // NewQuestionBankRepository 创建一个新的问题库仓库实例
@@ -34,6 +39,29 @@ type questionBankRepository struct {
 	*Repository
 }
 
+// IncrViewNum 累加问题库浏览量增量
+func (r *questionBankRepository) IncrViewNum(ctx context.Context, id uint64) error {
+	return incrCounter(ctx, r.Repository, constant.QuestionBankViewNumRedisKey, id, 1)
+}
+
+// FlushViewNum 将问题库浏览量增量刷回数据库
+func (r *questionBankRepository) FlushViewNum(ctx context.Context) error {
+	return flushCounter(ctx, r.Repository, constant.QuestionBankViewNumRedisKey, &model.QuestionBank{}, "view_num")
+}
+
+// UpdateReviewStatus 批量更新问题库审核状态
+func (r *questionBankRepository) UpdateReviewStatus(ctx context.Context, ids []uint64, reviewStatus int, reviewMessage *string, reviewerId uint64) error {
+	if err := r.DB(ctx).Model(&model.QuestionBank{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+		"review_status":  reviewStatus,
+		"review_message": reviewMessage,
+		"reviewer_id":    reviewerId,
+		"review_time":    time.Now(),
+	}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
 // Update 更新问题库
 func (r *questionBankRepository) Update(ctx context.Context, bank *model.QuestionBank) error {
 	if err := r.DB(ctx).Save(bank).Error; err != nil {
@@ -151,6 +179,10 @@ func (r *questionBankRepository) GetQuestionBank(ctx context.Context, req *v1.Qu
 		conditions = append(conditions, "description LIKE ?")
 		params = append(params, "%"+description+"%")
 	}
+	if req.ReviewStatus != nil {
+		conditions = append(conditions, "review_status = ?")
+		params = append(params, *req.ReviewStatus)
+	}
 
 	// 构造完整的查询条件
 	query = strings.Join(conditions, " AND ")
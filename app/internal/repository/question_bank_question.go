@@ -1,8 +1,13 @@
 package repository
 
 import (
+	v1 "app/api/v1"
 	"app/internal/model"
+	"app/pkg/constant"
 	"context"
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // QuestionBankQuestionRepository 定义了一个问题库问题的仓库接口
@@ -13,6 +18,31 @@ type QuestionBankQuestionRepository interface {
 	RemoveQuestionBankQuestion(ctx context.Context, id uint64, id2 uint64) (bool, error)
 	BatchAddQuestionBankQuestion(ctx context.Context, question []model.QuestionBankQuestion) error
 	BatchRemoveQuestionBankQuestion(ctx context.Context, question []model.QuestionBankQuestion) error
+	LockQuestionBank(ctx context.Context, questionBankId uint64) error
+	GetMaxQuestionOrder(ctx context.Context, questionBankId uint64) (int, error)
+	GetByQuestionBankIdAndQuestionId(ctx context.Context, questionBankId uint64, questionId uint64) (*model.QuestionBankQuestion, error)
+	CreateQuestionBankQuestion(ctx context.Context, questionBankQuestion *model.QuestionBankQuestion) error
+	ShiftQuestionOrder(ctx context.Context, questionBankId uint64, from int, to int, delta int) error
+	UpdateQuestionOrder(ctx context.Context, id uint64, order int) error
+	GetNeighborQuestionBankQuestion(ctx context.Context, questionBankId uint64, order int, id uint64, next bool) (*model.QuestionBankQuestion, error)
+	CountPassQuestionBefore(ctx context.Context, questionBankId uint64, order int, id uint64) (int, error)
+	CountPassQuestion(ctx context.Context, questionBankId uint64) (int, error)
+	ListQuestionBankQuestionByPage(ctx context.Context, req *v1.QuestionBankQuestionQueryRequest) ([]QuestionBankQuestionDetail, int, error)
+	ListQuestionBelongBank(ctx context.Context, questionIds []uint64) ([]QuestionBelongBank, error)
+}
+
+// QuestionBankQuestionDetail 题库题目关系及关联的题目信息
+type QuestionBankQuestionDetail struct {
+	model.QuestionBankQuestion
+	QuestionTitle        *string
+	QuestionReviewStatus *int
+}
+
+// QuestionBelongBank 题目所属的题库
+type QuestionBelongBank struct {
+	QuestionID     uint64
+	QuestionBankID uint64
+	Title          *string
 }
 
 // NewQuestionBankQuestionRepository 创建一个新的问题库问题仓库
@@ -29,44 +59,198 @@ type questionBankQuestionRepository struct {
 	*Repository
 }
 
-// BatchRemoveQuestionBankQuestion 批量删除问题库问题
-func (r *questionBankQuestionRepository) BatchRemoveQuestionBankQuestion(ctx context.Context, question []model.QuestionBankQuestion) error {
-	tx := r.DB(ctx).Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
+// LockQuestionBank 锁定题库，同一题库的题目排序操作需要串行执行
+func (r *questionBankQuestionRepository) LockQuestionBank(ctx context.Context, questionBankId uint64) error {
+	var bank model.QuestionBank
+	if err := r.DB(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").
+		Where("id = ?", questionBankId).First(&bank).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return v1.ErrNotFound
 		}
-	}()
-
-	// 每次删除1000条
-	for i := 0; i < len(question); i += 1000 {
-		for j := i; j < i+1000 && j < len(question); j++ {
-			if err := tx.Where("question_id = ? AND question_bank_id = ?", question[j].QuestionID, question[j].QuestionBankID).Delete(question).Error; err != nil {
-				tx.Rollback()
-				return err
-			}
+		return err
+	}
+	return nil
+}
+
+// GetMaxQuestionOrder 获取题库中最大的题号，题库为空时返回 0
+func (r *questionBankQuestionRepository) GetMaxQuestionOrder(ctx context.Context, questionBankId uint64) (int, error) {
+	var order int
+	if err := r.DB(ctx).Model(&model.QuestionBankQuestion{}).
+		Where("question_bank_id = ?", questionBankId).
+		Select("COALESCE(MAX(question_order), 0)").Scan(&order).Error; err != nil {
+		return 0, err
+	}
+	return order, nil
+}
+
+// GetByQuestionBankIdAndQuestionId 获取题目在题库中的关系，不存在时返回 nil
+func (r *questionBankQuestionRepository) GetByQuestionBankIdAndQuestionId(ctx context.Context, questionBankId uint64, questionId uint64) (*model.QuestionBankQuestion, error) {
+	var questionBankQuestion model.QuestionBankQuestion
+	if err := r.DB(ctx).Where("question_bank_id = ? AND question_id = ?", questionBankId, questionId).
+		First(&questionBankQuestion).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
 		}
+		return nil, err
 	}
+	return &questionBankQuestion, nil
+}
 
-	tx.Commit()
+// CreateQuestionBankQuestion 添加题目题库关系
+func (r *questionBankQuestionRepository) CreateQuestionBankQuestion(ctx context.Context, questionBankQuestion *model.QuestionBankQuestion) error {
+	if err := r.DB(ctx).Create(questionBankQuestion).Error; err != nil {
+		return err
+	}
 	return nil
 }
 
-// BatchAddQuestionBankQuestion 批量添加问题库问题
-func (r *questionBankQuestionRepository) BatchAddQuestionBankQuestion(ctx context.Context, question []model.QuestionBankQuestion) error {
-	tx := r.DB(ctx).Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
-		}
-	}()
+// ShiftQuestionOrder 将题号在 [from, to] 区间内的题目整体移动 delta 位，to 小于 0 表示不限上界
+func (r *questionBankQuestionRepository) ShiftQuestionOrder(ctx context.Context, questionBankId uint64, from int, to int, delta int) error {
+	db := r.DB(ctx).Model(&model.QuestionBankQuestion{}).
+		Where("question_bank_id = ? AND question_order >= ?", questionBankId, from)
+	if to >= 0 {
+		db = db.Where("question_order <= ?", to)
+	}
+	if err := db.UpdateColumn("question_order", gorm.Expr("question_order + ?", delta)).Error; err != nil {
+		return err
+	}
+	return nil
+}
 
-	if err := tx.CreateInBatches(question, len(question)).Error; err != nil {
-		tx.Rollback()
+// UpdateQuestionOrder 更新题号
+func (r *questionBankQuestionRepository) UpdateQuestionOrder(ctx context.Context, id uint64, order int) error {
+	if err := r.DB(ctx).Model(&model.QuestionBankQuestion{}).Where("id = ?", id).
+		UpdateColumn("question_order", order).Error; err != nil {
 		return err
 	}
+	return nil
+}
+
+// GetNeighborQuestionBankQuestion 获取题库中上一道 / 下一道审核通过的题目，不存在时返回 nil
+func (r *questionBankQuestionRepository) GetNeighborQuestionBankQuestion(ctx context.Context, questionBankId uint64, order int, id uint64, next bool) (*model.QuestionBankQuestion, error) {
+	var questionBankQuestion model.QuestionBankQuestion
+	db := r.DB(ctx).Joins("INNER JOIN question ON question.id = question_bank_question.question_id AND question.deleted_at IS NULL").
+		Where("question_bank_question.question_bank_id = ? AND question.review_status = ?", questionBankId, constant.ReviewStatusPass)
+	if next {
+		db = db.Where("(question_bank_question.question_order > ? OR (question_bank_question.question_order = ? AND question_bank_question.id > ?))", order, order, id).
+			Order("question_bank_question.question_order ASC, question_bank_question.id ASC")
+	} else {
+		db = db.Where("(question_bank_question.question_order < ? OR (question_bank_question.question_order = ? AND question_bank_question.id < ?))", order, order, id).
+			Order("question_bank_question.question_order DESC, question_bank_question.id DESC")
+	}
+	if err := db.First(&questionBankQuestion).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &questionBankQuestion, nil
+}
 
-	tx.Commit()
+// CountPassQuestionBefore 统计题库中排在指定位置之前的审核通过的题目数量
+func (r *questionBankQuestionRepository) CountPassQuestionBefore(ctx context.Context, questionBankId uint64, order int, id uint64) (int, error) {
+	var count int64
+	if err := r.DB(ctx).Model(&model.QuestionBankQuestion{}).
+		Joins("INNER JOIN question ON question.id = question_bank_question.question_id AND question.deleted_at IS NULL").
+		Where("question_bank_question.question_bank_id = ? AND question.review_status = ?", questionBankId, constant.ReviewStatusPass).
+		Where("(question_bank_question.question_order < ? OR (question_bank_question.question_order = ? AND question_bank_question.id < ?))", order, order, id).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
+// CountPassQuestion 统计题库中审核通过的题目数量
+func (r *questionBankQuestionRepository) CountPassQuestion(ctx context.Context, questionBankId uint64) (int, error) {
+	var count int64
+	if err := r.DB(ctx).Model(&model.QuestionBankQuestion{}).
+		Joins("INNER JOIN question ON question.id = question_bank_question.question_id AND question.deleted_at IS NULL").
+		Where("question_bank_question.question_bank_id = ? AND question.review_status = ?", questionBankId, constant.ReviewStatusPass).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
+// ListQuestionBankQuestionByPage 分页查询题库题目关系，关联题目标题和审核状态
+func (r *questionBankQuestionRepository) ListQuestionBankQuestionByPage(ctx context.Context, req *v1.QuestionBankQuestionQueryRequest) ([]QuestionBankQuestionDetail, int, error) {
+	var details []QuestionBankQuestionDetail
+	var total int64
+	db := r.DB(ctx).Model(&model.QuestionBankQuestion{}).
+		Joins("LEFT JOIN question ON question.id = question_bank_question.question_id AND question.deleted_at IS NULL")
+	if req.QuestionBankID != nil {
+		db = db.Where("question_bank_question.question_bank_id = ?", *req.QuestionBankID)
+	}
+	if req.QuestionID != nil {
+		db = db.Where("question_bank_question.question_id = ?", *req.QuestionID)
+	}
+	if req.UserID != nil {
+		db = db.Where("question_bank_question.user_id = ?", *req.UserID)
+	}
+	if req.CreateTimeAfter != nil {
+		db = db.Where("question_bank_question.create_time >= ?", *req.CreateTimeAfter)
+	}
+	if req.ReviewStatus != nil {
+		db = db.Where("question.review_status = ?", *req.ReviewStatus)
+	}
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// 默认按题号排序，题号相同时按添加顺序
+	sortOrder := "ASC"
+	if req.SortOrder != nil && *req.SortOrder == "descend" {
+		sortOrder = "DESC"
+	}
+	sortField := "question_bank_question.question_order"
+	if req.SortField != nil && *req.SortField == "createTime" {
+		sortField = "question_bank_question.create_time"
+	}
+	offset := (*req.Current - 1) * *req.PageSize
+	if err := db.Select("question_bank_question.*, question.title AS question_title, question.review_status AS question_review_status").
+		Order(sortField + " " + sortOrder).Order("question_bank_question.id " + sortOrder).
+		Offset(offset).Limit(*req.PageSize).Scan(&details).Error; err != nil {
+		return nil, 0, err
+	}
+	return details, int(total), nil
+}
+
+// ListQuestionBelongBank 查询题目所属的题库
+func (r *questionBankQuestionRepository) ListQuestionBelongBank(ctx context.Context, questionIds []uint64) ([]QuestionBelongBank, error) {
+	var belongs []QuestionBelongBank
+	if len(questionIds) == 0 {
+		return belongs, nil
+	}
+	if err := r.DB(ctx).Model(&model.QuestionBankQuestion{}).
+		Select("question_bank_question.question_id, question_bank_question.question_bank_id, question_bank.title").
+		Joins("INNER JOIN question_bank ON question_bank.id = question_bank_question.question_bank_id AND question_bank.deleted_at IS NULL").
+		Where("question_bank_question.question_id IN ?", questionIds).
+		Order("question_bank_question.question_id ASC, question_bank_question.question_bank_id ASC").
+		Scan(&belongs).Error; err != nil {
+		return nil, err
+	}
+	return belongs, nil
+}
+
+// BatchRemoveQuestionBankQuestion 批量删除问题库问题，需要在事务中调用
+func (r *questionBankQuestionRepository) BatchRemoveQuestionBankQuestion(ctx context.Context, question []model.QuestionBankQuestion) error {
+	for _, q := range question {
+		if err := r.DB(ctx).Where("question_id = ? AND question_bank_id = ?", q.QuestionID, q.QuestionBankID).
+			Delete(&model.QuestionBankQuestion{}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BatchAddQuestionBankQuestion 批量添加问题库问题，需要在事务中调用
+func (r *questionBankQuestionRepository) BatchAddQuestionBankQuestion(ctx context.Context, question []model.QuestionBankQuestion) error {
+	if len(question) == 0 {
+		return nil
+	}
+	if err := r.DB(ctx).CreateInBatches(question, 1000).Error; err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -112,7 +296,7 @@ func (r *questionBankQuestionRepository) GetQuestionBankQuestion(ctx context.Con
 		}
 	// 根据questionBankId查询
 	default:
-		if err := r.DB(ctx).Where("question_bank_id = ?", id).Find(&questionBankQuestion).Error; err != nil {
+		if err := r.DB(ctx).Where("question_bank_id = ?", id).Order("question_order ASC, id ASC").Find(&questionBankQuestion).Error; err != nil {
 			return nil, err
 		}
 	}
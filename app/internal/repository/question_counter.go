@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"strconv"
+)
+
+// snapshotCounterScript 将增量 hash 重命名为快照后返回快照内容；上一轮的快照未刷完时不重命名，先继续刷上一轮的快照
+var snapshotCounterScript = `
+if redis.call('EXISTS', KEYS[2]) == 0 and redis.call('EXISTS', KEYS[1]) == 1 then
+	redis.call('RENAME', KEYS[1], KEYS[2])
+end
+return redis.call('HGETALL', KEYS[2])
+`
+
+// incrCounter 在 Redis 中累加计数的增量，HINCRBY 是原子操作，并发下不会丢失
+func incrCounter(ctx context.Context, r *Repository, key string, id uint64, delta int64) error {
+	return r.rdb.HIncrBy(ctx, key, strconv.FormatUint(id, 10), delta).Err()
+}
+
+// flushCounter 将 Redis 中的计数增量刷回数据库，table 为对应的模型，column 为计数列；
+// 先把增量转成快照，写库成功后再删除快照中的 field，进程中途退出时增量仍保留在快照中，下一轮继续刷
+func flushCounter(ctx context.Context, r *Repository, key string, table interface{}, column string) error {
+	snapshotKey := key + ":flushing"
+	values, err := r.rdb.Eval(ctx, snapshotCounterScript, []string{key, snapshotKey}).StringSlice()
+	if err != nil {
+		return err
+	}
+	for i := 0; i+1 < len(values); i += 2 {
+		field, value := values[i], values[i+1]
+		delta, err := strconv.ParseInt(value, 10, 64)
+		id, idErr := strconv.ParseUint(field, 10, 64)
+		if err == nil && idErr == nil && delta != 0 {
+			if err = r.DB(ctx).Model(table).Where("id = ?", id).
+				UpdateColumn(column, gorm.Expr(column+" + ?", delta)).Error; err != nil {
+				// 写库失败时保留快照，等待下一轮重试
+				return err
+			}
+		}
+		// 写库后删除快照中的 field，删除前退出时下一轮会重复累加这一个 field，但不会丢失增量
+		if err = r.rdb.HDel(ctx, snapshotKey, field).Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
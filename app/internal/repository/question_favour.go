@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"app/internal/model"
+	"app/pkg/constant"
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// QuestionFavourRepository 定义了一个题目收藏仓库接口
+type QuestionFavourRepository interface {
+	GetByQuestionIdAndUserId(ctx context.Context, questionId uint64, userId uint64) (*model.QuestionFavour, error)
+	Create(ctx context.Context, favour *model.QuestionFavour) (bool, error)
+	DeleteById(ctx context.Context, id uint64) (bool, error)
+	GetFavourQuestionIds(ctx context.Context, userId uint64, questionIds []uint64) (map[uint64]bool, error)
+	IncrFavourNum(ctx context.Context, questionId uint64, delta int64) error
+	FlushFavourNum(ctx context.Context) error
+	ListFavourQuestionByPage(ctx context.Context, userId uint64, current int, pageSize int) ([]model.Question, int, error)
+}
+
+// NewQuestionFavourRepository 创建一个新的题目收藏仓库
+func NewQuestionFavourRepository(
+	repository *Repository,
+) QuestionFavourRepository {
+	return &questionFavourRepository{
+		Repository: repository,
+	}
+}
+
+type questionFavourRepository struct {
+	*Repository
+}
+
+// GetByQuestionIdAndUserId 获取用户对题目的收藏记录，不存在时返回 nil
+func (r *questionFavourRepository) GetByQuestionIdAndUserId(ctx context.Context, questionId uint64, userId uint64) (*model.QuestionFavour, error) {
+	var favour model.QuestionFavour
+	if err := r.DB(ctx).Where("question_id = ? AND user_id = ?", questionId, userId).First(&favour).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &favour, nil
+}
+
+// Create 添加收藏记录，记录已存在时不做修改，返回是否新增了记录
+func (r *questionFavourRepository) Create(ctx context.Context, favour *model.QuestionFavour) (bool, error) {
+	result := r.DB(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(favour)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// DeleteById 删除收藏记录，返回是否删除了记录
+func (r *questionFavourRepository) DeleteById(ctx context.Context, id uint64) (bool, error) {
+	result := r.DB(ctx).Delete(&model.QuestionFavour{}, id)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetFavourQuestionIds 获取用户在给定题目中收藏过的题目
+func (r *questionFavourRepository) GetFavourQuestionIds(ctx context.Context, userId uint64, questionIds []uint64) (map[uint64]bool, error) {
+	result := make(map[uint64]bool)
+	if len(questionIds) == 0 {
+		return result, nil
+	}
+	var ids []uint64
+	if err := r.DB(ctx).Model(&model.QuestionFavour{}).
+		Where("user_id = ? AND question_id IN ?", userId, questionIds).
+		Pluck("question_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		result[id] = true
+	}
+	return result, nil
+}
+
+// IncrFavourNum 累加题目收藏数增量
+func (r *questionFavourRepository) IncrFavourNum(ctx context.Context, questionId uint64, delta int64) error {
+	return incrCounter(ctx, r.Repository, constant.QuestionFavourNumRedisKey, questionId, delta)
+}
+
+// FlushFavourNum 将收藏数增量刷回数据库
+func (r *questionFavourRepository) FlushFavourNum(ctx context.Context) error {
+	return flushCounter(ctx, r.Repository, constant.QuestionFavourNumRedisKey, &model.Question{}, "favour_num")
+}
+
+// ListFavourQuestionByPage 分页获取用户收藏的题目，按收藏时间倒序
+func (r *questionFavourRepository) ListFavourQuestionByPage(ctx context.Context, userId uint64, current int, pageSize int) ([]model.Question, int, error) {
+	var questions []model.Question
+	var total int64
+	db := r.DB(ctx).Model(&model.Question{}).
+		Joins("JOIN question_favour ON question_favour.question_id = question.id").
+		Where("question_favour.user_id = ?", userId)
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (current - 1) * pageSize
+	if err := db.Order("question_favour.create_time DESC").
+		Offset(offset).Limit(pageSize).Find(&questions).Error; err != nil {
+		return nil, 0, err
+	}
+	return questions, int(total), nil
+}
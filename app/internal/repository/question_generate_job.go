@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"app/internal/model"
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"time"
+)
+
+// QuestionGenerateJobRepository 定义了 AI 生成题目任务仓库接口
+type QuestionGenerateJobRepository interface {
+	// 创建任务
+	Create(ctx context.Context, job *model.QuestionGenerateJob) error
+	// 根据ID获取任务，不存在时返回 nil
+	GetByID(ctx context.Context, id uint64) (*model.QuestionGenerateJob, error)
+	// 以当前状态为条件更新任务状态，状态已被修改时返回 false
+	UpdateStatus(ctx context.Context, id uint64, fromStatus int, toStatus int) (bool, error)
+	// 保存 AI 返回的题目标题，同时重置进度
+	SaveTitles(ctx context.Context, job *model.QuestionGenerateJob) error
+	// 更新任务进度
+	UpdateProgress(ctx context.Context, job *model.QuestionGenerateJob) error
+	// 结束任务，记录最终状态、进度和失败原因
+	Finish(ctx context.Context, job *model.QuestionGenerateJob) error
+	// 分页获取用户的任务，按创建时间倒序
+	ListByPage(ctx context.Context, userId uint64, current int, pageSize int) ([]model.QuestionGenerateJob, int, error)
+}
+
+// NewQuestionGenerateJobRepository 创建 AI 生成题目任务仓库
+func NewQuestionGenerateJobRepository(
+	repository *Repository,
+) QuestionGenerateJobRepository {
+	return &questionGenerateJobRepository{
+		Repository: repository,
+	}
+}
+
+type questionGenerateJobRepository struct {
+	*Repository
+}
+
+// Create 创建任务
+func (r *questionGenerateJobRepository) Create(ctx context.Context, job *model.QuestionGenerateJob) error {
+	return r.DB(ctx).Create(job).Error
+}
+
+// GetByID 根据ID获取任务
+func (r *questionGenerateJobRepository) GetByID(ctx context.Context, id uint64) (*model.QuestionGenerateJob, error) {
+	var job model.QuestionGenerateJob
+	if err := r.DB(ctx).Where("id = ?", id).First(&job).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+// UpdateStatus 条件更新任务状态
+func (r *questionGenerateJobRepository) UpdateStatus(ctx context.Context, id uint64, fromStatus int, toStatus int) (bool, error) {
+	result := r.DB(ctx).Model(&model.QuestionGenerateJob{}).
+		Where("id = ? AND status = ?", id, fromStatus).
+		Updates(map[string]interface{}{
+			"status":      toStatus,
+			"update_time": time.Now(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// SaveTitles 保存 AI 返回的题目标题
+func (r *questionGenerateJobRepository) SaveTitles(ctx context.Context, job *model.QuestionGenerateJob) error {
+	return r.DB(ctx).Model(&model.QuestionGenerateJob{}).
+		Where("id = ?", job.ID).
+		Updates(map[string]interface{}{
+			"titles":        job.Titles,
+			"total":         job.Total,
+			"processed_num": job.ProcessedNum,
+			"created_num":   job.CreatedNum,
+			"skipped_num":   job.SkippedNum,
+			"update_time":   time.Now(),
+		}).Error
+}
+
+// UpdateProgress 更新任务进度
+func (r *questionGenerateJobRepository) UpdateProgress(ctx context.Context, job *model.QuestionGenerateJob) error {
+	return r.DB(ctx).Model(&model.QuestionGenerateJob{}).
+		Where("id = ?", job.ID).
+		Updates(map[string]interface{}{
+			"total":         job.Total,
+			"processed_num": job.ProcessedNum,
+			"created_num":   job.CreatedNum,
+			"skipped_num":   job.SkippedNum,
+			"update_time":   time.Now(),
+		}).Error
+}
+
+// Finish 结束任务
+func (r *questionGenerateJobRepository) Finish(ctx context.Context, job *model.QuestionGenerateJob) error {
+	return r.DB(ctx).Model(&model.QuestionGenerateJob{}).
+		Where("id = ?", job.ID).
+		Updates(map[string]interface{}{
+			"status":        job.Status,
+			"total":         job.Total,
+			"processed_num": job.ProcessedNum,
+			"created_num":   job.CreatedNum,
+			"skipped_num":   job.SkippedNum,
+			"error_message": job.ErrorMessage,
+			"finish_time":   job.FinishTime,
+			"update_time":   time.Now(),
+		}).Error
+}
+
+// ListByPage 分页获取用户的任务
+func (r *questionGenerateJobRepository) ListByPage(ctx context.Context, userId uint64, current int, pageSize int) ([]model.QuestionGenerateJob, int, error) {
+	var jobs []model.QuestionGenerateJob
+	var total int64
+	db := r.DB(ctx).Model(&model.QuestionGenerateJob{}).Where("user_id = ?", userId)
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (current - 1) * pageSize
+	if err := db.Order("create_time DESC, id DESC").Offset(offset).Limit(pageSize).Find(&jobs).Error; err != nil {
+		return nil, 0, err
+	}
+	return jobs, int(total), nil
+}
@@ -0,0 +1,425 @@
+package repository
+
+import (
+	"app/internal/model"
+	"app/pkg/constant"
+	"app/pkg/embedding"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+	"github.com/spf13/viper"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// QuestionIndexRepository 定义了题目 ES 索引管理仓库接口，用于重建索引和切换别名
+type QuestionIndexRepository interface {
+	// 按显式的 mapping 和分词器创建索引
+	CreateIndex(ctx context.Context, index string) error
+	// 使用 _bulk 批量写入文档，version 为外部版本号
+	BulkIndex(ctx context.Context, index string, docs []model.QuestionEs, version uint64) error
+	// 写入完成后恢复刷新间隔并刷新索引
+	FinishBulk(ctx context.Context, index string) error
+	// 统计索引中的文档数
+	Count(ctx context.Context, index string) (int64, error)
+	// 获取别名当前指向的索引，isConcrete 表示存在与别名同名的实际索引
+	GetAliasIndices(ctx context.Context, alias string) (indices []string, isConcrete bool, err error)
+	// 原子地将别名切换到新索引
+	SwapAlias(ctx context.Context, alias string, newIndex string, oldIndices []string, isConcrete bool) error
+	// 列出指定前缀的索引
+	ListIndices(ctx context.Context, prefix string) ([]string, error)
+	// 删除索引
+	DeleteIndex(ctx context.Context, index string) error
+	// 将同义词规则推送到 ES 同义词集合，使用该集合的搜索分词器会自动重新加载
+	PutSynonyms(ctx context.Context, synonyms []model.QuestionSynonym) error
+}
+
+// NewQuestionIndexRepository 创建一个新的题目 ES 索引管理仓库
+func NewQuestionIndexRepository(
+	repository *Repository,
+	conf *viper.Viper,
+	embeddingProvider embedding.Provider,
+) QuestionIndexRepository {
+	analysis := questionAnalysis{
+		IndexTokenizer:  conf.GetString("data.elasticsearch.analysis.index_tokenizer"),
+		SearchTokenizer: conf.GetString("data.elasticsearch.analysis.search_tokenizer"),
+		Filters:         conf.GetStringSlice("data.elasticsearch.analysis.filters"),
+		Pinyin:          conf.GetBool("data.elasticsearch.analysis.pinyin"),
+		EmbeddingDims:   embeddingProvider.Dims(),
+	}
+	if analysis.IndexTokenizer == "" {
+		analysis.IndexTokenizer = "ik_max_word"
+	}
+	if analysis.SearchTokenizer == "" {
+		analysis.SearchTokenizer = "ik_smart"
+	}
+	if !conf.IsSet("data.elasticsearch.analysis.filters") {
+		analysis.Filters = []string{"lowercase"}
+	}
+	return &questionIndexRepository{
+		Repository: repository,
+		analysis:   analysis,
+	}
+}
+
+type questionIndexRepository struct {
+	*Repository
+	analysis questionAnalysis
+}
+
+// questionAnalysis 题目索引的分词配置
+type questionAnalysis struct {
+	// 建索引时使用的分词器
+	IndexTokenizer string
+	// 搜索时使用的分词器
+	SearchTokenizer string
+	// 分词后依次应用的过滤器
+	Filters []string
+	// 是否为标题增加拼音子字段，需要安装 analysis-pinyin 插件
+	Pinyin bool
+	// 文本向量维度
+	EmbeddingDims int
+}
+
+// questionIndexBody 题目索引的 settings 和 mappings，文本字段按配置的分词链分词，
+// 搜索时额外应用同义词过滤器，标签按关键词精确匹配
+func questionIndexBody(analysis questionAnalysis) map[string]interface{} {
+	text := map[string]interface{}{
+		"type":            "text",
+		"analyzer":        "question_text",
+		"search_analyzer": "question_search",
+	}
+	title := map[string]interface{}{
+		"type":            "text",
+		"analyzer":        "question_text",
+		"search_analyzer": "question_search",
+		"fields": map[string]interface{}{
+			"keyword": map[string]interface{}{"type": "keyword", "ignore_above": 256},
+		},
+	}
+	analyzer := map[string]interface{}{
+		"question_text": map[string]interface{}{
+			"type":      "custom",
+			"tokenizer": analysis.IndexTokenizer,
+			"filter":    analysis.Filters,
+		},
+		// 同义词过滤器可热更新，只能用于搜索分词器
+		"question_search": map[string]interface{}{
+			"type":      "custom",
+			"tokenizer": analysis.SearchTokenizer,
+			"filter":    append(append([]string{}, analysis.Filters...), "question_synonym"),
+		},
+		"question_suggest": map[string]interface{}{
+			"type":      "custom",
+			"tokenizer": analysis.SearchTokenizer,
+			"filter":    analysis.Filters,
+		},
+	}
+	settings := map[string]interface{}{
+		// 批量写入期间关闭刷新，写入完成后再恢复
+		"refresh_interval": "-1",
+		"analysis": map[string]interface{}{
+			"analyzer": analyzer,
+			"filter": map[string]interface{}{
+				"question_synonym": map[string]interface{}{
+					"type":         "synonym_graph",
+					"synonyms_set": constant.QuestionSynonymSet,
+					"updateable":   true,
+				},
+			},
+		},
+	}
+	if analysis.Pinyin {
+		analyzer["question_pinyin"] = map[string]interface{}{
+			"type":      "custom",
+			"tokenizer": "question_pinyin",
+			"filter":    []string{"lowercase"},
+		}
+		settings["analysis"].(map[string]interface{})["tokenizer"] = map[string]interface{}{
+			"question_pinyin": map[string]interface{}{
+				"type":                    "pinyin",
+				"keep_first_letter":       true,
+				"keep_full_pinyin":        true,
+				"keep_joined_full_pinyin": true,
+				"keep_original":           false,
+				"lowercase":               true,
+			},
+		}
+		title["fields"].(map[string]interface{})["pinyin"] = map[string]interface{}{
+			"type":     "text",
+			"analyzer": "question_pinyin",
+		}
+	}
+	return map[string]interface{}{
+		"settings": settings,
+		"mappings": map[string]interface{}{
+			"dynamic": "strict",
+			"properties": map[string]interface{}{
+				"id":    map[string]interface{}{"type": "long"},
+				"title": title,
+				// 边输入边搜索，自动生成 2、3 元 shingle 子字段用于前缀补全
+				"title_suggest": map[string]interface{}{
+					"type":     "search_as_you_type",
+					"analyzer": "question_suggest",
+				},
+				"content":       text,
+				"answer":        text,
+				"tags":          map[string]interface{}{"type": "keyword"},
+				"user_id":       map[string]interface{}{"type": "long"},
+				"edit_time":     map[string]interface{}{"type": "date"},
+				"create_time":   map[string]interface{}{"type": "date"},
+				"update_time":   map[string]interface{}{"type": "date"},
+				"is_delete":     map[string]interface{}{"type": "byte"},
+				"review_status": map[string]interface{}{"type": "integer"},
+				"embedding": map[string]interface{}{
+					"type":       "dense_vector",
+					"dims":       analysis.EmbeddingDims,
+					"index":      true,
+					"similarity": "cosine",
+				},
+			},
+		},
+	}
+}
+
+// CreateIndex 创建索引
+func (r *questionIndexRepository) CreateIndex(ctx context.Context, index string) error {
+	body, err := json.Marshal(questionIndexBody(r.analysis))
+	if err != nil {
+		return err
+	}
+	res, err := esapi.IndicesCreateRequest{Index: index, Body: bytes.NewReader(body)}.Do(ctx, r.es)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return fmt.Errorf("create index %s: %s", index, res.String())
+	}
+	return nil
+}
+
+// BulkIndex 批量写入文档，任意一条失败都返回错误
+func (r *questionIndexRepository) BulkIndex(ctx context.Context, index string, docs []model.QuestionEs, version uint64) error {
+	if len(docs) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, doc := range docs {
+		meta := map[string]interface{}{
+			"index": map[string]interface{}{
+				"_id":          strconv.FormatInt(doc.Id, 10),
+				"version":      version,
+				"version_type": "external",
+			},
+		}
+		if err := enc.Encode(meta); err != nil {
+			return err
+		}
+		if err := enc.Encode(doc); err != nil {
+			return err
+		}
+	}
+	res, err := esapi.BulkRequest{Index: index, Body: &buf}.Do(ctx, r.es)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return fmt.Errorf("bulk index %s: %s", index, res.String())
+	}
+
+	var result struct {
+		Errors bool `json:"errors"`
+		Items  []map[string]struct {
+			ID     string          `json:"_id"`
+			Status int             `json:"status"`
+			Error  json.RawMessage `json:"error"`
+		} `json:"items"`
+	}
+	if err = json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return err
+	}
+	if !result.Errors {
+		return nil
+	}
+	for _, item := range result.Items {
+		for _, action := range item {
+			if action.Status >= http.StatusMultipleChoices {
+				return fmt.Errorf("bulk index %s: document %s: %s", index, action.ID, action.Error)
+			}
+		}
+	}
+	return errors.New("bulk index " + index + ": unknown error")
+}
+
+// FinishBulk 恢复刷新间隔并立即刷新，使写入的文档可以被统计和搜索
+func (r *questionIndexRepository) FinishBulk(ctx context.Context, index string) error {
+	res, err := esapi.IndicesPutSettingsRequest{
+		Index: []string{index},
+		Body:  strings.NewReader(`{"index":{"refresh_interval":"1s"}}`),
+	}.Do(ctx, r.es)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return fmt.Errorf("put settings %s: %s", index, res.String())
+	}
+
+	refresh, err := esapi.IndicesRefreshRequest{Index: []string{index}}.Do(ctx, r.es)
+	if err != nil {
+		return err
+	}
+	defer refresh.Body.Close()
+	if refresh.IsError() {
+		return fmt.Errorf("refresh %s: %s", index, refresh.String())
+	}
+	return nil
+}
+
+// Count 统计索引中的文档数
+func (r *questionIndexRepository) Count(ctx context.Context, index string) (int64, error) {
+	res, err := esapi.CountRequest{Index: []string{index}}.Do(ctx, r.es)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return 0, fmt.Errorf("count %s: %s", index, res.String())
+	}
+	var result struct {
+		Count int64 `json:"count"`
+	}
+	if err = json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return 0, err
+	}
+	return result.Count, nil
+}
+
+// GetAliasIndices 获取别名当前指向的索引，旧版本直接以别名为名创建了实际索引时 isConcrete 为 true
+func (r *questionIndexRepository) GetAliasIndices(ctx context.Context, alias string) ([]string, bool, error) {
+	res, err := esapi.IndicesGetAliasRequest{Index: []string{alias}}.Do(ctx, r.es)
+	if err != nil {
+		return nil, false, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusNotFound {
+		return nil, false, nil
+	}
+	if res.IsError() {
+		return nil, false, fmt.Errorf("get alias %s: %s", alias, res.String())
+	}
+	var result map[string]json.RawMessage
+	if err = json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return nil, false, err
+	}
+	var indices []string
+	isConcrete := false
+	for index := range result {
+		if index == alias {
+			isConcrete = true
+			continue
+		}
+		indices = append(indices, index)
+	}
+	return indices, isConcrete, nil
+}
+
+// SwapAlias 在一次 _aliases 请求中移除旧索引的别名并指向新索引，保证切换期间查询不中断
+func (r *questionIndexRepository) SwapAlias(ctx context.Context, alias string, newIndex string, oldIndices []string, isConcrete bool) error {
+	actions := make([]map[string]interface{}, 0, len(oldIndices)+2)
+	for _, index := range oldIndices {
+		actions = append(actions, map[string]interface{}{
+			"remove": map[string]interface{}{"index": index, "alias": alias},
+		})
+	}
+	if isConcrete {
+		// 与别名同名的实际索引必须在同一请求中删除，否则无法创建别名
+		actions = append(actions, map[string]interface{}{
+			"remove_index": map[string]interface{}{"index": alias},
+		})
+	}
+	actions = append(actions, map[string]interface{}{
+		"add": map[string]interface{}{"index": newIndex, "alias": alias},
+	})
+	body, err := json.Marshal(map[string]interface{}{"actions": actions})
+	if err != nil {
+		return err
+	}
+	res, err := esapi.IndicesUpdateAliasesRequest{Body: bytes.NewReader(body)}.Do(ctx, r.es)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return fmt.Errorf("swap alias %s to %s: %s", alias, newIndex, res.String())
+	}
+	return nil
+}
+
+// ListIndices 列出指定前缀的索引
+func (r *questionIndexRepository) ListIndices(ctx context.Context, prefix string) ([]string, error) {
+	res, err := esapi.IndicesGetRequest{Index: []string{prefix + "*"}}.Do(ctx, r.es)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return nil, fmt.Errorf("list indices %s*: %s", prefix, res.String())
+	}
+	var result map[string]json.RawMessage
+	if err = json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	indices := make([]string, 0, len(result))
+	for index := range result {
+		indices = append(indices, index)
+	}
+	return indices, nil
+}
+
+// DeleteIndex 删除索引
+func (r *questionIndexRepository) DeleteIndex(ctx context.Context, index string) error {
+	res, err := esapi.IndicesDeleteRequest{Index: []string{index}}.Do(ctx, r.es)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() && res.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("delete index %s: %s", index, res.String())
+	}
+	return nil
+}
+
+// PutSynonyms 覆盖同义词集合中的全部规则，集合不存在时自动创建
+func (r *questionIndexRepository) PutSynonyms(ctx context.Context, synonyms []model.QuestionSynonym) error {
+	rules := make([]map[string]interface{}, 0, len(synonyms))
+	for _, synonym := range synonyms {
+		rules = append(rules, map[string]interface{}{
+			"id":       strconv.FormatUint(synonym.ID, 10),
+			"synonyms": synonym.Rule,
+		})
+	}
+	body, err := json.Marshal(map[string]interface{}{"synonyms_set": rules})
+	if err != nil {
+		return err
+	}
+	res, err := esapi.SynonymsPutSynonymRequest{
+		DocumentID: constant.QuestionSynonymSet,
+		Body:       bytes.NewReader(body),
+	}.Do(ctx, r.es)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return fmt.Errorf("put synonyms %s: %s", constant.QuestionSynonymSet, res.String())
+	}
+	return nil
+}
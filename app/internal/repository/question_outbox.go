@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"app/internal/model"
+	"app/pkg/constant"
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// QuestionOutboxRepository 定义了一个题目变更事件仓库接口
+type QuestionOutboxRepository interface {
+	Create(ctx context.Context, events []model.QuestionOutbox) error
+	ListPending(ctx context.Context, limit int) ([]model.QuestionOutbox, error)
+	MarkSent(ctx context.Context, ids []uint64) error
+	MarkRetry(ctx context.Context, event *model.QuestionOutbox, lastError string) error
+	DeleteSentBefore(ctx context.Context, t time.Time) (int64, error)
+	GetMaxId(ctx context.Context) (uint64, error)
+	ReplayAfter(ctx context.Context, id uint64) (int64, error)
+}
+
+// NewQuestionOutboxRepository 创建一个新的题目变更事件仓库
+func NewQuestionOutboxRepository(
+	repository *Repository,
+) QuestionOutboxRepository {
+	return &questionOutboxRepository{
+		Repository: repository,
+	}
+}
+
+type questionOutboxRepository struct {
+	*Repository
+}
+
+// Create 写入变更事件，需要和题目修改在同一事务中调用
+func (r *questionOutboxRepository) Create(ctx context.Context, events []model.QuestionOutbox) error {
+	if len(events) == 0 {
+		return nil
+	}
+	if err := r.DB(ctx).Create(&events).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// ListPending 获取到达投递时间的待投递事件，并加锁避免多个中继重复投递，需要在事务中调用
+func (r *questionOutboxRepository) ListPending(ctx context.Context, limit int) ([]model.QuestionOutbox, error) {
+	var events []model.QuestionOutbox
+	if err := r.DB(ctx).Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status = ? AND next_retry_time <= ?", constant.QuestionOutboxStatusPending, time.Now()).
+		Order("id ASC").Limit(limit).Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// MarkSent 标记事件已投递
+func (r *questionOutboxRepository) MarkSent(ctx context.Context, ids []uint64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := r.DB(ctx).Model(&model.QuestionOutbox{}).Where("id IN ?", ids).
+		Update("status", constant.QuestionOutboxStatusSent).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// MarkRetry 记录投递失败，按失败次数指数退避，超过最大重试次数后标记为失败
+func (r *questionOutboxRepository) MarkRetry(ctx context.Context, event *model.QuestionOutbox, lastError string) error {
+	retryCount := event.RetryCount + 1
+	status := constant.QuestionOutboxStatusPending
+	if retryCount >= constant.QuestionOutboxMaxRetry {
+		status = constant.QuestionOutboxStatusFailed
+	}
+	if len(lastError) > 512 {
+		lastError = lastError[:512]
+	}
+	// 第 1 次重试等待 1s，之后逐次翻倍：1s, 2s, 4s ... 最长 10 分钟
+	backoff := min(time.Duration(1<<min(retryCount-1, 10))*time.Second, 10*time.Minute)
+	if err := r.DB(ctx).Model(&model.QuestionOutbox{}).Where("id = ?", event.ID).Updates(map[string]interface{}{
+		"status":          status,
+		"retry_count":     gorm.Expr("retry_count + 1"),
+		"next_retry_time": time.Now().Add(backoff),
+		"last_error":      lastError,
+	}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetMaxId 获取当前最大的事件ID，没有事件时返回 0
+func (r *questionOutboxRepository) GetMaxId(ctx context.Context) (uint64, error) {
+	var id uint64
+	if err := r.DB(ctx).Model(&model.QuestionOutbox{}).Select("COALESCE(MAX(id), 0)").Scan(&id).Error; err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// ReplayAfter 将ID大于 id 的已投递事件重新标记为待投递，由中继重新投递
+func (r *questionOutboxRepository) ReplayAfter(ctx context.Context, id uint64) (int64, error) {
+	result := r.DB(ctx).Model(&model.QuestionOutbox{}).
+		Where("id > ? AND status = ?", id, constant.QuestionOutboxStatusSent).
+		Updates(map[string]interface{}{
+			"status":          constant.QuestionOutboxStatusPending,
+			"retry_count":     0,
+			"next_retry_time": time.Now(),
+		})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+// DeleteSentBefore 清理指定时间之前已投递的事件
+func (r *questionOutboxRepository) DeleteSentBefore(ctx context.Context, t time.Time) (int64, error) {
+	result := r.DB(ctx).Where("status = ? AND update_time < ?", constant.QuestionOutboxStatusSent, t).
+		Delete(&model.QuestionOutbox{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	v1 "app/api/v1"
+	"app/internal/model"
+	"context"
+	"errors"
+	"gorm.io/gorm"
+)
+
+// QuestionRevisionRepository 定义了一个题目修订记录仓库接口
+type QuestionRevisionRepository interface {
+	Create(ctx context.Context, revision *model.QuestionRevision) error
+	GetLatestVersion(ctx context.Context, questionId uint64) (int, error)
+	GetByVersion(ctx context.Context, questionId uint64, version int) (*model.QuestionRevision, error)
+	ListByQuestionId(ctx context.Context, questionId uint64, current int, pageSize int) ([]model.QuestionRevision, int, error)
+}
+
+// NewQuestionRevisionRepository 创建一个新的题目修订记录仓库
+func NewQuestionRevisionRepository(
+	repository *Repository,
+) QuestionRevisionRepository {
+	return &questionRevisionRepository{
+		Repository: repository,
+	}
+}
+
+type questionRevisionRepository struct {
+	*Repository
+}
+
+// Create 添加修订记录
+func (r *questionRevisionRepository) Create(ctx context.Context, revision *model.QuestionRevision) error {
+	if err := r.DB(ctx).Create(revision).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetLatestVersion 获取题目最新的版本号，没有修订记录时返回 0
+func (r *questionRevisionRepository) GetLatestVersion(ctx context.Context, questionId uint64) (int, error) {
+	var version int
+	if err := r.DB(ctx).Model(&model.QuestionRevision{}).
+		Where("question_id = ?", questionId).
+		Select("COALESCE(MAX(version), 0)").Scan(&version).Error; err != nil {
+		return 0, err
+	}
+	return version, nil
+}
+
+// GetByVersion 获取题目指定版本的修订记录
+func (r *questionRevisionRepository) GetByVersion(ctx context.Context, questionId uint64, version int) (*model.QuestionRevision, error) {
+	var revision model.QuestionRevision
+	if err := r.DB(ctx).Where("question_id = ? AND version = ?", questionId, version).First(&revision).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, v1.ErrNotFound
+		}
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// ListByQuestionId 分页获取题目的修订记录，按版本号倒序
+func (r *questionRevisionRepository) ListByQuestionId(ctx context.Context, questionId uint64, current int, pageSize int) ([]model.QuestionRevision, int, error) {
+	var revisions []model.QuestionRevision
+	var total int64
+	db := r.DB(ctx).Model(&model.QuestionRevision{}).Where("question_id = ?", questionId)
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (current - 1) * pageSize
+	if err := db.Order("version DESC").Offset(offset).Limit(pageSize).Find(&revisions).Error; err != nil {
+		return nil, 0, err
+	}
+	return revisions, int(total), nil
+}
@@ -0,0 +1,233 @@
+package repository
+
+import (
+	v1 "app/api/v1"
+	"app/internal/model"
+	"app/pkg/constant"
+	"app/pkg/utils"
+	"context"
+	"github.com/alibaba/sentinel-golang/api"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// questionSearchEsTimeout ES 搜索超时时间，超时按失败计入熔断统计并回退到数据库
+const questionSearchEsTimeout = 3 * time.Second
+
+// QuestionSearchResult 题目搜索结果
+type QuestionSearchResult struct {
+	Questions []v1.Question
+	Total     int
+	TagFacets []v1.TagFacet
+	// 实际提供结果的搜索后端
+	Backend string
+}
+
+// QuestionSearcher 定义了题目搜索接口，过滤、排序和分页规则在各实现中保持一致
+type QuestionSearcher interface {
+	// 根据请求搜索题目
+	Search(ctx context.Context, req *v1.QuestionRequest) (*QuestionSearchResult, error)
+}
+
+// NewQuestionSearcher 创建题目搜索实例，优先使用 ES，ES 出错或熔断期间回退到数据库
+func NewQuestionSearcher(
+	repository *Repository,
+	questionRepository QuestionRepository,
+) QuestionSearcher {
+	return &failoverQuestionSearcher{
+		Repository: repository,
+		es:         NewEsQuestionSearcher(questionRepository),
+		db:         NewDBQuestionSearcher(repository),
+	}
+}
+
+// failoverQuestionSearcher 通过熔断在 ES 和数据库之间切换
+type failoverQuestionSearcher struct {
+	*Repository
+	es QuestionSearcher
+	db QuestionSearcher
+}
+
+// Search 熔断关闭时查询 ES，失败时记录错误并改查数据库；熔断打开期间直接查询数据库
+func (s *failoverQuestionSearcher) Search(ctx context.Context, req *v1.QuestionRequest) (*QuestionSearchResult, error) {
+	entry, blockErr := api.Entry(constant.QuestionSearchEsResource)
+	if blockErr != nil {
+		return s.db.Search(ctx, req)
+	}
+	result, err := s.es.Search(ctx, req)
+	if err != nil {
+		api.TraceError(entry, err)
+		entry.Exit()
+		s.logger.Warn("search question by elasticsearch error, fallback to database", zap.Error(err))
+		return s.db.Search(ctx, req)
+	}
+	entry.Exit()
+	return result, nil
+}
+
+// NewEsQuestionSearcher 创建基于 ES 的题目搜索
+func NewEsQuestionSearcher(questionRepository QuestionRepository) QuestionSearcher {
+	return &esQuestionSearcher{questionRepository: questionRepository}
+}
+
+type esQuestionSearcher struct {
+	questionRepository QuestionRepository
+}
+
+// Search 使用 ES 搜索题目，返回高亮片段和标签聚合
+func (s *esQuestionSearcher) Search(ctx context.Context, req *v1.QuestionRequest) (*QuestionSearchResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, questionSearchEsTimeout)
+	defer cancel()
+	questions, total, tagFacets, err := s.questionRepository.GetEsQuestion(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return &QuestionSearchResult{
+		Questions: questions,
+		Total:     total,
+		TagFacets: tagFacets,
+		Backend:   constant.QuestionSearchBackendEs,
+	}, nil
+}
+
+// NewDBQuestionSearcher 创建基于数据库的题目搜索，只使用 LIKE 匹配，MySQL 和 SQLite 均可使用
+func NewDBQuestionSearcher(repository *Repository) QuestionSearcher {
+	return &dbQuestionSearcher{Repository: repository}
+}
+
+type dbQuestionSearcher struct {
+	*Repository
+}
+
+// Search 使用数据库搜索题目，关键词在标题、内容和答案中模糊匹配，不返回高亮和标签聚合
+func (s *dbQuestionSearcher) Search(ctx context.Context, req *v1.QuestionRequest) (*QuestionSearchResult, error) {
+	result := &QuestionSearchResult{
+		Questions: []v1.Question{},
+		Backend:   constant.QuestionSearchBackendDB,
+	}
+	query, ok := s.query(ctx, req)
+	if !ok {
+		return result, nil
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+	result.Total = int(total)
+	if total == 0 {
+		return result, nil
+	}
+
+	size, current := questionSearchPage(req)
+	var questions []model.Question
+	if err := query.Order(questionSearchOrder(req)).
+		Limit(size).
+		Offset(size * (current - 1)).
+		Find(&questions).Error; err != nil {
+		return nil, err
+	}
+	for _, q := range questions {
+		id := utils.Uint64TOString(q.ID)
+		userId := utils.Uint64TOString(q.UserID)
+		isDelete := q.IsDelete
+		reviewStatus := q.ReviewStatus
+		createTime, editTime, updateTime := q.CreateTime, q.EditTime, q.UpdateTime
+		result.Questions = append(result.Questions, v1.Question{
+			Answer:       q.Answer,
+			Content:      q.Content,
+			CreateTime:   &createTime,
+			EditTime:     &editTime,
+			ID:           &id,
+			IsDelete:     &isDelete,
+			Tags:         q.Tags,
+			Title:        q.Title,
+			UpdateTime:   &updateTime,
+			UserID:       &userId,
+			ReviewStatus: &reviewStatus,
+		})
+	}
+	return result, nil
+}
+
+// query 构造与 ES 查询一致的过滤条件，返回的查询可重复使用；ID 参数不合法时返回 false 表示没有结果
+func (s *dbQuestionSearcher) query(ctx context.Context, req *v1.QuestionRequest) (*gorm.DB, bool) {
+	db := s.DB(ctx).Model(&model.Question{}).Where("question.is_delete = ?", 0)
+	if req.SearchText != nil {
+		// 数据库不支持短语语法，去掉引号后整体模糊匹配
+		if text := strings.TrimSpace(strings.ReplaceAll(*req.SearchText, `"`, "")); text != "" {
+			like := "%" + escapeLike(text) + "%"
+			db = db.Where("(question.title LIKE ? ESCAPE '!' OR question.content LIKE ? ESCAPE '!' OR question.answer LIKE ? ESCAPE '!')",
+				like, like, like)
+		}
+	}
+	// 标签以 JSON 数组保存，带引号匹配，避免 java 命中 javascript
+	for _, tag := range req.Tags {
+		db = db.Where("question.tags LIKE ? ESCAPE '!'", "%"+escapeLike(strconv.Quote(tag))+"%")
+	}
+	if req.ID != nil && *req.ID != "" {
+		id, err := utils.StringToUint64(*req.ID)
+		if err != nil {
+			return nil, false
+		}
+		db = db.Where("question.id = ?", id)
+	}
+	if req.UserID != nil && *req.UserID != "" {
+		userId, err := utils.StringToUint64(*req.UserID)
+		if err != nil {
+			return nil, false
+		}
+		db = db.Where("question.user_id = ?", userId)
+	}
+	if req.QuestionBankID != nil && *req.QuestionBankID != "" {
+		bankId, err := utils.StringToUint64(*req.QuestionBankID)
+		if err != nil {
+			return nil, false
+		}
+		db = db.Where("question.id IN (?)", s.DB(ctx).Model(&model.QuestionBankQuestion{}).
+			Select("question_id").
+			Where("question_bank_id = ?", bankId))
+	}
+	if req.ReviewStatus != nil {
+		db = db.Where("question.review_status = ?", *req.ReviewStatus)
+	}
+	return db.Session(&gorm.Session{}), true
+}
+
+// questionSearchPage 获取分页参数，默认每页 12 条
+func questionSearchPage(req *v1.QuestionRequest) (int, int) {
+	size, current := 12, 1
+	if req.PageSize != nil && req.Current != nil {
+		size, current = *req.PageSize, *req.Current
+	}
+	if size <= 0 {
+		size = 12
+	}
+	if current <= 0 {
+		current = 1
+	}
+	return size, current
+}
+
+// questionSearchOrder 获取排序条件，未指定时按 ID 倒序
+func questionSearchOrder(req *v1.QuestionRequest) string {
+	if req.SortOrder == nil || req.SortField == nil {
+		return "question.id desc"
+	}
+	field := "question.update_time"
+	if *req.SortField == "createTime" {
+		field = "question.create_time"
+	}
+	if *req.SortOrder == "ascend" {
+		return field + " asc"
+	}
+	return field + " desc"
+}
+
+// escapeLike 转义 LIKE 中的通配符，配合 ESCAPE '!' 使用
+func escapeLike(s string) string {
+	return strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(s)
+}
@@ -0,0 +1,222 @@
+package repository
+
+import (
+	v1 "app/api/v1"
+	"app/internal/model"
+	"app/pkg/constant"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"sync"
+	"time"
+)
+
+// QuestionSearchLogRepository 定义了题目搜索日志仓库接口
+type QuestionSearchLogRepository interface {
+	// 异步记录一次搜索，缓冲区满时丢弃，不阻塞搜索请求
+	RecordSearch(log *model.QuestionSearchLog)
+	// 异步记录一次结果点击
+	RecordClick(click *model.QuestionSearchClick)
+	// 统计搜索次数最多的搜索词
+	ListTopQuery(ctx context.Context, since time.Time, limit int) ([]v1.QuestionSearchQueryStat, error)
+	// 统计无结果次数最多的搜索词
+	ListZeroResultQuery(ctx context.Context, since time.Time, limit int) ([]v1.QuestionZeroResultStat, error)
+	// 统计搜索词的点击率
+	ListClickThrough(ctx context.Context, since time.Time, limit int) ([]v1.QuestionClickThroughStat, error)
+	// 获取最近一段时间的热门搜索，结果短暂缓存
+	ListTrending(ctx context.Context) ([]v1.QuestionTrendingSearch, error)
+	// 删除指定时间之前的搜索和点击日志
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// NewQuestionSearchLogRepository 创建题目搜索日志仓库，并启动后台批量写入；返回的 cleanup 会写完缓冲区中的日志
+func NewQuestionSearchLogRepository(
+	repository *Repository,
+) (QuestionSearchLogRepository, func()) {
+	r := &questionSearchLogRepository{
+		Repository: repository,
+		entries:    make(chan interface{}, constant.QuestionSearchLogBufferSize),
+		done:       make(chan struct{}),
+	}
+	go r.run()
+	return r, r.close
+}
+
+type questionSearchLogRepository struct {
+	*Repository
+	entries chan interface{}
+	done    chan struct{}
+	mu      sync.RWMutex
+	closed  bool
+}
+
+// RecordSearch 异步记录一次搜索
+func (r *questionSearchLogRepository) RecordSearch(log *model.QuestionSearchLog) {
+	r.enqueue(log)
+}
+
+// RecordClick 异步记录一次结果点击
+func (r *questionSearchLogRepository) RecordClick(click *model.QuestionSearchClick) {
+	r.enqueue(click)
+}
+
+// enqueue 非阻塞地放入缓冲区
+func (r *questionSearchLogRepository) enqueue(entry interface{}) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.closed {
+		return
+	}
+	select {
+	case r.entries <- entry:
+	default:
+		r.logger.Warn("question search log buffer is full, drop entry")
+	}
+}
+
+// run 攒批写入，达到批量大小或间隔时间到达时写入数据库
+func (r *questionSearchLogRepository) run() {
+	defer close(r.done)
+	ticker := time.NewTicker(constant.QuestionSearchLogFlushInterval)
+	defer ticker.Stop()
+
+	var logs []*model.QuestionSearchLog
+	var clicks []*model.QuestionSearchClick
+	flush := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if len(logs) > 0 {
+			if err := r.db.WithContext(ctx).CreateInBatches(logs, constant.QuestionSearchLogBatchSize).Error; err != nil {
+				r.logger.Error("write question search log error", zap.Int("count", len(logs)), zap.Error(err))
+			}
+			logs = nil
+		}
+		if len(clicks) > 0 {
+			if err := r.db.WithContext(ctx).CreateInBatches(clicks, constant.QuestionSearchLogBatchSize).Error; err != nil {
+				r.logger.Error("write question search click error", zap.Int("count", len(clicks)), zap.Error(err))
+			}
+			clicks = nil
+		}
+	}
+
+	for {
+		select {
+		case entry, ok := <-r.entries:
+			if !ok {
+				flush()
+				return
+			}
+			switch e := entry.(type) {
+			case *model.QuestionSearchLog:
+				logs = append(logs, e)
+			case *model.QuestionSearchClick:
+				clicks = append(clicks, e)
+			}
+			if len(logs)+len(clicks) >= constant.QuestionSearchLogBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// close 停止接收新日志，并等待缓冲区中的日志写完
+func (r *questionSearchLogRepository) close() {
+	r.mu.Lock()
+	if !r.closed {
+		r.closed = true
+		close(r.entries)
+	}
+	r.mu.Unlock()
+	<-r.done
+}
+
+// ListTopQuery 统计搜索次数最多的搜索词，不含只按条件筛选的空搜索
+func (r *questionSearchLogRepository) ListTopQuery(ctx context.Context, since time.Time, limit int) ([]v1.QuestionSearchQueryStat, error) {
+	stats := make([]v1.QuestionSearchQueryStat, 0)
+	err := r.DB(ctx).Model(&model.QuestionSearchLog{}).
+		Select("query, COUNT(*) AS search_count, COUNT(DISTINCT user_id) AS user_count, "+
+			"SUM(CASE WHEN result_count = 0 THEN 1 ELSE 0 END) AS zero_result_count, "+
+			"AVG(result_count) AS avg_result_count, AVG(latency_ms) AS avg_latency_ms").
+		Where("create_time >= ? AND query <> ''", since).
+		Group("query").
+		Order("search_count DESC").
+		Limit(limit).
+		Scan(&stats).Error
+	return stats, err
+}
+
+// ListZeroResultQuery 统计无结果次数最多的搜索词，用于补充题目或同义词
+func (r *questionSearchLogRepository) ListZeroResultQuery(ctx context.Context, since time.Time, limit int) ([]v1.QuestionZeroResultStat, error) {
+	stats := make([]v1.QuestionZeroResultStat, 0)
+	err := r.DB(ctx).Model(&model.QuestionSearchLog{}).
+		Select("query, COUNT(*) AS search_count, MAX(create_time) AS last_search_time").
+		Where("create_time >= ? AND query <> '' AND result_count = 0", since).
+		Group("query").
+		Order("search_count DESC").
+		Limit(limit).
+		Scan(&stats).Error
+	return stats, err
+}
+
+// ListClickThrough 按搜索次数倒序统计搜索词的点击率
+func (r *questionSearchLogRepository) ListClickThrough(ctx context.Context, since time.Time, limit int) ([]v1.QuestionClickThroughStat, error) {
+	stats := make([]v1.QuestionClickThroughStat, 0)
+	err := r.DB(ctx).Table("question_search_log AS l").
+		Select("l.query, COUNT(DISTINCT l.search_id) AS search_count, "+
+			"COUNT(DISTINCT c.search_id) AS clicked_search_count, COUNT(c.id) AS click_count, "+
+			"COUNT(DISTINCT c.search_id) * 1.0 / COUNT(DISTINCT l.search_id) AS click_through_rate, "+
+			"COALESCE(AVG(c.position), 0) AS avg_click_position").
+		Joins("LEFT JOIN question_search_click AS c ON c.search_id = l.search_id").
+		Where("l.create_time >= ? AND l.query <> '' AND l.result_count > 0", since).
+		Group("l.query").
+		Order("search_count DESC").
+		Limit(limit).
+		Scan(&stats).Error
+	return stats, err
+}
+
+// ListTrending 获取统计窗口内有结果的热门搜索词；按搜索过的不同登录用户数排序，每个用户只计一次，
+// 未登录的搜索不参与统计，避免单个客户端反复搜索把搜索词刷上热门
+func (r *questionSearchLogRepository) ListTrending(ctx context.Context) ([]v1.QuestionTrendingSearch, error) {
+	trending := make([]v1.QuestionTrendingSearch, 0)
+	cached, err := r.rdb.Get(ctx, constant.QuestionTrendingSearchRedisKey).Result()
+	if err == nil {
+		if err = json.Unmarshal([]byte(cached), &trending); err == nil {
+			return trending, nil
+		}
+	} else if !errors.Is(err, redis.Nil) {
+		r.logger.Warn("get trending search cache error", zap.Error(err))
+	}
+
+	if err = r.DB(ctx).Model(&model.QuestionSearchLog{}).
+		Select("query, COUNT(DISTINCT user_id) AS search_count").
+		Where("create_time >= ? AND query <> '' AND result_count > 0 AND user_id IS NOT NULL", time.Now().Add(-constant.QuestionTrendingSearchWindow)).
+		Group("query").
+		Having("COUNT(DISTINCT user_id) >= ?", constant.QuestionTrendingSearchMinUsers).
+		Order("search_count DESC").
+		Limit(constant.QuestionTrendingSearchSize).
+		Scan(&trending).Error; err != nil {
+		return nil, err
+	}
+	if data, err := json.Marshal(trending); err == nil {
+		r.rdb.Set(ctx, constant.QuestionTrendingSearchRedisKey, data, constant.QuestionTrendingSearchCacheTTL)
+	}
+	return trending, nil
+}
+
+// DeleteBefore 删除指定时间之前的搜索和点击日志
+func (r *questionSearchLogRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	res := r.DB(ctx).Where("create_time < ?", before).Delete(&model.QuestionSearchLog{})
+	if res.Error != nil {
+		return 0, res.Error
+	}
+	clickRes := r.DB(ctx).Where("create_time < ?", before).Delete(&model.QuestionSearchClick{})
+	if clickRes.Error != nil {
+		return 0, clickRes.Error
+	}
+	return res.RowsAffected + clickRes.RowsAffected, nil
+}
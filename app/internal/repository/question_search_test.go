@@ -0,0 +1,216 @@
+package repository
+
+import (
+	v1 "app/api/v1"
+	"app/internal/model"
+	"app/pkg/constant"
+	"app/pkg/log"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newTestDBQuestionSearcher(t *testing.T) (QuestionSearcher, *gorm.DB) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err = db.AutoMigrate(&model.Question{}); err != nil {
+		t.Fatalf("migrate question: %v", err)
+	}
+	if err = db.AutoMigrate(&model.QuestionBankQuestion{}); err != nil {
+		t.Fatalf("migrate question_bank_question: %v", err)
+	}
+	return NewDBQuestionSearcher(NewRepository(&log.Logger{Logger: zap.NewNop()}, db, nil, nil)), db
+}
+
+func TestDBQuestionSearcher_Search(t *testing.T) {
+	searcher, db := newTestDBQuestionSearcher(t)
+	str := func(s string) *string { return &s }
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	questions := []model.Question{
+		{ID: 1, Title: str("Java 反射"), Content: str("运行时获取类信息"), Tags: str(`["java"]`), UserID: 10, ReviewStatus: constant.ReviewStatusPass},
+		{ID: 2, Title: str("JavaScript 闭包"), Content: str("函数和作用域"), Tags: str(`["javascript"]`), UserID: 10, ReviewStatus: constant.ReviewStatusPass},
+		{ID: 3, Title: str("Redis 持久化"), Answer: str("RDB 和 AOF，命中率 100%"), Tags: str(`["redis","java"]`), UserID: 20, ReviewStatus: constant.ReviewStatusPass},
+		{ID: 4, Title: str("MySQL 索引"), Content: str("B+ 树"), Tags: str(`["mysql"]`), UserID: 20, ReviewStatus: constant.ReviewStatusPending},
+		{ID: 5, Title: str("user_id 的设计"), Tags: str(`["mysql"]`), UserID: 20, ReviewStatus: constant.ReviewStatusPass},
+		{ID: 6, Title: str("已删除的 Java 题目"), Tags: str(`["java"]`), UserID: 10, IsDelete: 1, ReviewStatus: constant.ReviewStatusPass},
+	}
+	for i := range questions {
+		questions[i].CreateTime = base.Add(time.Duration(i) * time.Hour)
+		questions[i].EditTime = questions[i].CreateTime
+		questions[i].UpdateTime = base.Add(time.Duration(len(questions)-i) * time.Hour)
+	}
+	if err := db.Create(&questions).Error; err != nil {
+		t.Fatalf("create questions: %v", err)
+	}
+	if err := db.Create(&[]model.QuestionBankQuestion{
+		{QuestionBankID: 100, QuestionID: 1, UserID: 10},
+		{QuestionBankID: 100, QuestionID: 3, UserID: 10},
+		{QuestionBankID: 200, QuestionID: 2, UserID: 10},
+	}).Error; err != nil {
+		t.Fatalf("create bank questions: %v", err)
+	}
+
+	intPtr := func(i int) *int { return &i }
+	tests := []struct {
+		name      string
+		req       v1.QuestionRequest
+		wantIds   []string
+		wantTotal int
+	}{
+		{
+			name:      "exclude deleted, order by id desc",
+			req:       v1.QuestionRequest{},
+			wantIds:   []string{"5", "4", "3", "2", "1"},
+			wantTotal: 5,
+		},
+		{
+			name:      "search text matches title, content and answer",
+			req:       v1.QuestionRequest{SearchText: str("java")},
+			wantIds:   []string{"2", "1"},
+			wantTotal: 2,
+		},
+		{
+			name:      "quotes are removed from search text",
+			req:       v1.QuestionRequest{SearchText: str(`"RDB 和 AOF"`)},
+			wantIds:   []string{"3"},
+			wantTotal: 1,
+		},
+		{
+			name:      "percent is matched literally",
+			req:       v1.QuestionRequest{SearchText: str("%")},
+			wantIds:   []string{"3"},
+			wantTotal: 1,
+		},
+		{
+			name:      "underscore is matched literally",
+			req:       v1.QuestionRequest{SearchText: str("_")},
+			wantIds:   []string{"5"},
+			wantTotal: 1,
+		},
+		{
+			name:      "quoted tag does not match tag prefix",
+			req:       v1.QuestionRequest{Tags: []string{"java"}},
+			wantIds:   []string{"3", "1"},
+			wantTotal: 2,
+		},
+		{
+			name:      "all tags must match",
+			req:       v1.QuestionRequest{Tags: []string{"java", "redis"}},
+			wantIds:   []string{"3"},
+			wantTotal: 1,
+		},
+		{
+			name:      "filter by user and review status",
+			req:       v1.QuestionRequest{UserID: str("20"), ReviewStatus: intPtr(constant.ReviewStatusPass)},
+			wantIds:   []string{"5", "3"},
+			wantTotal: 2,
+		},
+		{
+			name:      "filter by id",
+			req:       v1.QuestionRequest{ID: str("4")},
+			wantIds:   []string{"4"},
+			wantTotal: 1,
+		},
+		{
+			name:      "filter by question bank",
+			req:       v1.QuestionRequest{QuestionBankID: str("100")},
+			wantIds:   []string{"3", "1"},
+			wantTotal: 2,
+		},
+		{
+			name:      "invalid id returns nothing",
+			req:       v1.QuestionRequest{QuestionBankID: str("abc")},
+			wantIds:   []string{},
+			wantTotal: 0,
+		},
+		{
+			name:      "paging",
+			req:       v1.QuestionRequest{Current: intPtr(2), PageSize: intPtr(2)},
+			wantIds:   []string{"3", "2"},
+			wantTotal: 5,
+		},
+		{
+			name:      "sort by create time ascend",
+			req:       v1.QuestionRequest{SortField: str("createTime"), SortOrder: str("ascend"), PageSize: intPtr(3), Current: intPtr(1)},
+			wantIds:   []string{"1", "2", "3"},
+			wantTotal: 5,
+		},
+		{
+			name:      "sort by update time descend",
+			req:       v1.QuestionRequest{SortField: str("updateTime"), SortOrder: str("descend"), PageSize: intPtr(3), Current: intPtr(1)},
+			wantIds:   []string{"1", "2", "3"},
+			wantTotal: 5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := searcher.Search(context.Background(), &tt.req)
+			if err != nil {
+				t.Fatalf("Search() error = %v", err)
+			}
+			if result.Backend != constant.QuestionSearchBackendDB {
+				t.Errorf("backend = %s, want %s", result.Backend, constant.QuestionSearchBackendDB)
+			}
+			if result.Total != tt.wantTotal {
+				t.Errorf("total = %d, want %d", result.Total, tt.wantTotal)
+			}
+			ids := make([]string, 0, len(result.Questions))
+			for _, q := range result.Questions {
+				ids = append(ids, *q.ID)
+			}
+			if len(ids) != len(tt.wantIds) {
+				t.Fatalf("ids = %v, want %v", ids, tt.wantIds)
+			}
+			for i := range ids {
+				if ids[i] != tt.wantIds[i] {
+					t.Fatalf("ids = %v, want %v", ids, tt.wantIds)
+				}
+			}
+		})
+	}
+}
+
+func TestQuestionSearchOrder(t *testing.T) {
+	str := func(s string) *string { return &s }
+	tests := []struct {
+		field, order *string
+		want         string
+	}{
+		{nil, nil, "question.id desc"},
+		{str("createTime"), nil, "question.id desc"},
+		{str("createTime"), str("ascend"), "question.create_time asc"},
+		{str("createTime"), str("descend"), "question.create_time desc"},
+		{str("updateTime"), str("ascend"), "question.update_time asc"},
+		{str("title; drop table question"), str("descend"), "question.update_time desc"},
+	}
+	for _, tt := range tests {
+		if got := questionSearchOrder(&v1.QuestionRequest{SortField: tt.field, SortOrder: tt.order}); got != tt.want {
+			t.Errorf("questionSearchOrder(%v, %v) = %q, want %q", tt.field, tt.order, got, tt.want)
+		}
+	}
+}
+
+func TestEscapeLike(t *testing.T) {
+	tests := []struct {
+		s    string
+		want string
+	}{
+		{"java", "java"},
+		{"100%", "100!%"},
+		{"user_id", "user!_id"},
+		{"wow!", "wow!!"},
+		{"!%_", "!!!%!_"},
+	}
+	for _, tt := range tests {
+		if got := escapeLike(tt.s); got != tt.want {
+			t.Errorf("escapeLike(%q) = %q, want %q", tt.s, got, tt.want)
+		}
+	}
+}
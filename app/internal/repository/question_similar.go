@@ -0,0 +1,90 @@
+package repository
+
+import (
+	v1 "app/api/v1"
+	"app/pkg/constant"
+	"app/pkg/utils"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// similarQuestionResponse ES 相似题目检索返回结果
+type similarQuestionResponse struct {
+	Hits struct {
+		Hits []struct {
+			Score  float64 `json:"_score"`
+			Source struct {
+				Id    int64    `json:"id"`
+				Title string   `json:"title"`
+				Tags  []string `json:"tags"`
+			} `json:"_source"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+// SearchSimilarQuestion 按文本向量 k-NN 检索未删除的题目，onlyPass 为 true 时只返回审核通过的题目
+func (r *questionRepository) SearchSimilarQuestion(ctx context.Context, vector []float32, size int, excludeId uint64, onlyPass bool) ([]v1.SimilarQuestionVO, error) {
+	similar := make([]v1.SimilarQuestionVO, 0, size)
+	if len(vector) == 0 {
+		return similar, nil
+	}
+	filter := map[string]interface{}{
+		"filter": []map[string]interface{}{
+			{"term": map[string]interface{}{"is_delete": 0}},
+		},
+	}
+	if onlyPass {
+		filter["filter"] = append(filter["filter"].([]map[string]interface{}),
+			map[string]interface{}{"term": map[string]interface{}{"review_status": constant.ReviewStatusPass}})
+	}
+	if excludeId != 0 {
+		filter["must_not"] = map[string]interface{}{"term": map[string]interface{}{"id": excludeId}}
+	}
+	query := map[string]interface{}{
+		"size":    size,
+		"_source": []string{"id", "title", "tags"},
+		"knn": map[string]interface{}{
+			"field":          "embedding",
+			"query_vector":   vector,
+			"k":              size,
+			"num_candidates": size * 10,
+			"filter":         map[string]interface{}{"bool": filter},
+		},
+	}
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(query); err != nil {
+		return nil, err
+	}
+	res, err := r.es.Search(
+		r.es.Search.WithContext(ctx),
+		r.es.Search.WithIndex(constant.QuestionIndexAlias),
+		r.es.Search.WithBody(&buf),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return nil, fmt.Errorf("search similar question: %s", res.String())
+	}
+	var rr similarQuestionResponse
+	if err = json.NewDecoder(res.Body).Decode(&rr); err != nil {
+		return nil, err
+	}
+	for _, hit := range rr.Hits.Hits {
+		tags := hit.Source.Tags
+		if tags == nil {
+			tags = []string{}
+		}
+		similar = append(similar, v1.SimilarQuestionVO{
+			ID:      utils.Int64TOString(hit.Source.Id),
+			Title:   hit.Source.Title,
+			TagList: tags,
+			// cosine 相似度的评分为 (1 + cos) / 2，还原为余弦相似度
+			Similarity: 2*hit.Score - 1,
+		})
+	}
+	return similar, nil
+}
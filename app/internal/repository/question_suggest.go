@@ -0,0 +1,165 @@
+package repository
+
+import (
+	v1 "app/api/v1"
+	"app/pkg/constant"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"strings"
+	"unicode"
+)
+
+// questionSuggestTagSize 搜索建议返回的标签数量
+const questionSuggestTagSize = 5
+
+// questionSuggestResponse ES 搜索建议返回结果
+type questionSuggestResponse struct {
+	Hits struct {
+		Hits []struct {
+			Source struct {
+				Id    int64  `json:"id"`
+				Title string `json:"title"`
+			} `json:"_source"`
+		} `json:"hits"`
+	} `json:"hits"`
+	Aggregations struct {
+		All struct {
+			Public struct {
+				Tags struct {
+					Buckets []struct {
+						Key      string `json:"key"`
+						DocCount int    `json:"doc_count"`
+					} `json:"buckets"`
+				} `json:"tags"`
+			} `json:"public"`
+		} `json:"all"`
+	} `json:"aggregations"`
+}
+
+// SuggestQuestion 根据前缀补全审核通过的题目标题，并返回以该前缀开头的热门标签
+func (r *questionRepository) SuggestQuestion(ctx context.Context, prefix string, size int) (*v1.QuestionSuggestVO, error) {
+	cacheKey := constant.GetQuestionSuggestRedisKey(strings.ToLower(prefix), size)
+	cached, err := r.rdb.Get(ctx, cacheKey).Result()
+	if err == nil {
+		var suggest v1.QuestionSuggestVO
+		if err = json.Unmarshal([]byte(cached), &suggest); err == nil {
+			return &suggest, nil
+		}
+	} else if !errors.Is(err, redis.Nil) {
+		r.logger.Warn("get question suggest cache error: " + err.Error())
+	}
+
+	publicFilter := []map[string]interface{}{
+		{"term": map[string]interface{}{"is_delete": 0}},
+		{"term": map[string]interface{}{"review_status": constant.ReviewStatusPass}},
+	}
+	query := map[string]interface{}{
+		"size":    size,
+		"_source": []string{"id", "title"},
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must": map[string]interface{}{
+					"multi_match": map[string]interface{}{
+						"query": prefix,
+						"type":  "bool_prefix",
+						"fields": []string{
+							"title_suggest",
+							"title_suggest._2gram",
+							"title_suggest._3gram",
+						},
+					},
+				},
+				"filter": publicFilter,
+			},
+		},
+		// 标签不受标题匹配影响，在所有公开题目中统计
+		"aggs": map[string]interface{}{
+			"all": map[string]interface{}{
+				"global": map[string]interface{}{},
+				"aggs": map[string]interface{}{
+					"public": map[string]interface{}{
+						"filter": map[string]interface{}{
+							"bool": map[string]interface{}{"filter": publicFilter},
+						},
+						"aggs": map[string]interface{}{
+							"tags": map[string]interface{}{
+								"terms": map[string]interface{}{
+									"field":   "tags",
+									"include": prefixRegexp(prefix),
+									"size":    questionSuggestTagSize,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	var buf bytes.Buffer
+	if err = json.NewEncoder(&buf).Encode(query); err != nil {
+		return nil, err
+	}
+	res, err := r.es.Search(
+		r.es.Search.WithContext(ctx),
+		r.es.Search.WithIndex(constant.QuestionIndexAlias),
+		r.es.Search.WithBody(&buf),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return nil, fmt.Errorf("suggest question: %s", res.String())
+	}
+	var rr questionSuggestResponse
+	if err = json.NewDecoder(res.Body).Decode(&rr); err != nil {
+		return nil, err
+	}
+
+	suggest := &v1.QuestionSuggestVO{
+		Questions: make([]v1.QuestionSuggestItem, 0, len(rr.Hits.Hits)),
+		Tags:      make([]v1.QuestionSuggestTag, 0, len(rr.Aggregations.All.Public.Tags.Buckets)),
+	}
+	for _, hit := range rr.Hits.Hits {
+		suggest.Questions = append(suggest.Questions, v1.QuestionSuggestItem{
+			ID:    fmt.Sprintf("%d", hit.Source.Id),
+			Title: hit.Source.Title,
+		})
+	}
+	for _, bucket := range rr.Aggregations.All.Public.Tags.Buckets {
+		suggest.Tags = append(suggest.Tags, v1.QuestionSuggestTag{
+			Tag:   bucket.Key,
+			Count: bucket.DocCount,
+		})
+	}
+
+	if data, err := json.Marshal(suggest); err == nil {
+		r.rdb.Set(ctx, cacheKey, data, constant.QuestionSuggestCacheTTL)
+	}
+	return suggest, nil
+}
+
+// prefixRegexp 构造匹配以 prefix 开头的 Lucene 正则，字母不区分大小写，特殊字符转义
+func prefixRegexp(prefix string) string {
+	var sb strings.Builder
+	for _, c := range prefix {
+		switch {
+		case unicode.IsLetter(c) && unicode.ToLower(c) != unicode.ToUpper(c):
+			sb.WriteString("[")
+			sb.WriteRune(unicode.ToLower(c))
+			sb.WriteRune(unicode.ToUpper(c))
+			sb.WriteString("]")
+		case strings.ContainsRune(`.?+*|{}[]()"\#@&<>~`, c):
+			sb.WriteRune('\\')
+			sb.WriteRune(c)
+		default:
+			sb.WriteRune(c)
+		}
+	}
+	sb.WriteString(".*")
+	return sb.String()
+}
@@ -0,0 +1,23 @@
+package repository
+
+import "testing"
+
+func TestPrefixRegexp(t *testing.T) {
+	tests := []struct {
+		prefix string
+		want   string
+	}{
+		{"", ".*"},
+		{"Go", "[gG][oO].*"},
+		{"c++", `[cC]\+\+.*`},
+		{"a.b*", `[aA]\.[bB]\*.*`},
+		{"redis 持久化", "[rR][eE][dD][iI][sS] 持久化.*"},
+		{"k8s", "[kK]8[sS].*"},
+		{`"(x)"`, `\"\([xX]\)\".*`},
+	}
+	for _, tt := range tests {
+		if got := prefixRegexp(tt.prefix); got != tt.want {
+			t.Errorf("prefixRegexp(%q) = %q, want %q", tt.prefix, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	v1 "app/api/v1"
+	"app/internal/model"
+	"context"
+	"errors"
+	"gorm.io/gorm"
+)
+
+// QuestionSynonymRepository 定义了一个搜索同义词仓库接口
+type QuestionSynonymRepository interface {
+	Create(ctx context.Context, synonym *model.QuestionSynonym) error
+	Update(ctx context.Context, synonym *model.QuestionSynonym) error
+	DeleteById(ctx context.Context, id uint64) error
+	GetById(ctx context.Context, id uint64) (*model.QuestionSynonym, error)
+	ListByPage(ctx context.Context, searchText string, current int, pageSize int) ([]model.QuestionSynonym, int, error)
+	ListAll(ctx context.Context) ([]model.QuestionSynonym, error)
+}
+
+// NewQuestionSynonymRepository 创建一个新的搜索同义词仓库
+func NewQuestionSynonymRepository(
+	repository *Repository,
+) QuestionSynonymRepository {
+	return &questionSynonymRepository{
+		Repository: repository,
+	}
+}
+
+type questionSynonymRepository struct {
+	*Repository
+}
+
+// Create 添加同义词规则
+func (r *questionSynonymRepository) Create(ctx context.Context, synonym *model.QuestionSynonym) error {
+	return r.DB(ctx).Create(synonym).Error
+}
+
+// Update 修改同义词规则
+func (r *questionSynonymRepository) Update(ctx context.Context, synonym *model.QuestionSynonym) error {
+	return r.DB(ctx).Model(synonym).Update("rule", synonym.Rule).Error
+}
+
+// DeleteById 删除同义词规则
+func (r *questionSynonymRepository) DeleteById(ctx context.Context, id uint64) error {
+	return r.DB(ctx).Delete(&model.QuestionSynonym{}, id).Error
+}
+
+// GetById 根据ID获取同义词规则
+func (r *questionSynonymRepository) GetById(ctx context.Context, id uint64) (*model.QuestionSynonym, error) {
+	var synonym model.QuestionSynonym
+	if err := r.DB(ctx).Where("id = ?", id).First(&synonym).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, v1.ErrNotFound
+		}
+		return nil, err
+	}
+	return &synonym, nil
+}
+
+// ListByPage 分页获取同义词规则，按ID倒序
+func (r *questionSynonymRepository) ListByPage(ctx context.Context, searchText string, current int, pageSize int) ([]model.QuestionSynonym, int, error) {
+	var synonyms []model.QuestionSynonym
+	var total int64
+	db := r.DB(ctx).Model(&model.QuestionSynonym{})
+	if searchText != "" {
+		db = db.Where("rule LIKE ?", "%"+searchText+"%")
+	}
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (current - 1) * pageSize
+	if err := db.Order("id DESC").Offset(offset).Limit(pageSize).Find(&synonyms).Error; err != nil {
+		return nil, 0, err
+	}
+	return synonyms, int(total), nil
+}
+
+// ListAll 获取全部同义词规则，用于推送到 ES
+func (r *questionSynonymRepository) ListAll(ctx context.Context) ([]model.QuestionSynonym, error) {
+	var synonyms []model.QuestionSynonym
+	if err := r.DB(ctx).Order("id").Find(&synonyms).Error; err != nil {
+		return nil, err
+	}
+	return synonyms, nil
+}
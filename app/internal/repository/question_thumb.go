@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"app/internal/model"
+	"app/pkg/constant"
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// QuestionThumbRepository 定义了一个题目点赞仓库接口
+type QuestionThumbRepository interface {
+	GetByQuestionIdAndUserId(ctx context.Context, questionId uint64, userId uint64) (*model.QuestionThumb, error)
+	Create(ctx context.Context, thumb *model.QuestionThumb) (bool, error)
+	DeleteById(ctx context.Context, id uint64) (bool, error)
+	GetThumbQuestionIds(ctx context.Context, userId uint64, questionIds []uint64) (map[uint64]bool, error)
+	IncrThumbNum(ctx context.Context, questionId uint64, delta int64) error
+	FlushThumbNum(ctx context.Context) error
+}
+
+// NewQuestionThumbRepository 创建一个新的题目点赞仓库
+func NewQuestionThumbRepository(
+	repository *Repository,
+) QuestionThumbRepository {
+	return &questionThumbRepository{
+		Repository: repository,
+	}
+}
+
+type questionThumbRepository struct {
+	*Repository
+}
+
+// GetByQuestionIdAndUserId 获取用户对题目的点赞记录，不存在时返回 nil
+func (r *questionThumbRepository) GetByQuestionIdAndUserId(ctx context.Context, questionId uint64, userId uint64) (*model.QuestionThumb, error) {
+	var thumb model.QuestionThumb
+	if err := r.DB(ctx).Where("question_id = ? AND user_id = ?", questionId, userId).First(&thumb).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &thumb, nil
+}
+
+// Create 添加点赞记录，记录已存在时不做修改，返回是否新增了记录
+func (r *questionThumbRepository) Create(ctx context.Context, thumb *model.QuestionThumb) (bool, error) {
+	result := r.DB(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(thumb)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// DeleteById 删除点赞记录，返回是否删除了记录
+func (r *questionThumbRepository) DeleteById(ctx context.Context, id uint64) (bool, error) {
+	result := r.DB(ctx).Delete(&model.QuestionThumb{}, id)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetThumbQuestionIds 获取用户在给定题目中点赞过的题目
+func (r *questionThumbRepository) GetThumbQuestionIds(ctx context.Context, userId uint64, questionIds []uint64) (map[uint64]bool, error) {
+	result := make(map[uint64]bool)
+	if len(questionIds) == 0 {
+		return result, nil
+	}
+	var ids []uint64
+	if err := r.DB(ctx).Model(&model.QuestionThumb{}).
+		Where("user_id = ? AND question_id IN ?", userId, questionIds).
+		Pluck("question_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		result[id] = true
+	}
+	return result, nil
+}
+
+// IncrThumbNum 累加题目点赞数增量
+func (r *questionThumbRepository) IncrThumbNum(ctx context.Context, questionId uint64, delta int64) error {
+	return incrCounter(ctx, r.Repository, constant.QuestionThumbNumRedisKey, questionId, delta)
+}
+
+// FlushThumbNum 将点赞数增量刷回数据库
+func (r *questionThumbRepository) FlushThumbNum(ctx context.Context) error {
+	return flushCounter(ctx, r.Repository, constant.QuestionThumbNumRedisKey, &model.Question{}, "thumb_num")
+}
@@ -124,8 +124,14 @@ func NewRedis(conf *viper.Viper) *redis.Client {
 	return rdb
 }
 func NewElasticsearch(conf *viper.Viper) *elasticsearch.Client {
+	addresses := conf.GetStringSlice("data.elasticsearch.addresses")
+	if len(addresses) == 0 {
+		addresses = []string{"http://localhost:9200"}
+	}
 	es, err := elasticsearch.NewClient(elasticsearch.Config{
-		Addresses: []string{"http://localhost:9200"},
+		Addresses: addresses,
+		Username:  conf.GetString("data.elasticsearch.username"),
+		Password:  conf.GetString("data.elasticsearch.password"),
 	})
 	if err != nil {
 		panic(err)
@@ -26,6 +26,7 @@ type UserRepository interface {
 	GetTokenByDevice(ctx context.Context, id uint64, deviceType string) (string, error)
 	AddTokenByDevice(ctx context.Context, id uint64, deviceType string, token string) error
 	DeleteTokenByDevice(ctx context.Context, id uint64, deviceType string) error
+	DeleteAllTokens(ctx context.Context, id uint64) error
 }
 
 // NewUserRepository 创建用户仓库实例
@@ -51,6 +52,12 @@ func (r *userRepository) DeleteTokenByDevice(ctx context.Context, id uint64, dev
 	return nil
 }
 
+// DeleteAllTokens 删除用户所有登录端的Token，用户需要重新登录
+func (r *userRepository) DeleteAllTokens(ctx context.Context, id uint64) error {
+	idStr := utils.Uint64TOString(id)
+	return r.rdb.Del(ctx, "user_tokens:"+idStr).Err()
+}
+
 // AddTokenByDevice 添加用户登录端的Token
 func (r *userRepository) AddTokenByDevice(ctx context.Context, id uint64, deviceType string, token string) error {
 	idStr := utils.Uint64TOString(id)
@@ -5,6 +5,7 @@ import (
 	"app/docs"
 	"app/internal/handler"
 	"app/internal/middleware"
+	"app/pkg/constant"
 	"app/pkg/jwt"
 	"app/pkg/log"
 	"app/pkg/server/http"
@@ -31,6 +32,13 @@ func NewHTTPServer(
 	questionBankHandler *handler.QuestionBankHandler,
 	mockInterviewHandler *handler.MockInterviewHandler,
 	questionBankQuestionHandler *handler.QuestionBankQuestionHandler,
+	questionThumbHandler *handler.QuestionThumbHandler,
+	questionFavourHandler *handler.QuestionFavourHandler,
+	questionRevisionHandler *handler.QuestionRevisionHandler,
+	questionSynonymHandler *handler.QuestionSynonymHandler,
+	questionSearchHandler *handler.QuestionSearchHandler,
+	promptTemplateHandler *handler.PromptTemplateHandler,
+	questionGenerateHandler *handler.QuestionGenerateHandler,
 ) *http.Server {
 	gin.SetMode(gin.DebugMode)
 	s := http.NewServer(
@@ -67,14 +75,12 @@ func NewHTTPServer(
 		),
 		// 黑名单
 		middleware.BlacklistMiddleware(),
-		// 判断用户是否登录
-		// middleware.GetLoginStatus(jwt, rdb),
 	)
 
 	r := s.Group("/api")
 	{
 		// No route group has permission
-		noAuthRouter := r.Group("/")
+		noAuthRouter := r.Group("/", middleware.NoStrictAuth(jwt, rdb, logger), middleware.Permission(constant.NotLoginRole))
 		{
 			// 用户模块
 			user := noAuthRouter.Group("/user")
@@ -82,60 +88,151 @@ func NewHTTPServer(
 			user.POST("/login", userHandler.Login)
 			user.GET("/get/login", userHandler.GetLoginUser)
 			user.POST("/logout", userHandler.Logout)
-			user.POST("/list/page", userHandler.ListPage)
-			user.POST("/add", userHandler.AddUser)
-			user.POST("/delete", userHandler.DeleteUser)
-			user.POST("/update", userHandler.UpdateUser)
-			user.POST("/add/sign_in", middleware.GetLoginStatus(jwt, rdb), middleware.AntiCrawling(jwt, rdb, db), userHandler.AddUserSignIn)
-			user.GET("/get/sign_in", middleware.GetLoginStatus(jwt, rdb), userHandler.GetUserSignIn)
 
 			// 题库模块
 			questionBank := noAuthRouter.Group("/questionBank")
-			questionBank.POST("/list/page", questionBankHandler.ListPage)
-			questionBank.POST("/list/page/vo", questionBankHandler.ListPage)
-			questionBank.POST("/add", questionBankHandler.AddQuestionBank)
-			questionBank.POST("/delete", questionBankHandler.DeleteQuestionBank)
-			questionBank.POST("/update", questionBankHandler.UpdateQuestionBank)
+			questionBank.POST("/list/page/vo", questionBankHandler.ListPublicPage)
 			questionBank.GET("/get/vo", questionBankHandler.GetQuestionBank)
 
 			// 题目模块
 			question := noAuthRouter.Group("/question")
-			question.POST("/list/page", questionHandler.ListPage)
 			question.POST("/list/page/vo", questionHandler.ListPageVo)
-			question.POST("/add", questionHandler.AddQuestion)
-			question.POST("/delete", questionHandler.DeleteQuestion)
-			question.POST("/update", questionHandler.UpdateQuestion)
 			question.GET("/get/vo", middleware.CacheByRedis(rdb), questionHandler.GetQuestion)
-			question.GET("/get/vo/test", questionHandler.GetQuestion)
 			question.POST("/search/page/vo", questionHandler.SearchPageVo)
-			question.POST("/delete/batch", questionHandler.DeleteBatchQuestion)
-			question.POST("/ai/generate/question", questionHandler.AiGenerateQuestion)
+			question.GET("/suggest", questionHandler.SuggestQuestion)
+			question.POST("/similar", questionHandler.ListSimilarQuestion)
+			question.GET("/hot/list", questionHandler.ListHotQuestion)
 
-			// 模拟面试模块
+			// 题目题库模块
+			questionBankQuestion := noAuthRouter.Group("/questionBankQuestion")
+			questionBankQuestion.POST("/list/page/vo", questionBankQuestionHandler.GetQuestionBankQuestion)
+			questionBankQuestion.GET("/navigation", questionBankQuestionHandler.GetQuestionBankNavigation)
+
+			// 搜索统计模块
+			questionSearch := noAuthRouter.Group("/questionSearch")
+			questionSearch.POST("/click", questionSearchHandler.Click)
+			questionSearch.GET("/trending", questionSearchHandler.ListTrending)
+
+			// 模拟面试分享模块
 			mockInterview := noAuthRouter.Group("/mockInterview")
+			mockInterview.GET("/share/view", mockInterviewHandler.ViewShare)
+		}
+
+		// Strict permission routing group
+		loginAuthRouter := r.Group("/", middleware.StrictAuth(jwt, rdb, logger), middleware.Permission(constant.DefaultRole))
+		{
+			// 用户模块
+			user := loginAuthRouter.Group("/user")
+			user.POST("/add/sign_in", middleware.AntiCrawling(jwt, rdb, db), userHandler.AddUserSignIn)
+			user.GET("/get/sign_in", userHandler.GetUserSignIn)
+
+			// 题目模块
+			question := loginAuthRouter.Group("/question")
+			question.POST("/add", questionHandler.AddQuestion)
+			question.POST("/update", questionHandler.UpdateQuestion)
+			question.POST("/my/list/page", questionHandler.ListMyPage)
+
+			// 题目点赞模块
+			questionThumb := loginAuthRouter.Group("/questionThumb")
+			questionThumb.POST("/do", questionThumbHandler.DoThumb)
+
+			// 题目收藏模块
+			questionFavour := loginAuthRouter.Group("/questionFavour")
+			questionFavour.POST("/do", questionFavourHandler.DoFavour)
+			questionFavour.POST("/my/list/page", questionFavourHandler.ListMyFavourPage)
+
+			// 题目修订记录模块
+			questionRevision := loginAuthRouter.Group("/questionRevision")
+			questionRevision.POST("/list/page", questionRevisionHandler.ListPage)
+			questionRevision.GET("/diff", questionRevisionHandler.Diff)
+			questionRevision.POST("/rollback", questionRevisionHandler.Rollback)
+
+			// 模拟面试模块
+			mockInterview := loginAuthRouter.Group("/mockInterview")
 			mockInterview.POST("/add", mockInterviewHandler.AddMockInterview)
 			mockInterview.GET("/get", mockInterviewHandler.GetMockInterview)
 			mockInterview.POST("/handleEvent", mockInterviewHandler.MockInterview)
+			mockInterview.POST("/handleEvent/stream", mockInterviewHandler.MockInterviewStream)
 			mockInterview.POST("/my/list/page/vo", mockInterviewHandler.ListPage)
+			mockInterview.GET("/report", mockInterviewHandler.GetMockInterviewReport)
+			mockInterview.POST("/message/list/page", mockInterviewHandler.ListMessagePage)
+			mockInterview.GET("/export", mockInterviewHandler.Export)
+			mockInterview.POST("/share", mockInterviewHandler.Share)
+			mockInterview.GET("/persona/list", promptTemplateHandler.ListMockInterviewPersona)
+		}
+
+		// Vip permission routing group
+		vipAuthRouter := r.Group("/", middleware.StrictAuth(jwt, rdb, logger), middleware.Permission(constant.VipRole))
+		{
+			// 题目模块
+			question := vipAuthRouter.Group("/question")
+			question.POST("/ai/generate/question", questionGenerateHandler.AiGenerateQuestion)
+			question.GET("/ai/generate/job/get", questionGenerateHandler.GetJob)
+			question.POST("/ai/generate/job/list/page", questionGenerateHandler.ListMyJobPage)
+		}
+
+		// Admin permission routing group
+		adminAuthRouter := r.Group("/", middleware.StrictAuth(jwt, rdb, logger), middleware.Permission(constant.AdminRole))
+		{
+			// 用户模块
+			user := adminAuthRouter.Group("/user")
+			user.POST("/list/page", userHandler.ListPage)
+			user.POST("/add", userHandler.AddUser)
+			user.POST("/delete", userHandler.DeleteUser)
+			user.POST("/update", userHandler.UpdateUser)
+
+			// 题库模块
+			questionBank := adminAuthRouter.Group("/questionBank")
+			questionBank.POST("/list/page", questionBankHandler.ListPage)
+			questionBank.POST("/add", questionBankHandler.AddQuestionBank)
+			questionBank.POST("/delete", questionBankHandler.DeleteQuestionBank)
+			questionBank.POST("/update", questionBankHandler.UpdateQuestionBank)
+			questionBank.POST("/review", questionBankHandler.ReviewQuestionBank)
+			questionBank.POST("/review/batch", questionBankHandler.BatchReviewQuestionBank)
+
+			// 题目模块
+			question := adminAuthRouter.Group("/question")
+			question.POST("/list/page", questionHandler.ListPage)
+			question.POST("/delete", questionHandler.DeleteQuestion)
+			question.POST("/delete/batch", questionHandler.DeleteBatchQuestion)
+			question.GET("/get/vo/test", questionHandler.GetQuestion)
+			question.POST("/review", questionHandler.ReviewQuestion)
+			question.POST("/review/batch", questionHandler.BatchReviewQuestion)
+
 			// 题目题库模块
-			questionBankQuestion := noAuthRouter.Group("/questionBankQuestion")
-			questionBankQuestion.POST("/list/page/vo", questionBankQuestionHandler.GetQuestionBankQuestion)
+			questionBankQuestion := adminAuthRouter.Group("/questionBankQuestion")
 			questionBankQuestion.POST("/add", questionBankQuestionHandler.AddQuestionBankQuestion)
 			questionBankQuestion.POST("/remove", questionBankQuestionHandler.RemoveQuestionBankQuestion)
 			questionBankQuestion.POST("/add/batch", questionBankQuestionHandler.BatchAddQuestionBankQuestion)
 			questionBankQuestion.POST("/remove/batch", questionBankQuestionHandler.BatchRemoveQuestionBankQuestion)
-		}
-		// Non-strict permission routing group
-		//noStrictAuthRouter := v1.Group("/").Use(middleware.NoStrictAuth(jwt, logger))
-		//{
-		//	//noStrictAuthRouter.GET("/user", userHandler.GetProfile)
-		//}
+			questionBankQuestion.POST("/insert", questionBankQuestionHandler.InsertQuestionBankQuestion)
+			questionBankQuestion.POST("/move", questionBankQuestionHandler.MoveQuestionBankQuestion)
+			questionBankQuestion.POST("/reorder", questionBankQuestionHandler.ReorderQuestionBankQuestion)
+			questionBankQuestion.POST("/renumber", questionBankQuestionHandler.RenumberQuestionBankQuestion)
+			questionBankQuestion.POST("/list/page", questionBankQuestionHandler.ListQuestionBankQuestion)
+			questionBankQuestion.POST("/list/bank", questionBankQuestionHandler.ListQuestionBelongBank)
 
-		// Strict permission routing group
-		//strictAuthRouter := v1.Group("/").Use(middleware.StrictAuth(jwt, logger))
-		//{
-		//	//strictAuthRouter.PUT("/user", userHandler.UpdateProfile)
-		//}
+			// 搜索同义词模块
+			questionSynonym := adminAuthRouter.Group("/questionSynonym")
+			questionSynonym.POST("/add", questionSynonymHandler.AddQuestionSynonym)
+			questionSynonym.POST("/update", questionSynonymHandler.UpdateQuestionSynonym)
+			questionSynonym.POST("/delete", questionSynonymHandler.DeleteQuestionSynonym)
+			questionSynonym.POST("/list/page", questionSynonymHandler.ListPage)
+			questionSynonym.POST("/push", questionSynonymHandler.Push)
+
+			// 搜索统计模块
+			questionSearch := adminAuthRouter.Group("/questionSearch")
+			questionSearch.GET("/stats/top", questionSearchHandler.ListTopQuery)
+			questionSearch.GET("/stats/zero", questionSearchHandler.ListZeroResultQuery)
+			questionSearch.GET("/stats/click", questionSearchHandler.ListClickThrough)
+
+			// Prompt 模板模块
+			promptTemplate := adminAuthRouter.Group("/promptTemplate")
+			promptTemplate.POST("/save", promptTemplateHandler.SavePromptTemplate)
+			promptTemplate.GET("/get", promptTemplateHandler.GetPromptTemplate)
+			promptTemplate.POST("/list/page", promptTemplateHandler.ListPage)
+			promptTemplate.POST("/delete", promptTemplateHandler.DeletePromptTemplate)
+		}
 	}
 
 	return s
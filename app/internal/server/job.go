@@ -7,20 +7,23 @@ import (
 )
 
 type JobServer struct {
-	log      *log.Logger
-	userJob  job.UserJob
-	question job.QuestionJob
+	log              *log.Logger
+	userJob          job.UserJob
+	question         job.QuestionJob
+	questionGenerate job.QuestionGenerateJob
 }
 
 func NewJobServer(
 	log *log.Logger,
 	userJob job.UserJob,
 	question job.QuestionJob,
+	questionGenerate job.QuestionGenerateJob,
 ) *JobServer {
 	return &JobServer{
-		log:      log,
-		userJob:  userJob,
-		question: question,
+		log:              log,
+		userJob:          userJob,
+		question:         question,
+		questionGenerate: questionGenerate,
 	}
 }
 
@@ -28,6 +31,9 @@ func (j *JobServer) Start(ctx context.Context) error {
 	// Tips: If you want job to start as a separate process, just refer to the task implementation and adjust the code accordingly.
 
 	// eg: kafka consumer
+	go func() {
+		_ = j.questionGenerate.ConsumeQuestionGenerate(ctx)
+	}()
 	err := j.question.DataToElasticsearch(ctx)
 	return err
 }
@@ -2,8 +2,12 @@ package server
 
 import (
 	"app/internal/model"
+	"app/internal/service"
+	"app/pkg/constant"
+	"app/pkg/llm"
 	"app/pkg/log"
 	"context"
+	"encoding/json"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 	"os"
@@ -22,18 +26,138 @@ func NewMigrateServer(db *gorm.DB, log *log.Logger) *MigrateServer {
 }
 func (m *MigrateServer) Start(ctx context.Context) error {
 	if err := m.db.AutoMigrate(
+		&model.SchemaMigration{},
 		&model.User{},
 		&model.QuestionBank{},
 		&model.Question{},
 		&model.QuestionBankQuestion{},
+		&model.QuestionThumb{},
+		&model.QuestionFavour{},
+		&model.QuestionRevision{},
+		&model.QuestionOutbox{},
+		&model.QuestionSynonym{},
+		&model.QuestionSearchLog{},
+		&model.QuestionSearchClick{},
+		&model.MockInterview{},
+		&model.MockInterviewQuestion{},
+		&model.MockInterviewReport{},
+		&model.MockInterviewMessage{},
+		&model.PromptTemplate{},
+		&model.QuestionGenerateJob{},
 	); err != nil {
 		m.log.Error("user migrate error", zap.Error(err))
 		return err
 	}
+	if err := m.backfillReviewStatus(ctx); err != nil {
+		m.log.Error("review status backfill error", zap.Error(err))
+		return err
+	}
+	if err := m.migrateMockInterviewMessages(ctx); err != nil {
+		m.log.Error("mock interview messages migrate error", zap.Error(err))
+		return err
+	}
+	if err := m.seedPromptTemplates(ctx); err != nil {
+		m.log.Error("prompt template seed error", zap.Error(err))
+		return err
+	}
 	m.log.Info("AutoMigrate success")
 	os.Exit(0)
 	return nil
 }
+
+// reviewStatusBackfillMigration 审核回填迁移的名称
+const reviewStatusBackfillMigration = "backfill_review_status"
+
+// backfillReviewStatus 将审核功能上线前的题目和题库（待审核且从未被审核过）回填为审核通过，否则上线后公开接口查不到这些内容；
+// 审核字段在此之前已经存在且默认为待审核，无法根据字段判断，通过迁移记录保证只执行一次，避免把上线后用户提交的待审核内容也改为通过。
+// ES 中的旧文档没有审核状态，回填后需要执行 cmd/reindex 重建索引
+func (m *MigrateServer) backfillReviewStatus(ctx context.Context) error {
+	return m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&model.SchemaMigration{}).Where("name = ?", reviewStatusBackfillMigration).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return nil
+		}
+		for _, table := range []string{(&model.Question{}).TableName(), (&model.QuestionBank{}).TableName()} {
+			result := tx.Exec("UPDATE "+table+" SET review_status = ? WHERE review_status = ? AND review_time IS NULL AND reviewer_id IS NULL",
+				constant.ReviewStatusPass, constant.ReviewStatusPending)
+			if result.Error != nil {
+				return result.Error
+			}
+			m.log.Info("review status backfilled", zap.String("table", table), zap.Int64("rows", result.RowsAffected))
+		}
+		return tx.Create(&model.SchemaMigration{Name: reviewStatusBackfillMigration}).Error
+	})
+}
+
+// migrateMockInterviewMessages 将 mock_interview.messages 中的历史 JSON 消息转换为 mock_interview_message 表的记录；
+// 已有消息记录的面试会跳过，可重复执行，原字段保留不删除
+func (m *MigrateServer) migrateMockInterviewMessages(ctx context.Context) error {
+	var interviews []model.MockInterview
+	converted := 0
+	err := m.db.WithContext(ctx).
+		Select("id", "messages", "create_time").
+		Where("messages IS NOT NULL AND messages <> ''").
+		Where("NOT EXISTS (SELECT 1 FROM mock_interview_message WHERE mock_interview_message.mock_interview_id = mock_interview.id)").
+		FindInBatches(&interviews, 100, func(tx *gorm.DB, batch int) error {
+			for _, interview := range interviews {
+				var legacy []model.MockInterviewContextMessage
+				if err := json.Unmarshal([]byte(interview.Messages), &legacy); err != nil {
+					m.log.Warn("skip invalid mock interview messages", zap.Uint64("id", interview.ID), zap.Error(err))
+					continue
+				}
+				if len(legacy) == 0 {
+					continue
+				}
+				messages := make([]model.MockInterviewMessage, 0, len(legacy))
+				for i, message := range legacy {
+					messages = append(messages, model.MockInterviewMessage{
+						MockInterviewID: interview.ID,
+						Seq:             i + 1,
+						Role:            message.Role,
+						Content:         message.Content,
+						QuestionID:      message.QuestionID,
+						Tokens:          llm.EstimateMessageTokens(llm.Message{Role: message.Role, Content: message.Content}),
+						CreateTime:      interview.CreateTime,
+					})
+				}
+				if err := m.db.WithContext(ctx).Create(&messages).Error; err != nil {
+					return err
+				}
+				converted++
+			}
+			return nil
+		}).Error
+	if err != nil {
+		return err
+	}
+	m.log.Info("mock interview messages migrated", zap.Int("interviews", converted))
+	return nil
+}
+
+// seedPromptTemplates 将内置 Prompt 模板写入为第 1 个版本，已有版本（包括已停用）的场景、风格会跳过，可重复执行
+func (m *MigrateServer) seedPromptTemplates(ctx context.Context) error {
+	for _, template := range service.DefaultPromptTemplates() {
+		var count int64
+		if err := m.db.WithContext(ctx).Model(&model.PromptTemplate{}).
+			Where("scene = ? AND persona = ?", template.Scene, template.Persona).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+		template.Version = 1
+		if err := m.db.WithContext(ctx).Create(&template).Error; err != nil {
+			return err
+		}
+		m.log.Info("prompt template seeded", zap.String("scene", template.Scene), zap.String("persona", template.Persona))
+	}
+	return nil
+}
+
 func (m *MigrateServer) Stop(ctx context.Context) error {
 	m.log.Info("AutoMigrate stop")
 	return nil
@@ -0,0 +1,162 @@
+package server
+
+import (
+	"app/internal/model"
+	"app/internal/repository"
+	"app/pkg/constant"
+	"app/pkg/embedding"
+	"app/pkg/log"
+	"context"
+	"fmt"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+	"os"
+	"time"
+)
+
+// ReindexServer 从 MySQL 全量重建题目索引，校验文档数后原子切换别名，实现零停机修改 mapping
+type ReindexServer struct {
+	log                *log.Logger
+	conf               *viper.Viper
+	questionRepo       repository.QuestionRepository
+	questionIndexRepo  repository.QuestionIndexRepository
+	questionOutboxRepo repository.QuestionOutboxRepository
+	synonymRepo        repository.QuestionSynonymRepository
+	embeddingProvider  embedding.Provider
+}
+
+func NewReindexServer(
+	log *log.Logger,
+	conf *viper.Viper,
+	questionRepo repository.QuestionRepository,
+	questionIndexRepo repository.QuestionIndexRepository,
+	questionOutboxRepo repository.QuestionOutboxRepository,
+	synonymRepo repository.QuestionSynonymRepository,
+	embeddingProvider embedding.Provider,
+) *ReindexServer {
+	return &ReindexServer{
+		log:                log,
+		conf:               conf,
+		questionRepo:       questionRepo,
+		questionIndexRepo:  questionIndexRepo,
+		questionOutboxRepo: questionOutboxRepo,
+		synonymRepo:        synonymRepo,
+		embeddingProvider:  embeddingProvider,
+	}
+}
+
+func (s *ReindexServer) Start(ctx context.Context) error {
+	if err := s.reindex(ctx); err != nil {
+		s.log.Error("reindex error", zap.Error(err))
+		os.Exit(1)
+	}
+	s.log.Info("reindex success")
+	os.Exit(0)
+	return nil
+}
+
+func (s *ReindexServer) Stop(ctx context.Context) error {
+	s.log.Info("reindex stop")
+	return nil
+}
+
+func (s *ReindexServer) reindex(ctx context.Context) error {
+	bulkSize := s.conf.GetInt("data.elasticsearch.bulk_size")
+	if bulkSize <= 0 {
+		bulkSize = 500
+	}
+
+	// 记录开始时的事件位置，重建期间的修改在切换别名后重新投递到新索引
+	snapshotId, err := s.questionOutboxRepo.GetMaxId(ctx)
+	if err != nil {
+		return err
+	}
+	// 新索引的搜索分词器引用同义词集合，创建索引前集合必须存在
+	synonyms, err := s.synonymRepo.ListAll(ctx)
+	if err != nil {
+		return err
+	}
+	if err = s.questionIndexRepo.PutSynonyms(ctx, synonyms); err != nil {
+		return err
+	}
+	newIndex := constant.QuestionIndexPrefix + time.Now().Format("20060102150405")
+	if err = s.questionIndexRepo.CreateIndex(ctx, newIndex); err != nil {
+		return err
+	}
+	s.log.Info("index created", zap.String("index", newIndex), zap.Uint64("snapshotId", snapshotId))
+
+	// 按ID分批读取题目，通过 _bulk 写入新索引
+	var afterId uint64
+	var indexed int64
+	for {
+		questions, err := s.questionRepo.ListQuestionAfterId(ctx, afterId, bulkSize)
+		if err != nil {
+			return err
+		}
+		if len(questions) == 0 {
+			break
+		}
+		docs := make([]model.QuestionEs, 0, len(questions))
+		for i := range questions {
+			doc := questions[i].ToEs()
+			if doc.Embedding, err = s.embeddingProvider.Embed(ctx, questions[i].EmbeddingText()); err != nil {
+				return err
+			}
+			docs = append(docs, doc)
+		}
+		if err = s.questionIndexRepo.BulkIndex(ctx, newIndex, docs, snapshotId); err != nil {
+			return err
+		}
+		afterId = questions[len(questions)-1].ID
+		indexed += int64(len(questions))
+		s.log.Info("bulk indexed", zap.Int64("indexed", indexed), zap.Uint64("lastId", afterId))
+	}
+	if err = s.questionIndexRepo.FinishBulk(ctx, newIndex); err != nil {
+		return err
+	}
+
+	// 文档数与写入数不一致时不切换别名，保留新索引便于排查
+	count, err := s.questionIndexRepo.Count(ctx, newIndex)
+	if err != nil {
+		return err
+	}
+	if count != indexed {
+		return fmt.Errorf("document count mismatch: index %s has %d, expected %d", newIndex, count, indexed)
+	}
+
+	oldIndices, isConcrete, err := s.questionIndexRepo.GetAliasIndices(ctx, constant.QuestionIndexAlias)
+	if err != nil {
+		return err
+	}
+	if err = s.questionIndexRepo.SwapAlias(ctx, constant.QuestionIndexAlias, newIndex, oldIndices, isConcrete); err != nil {
+		return err
+	}
+	s.log.Info("alias swapped", zap.String("alias", constant.QuestionIndexAlias),
+		zap.String("index", newIndex), zap.Strings("oldIndices", oldIndices))
+
+	replayed, err := s.questionOutboxRepo.ReplayAfter(ctx, snapshotId)
+	if err != nil {
+		return err
+	}
+	s.log.Info("outbox events replayed", zap.Int64("count", replayed))
+
+	// 只保留上一个版本的索引用于回滚，更早的索引直接删除
+	keep := map[string]bool{newIndex: true}
+	for _, index := range oldIndices {
+		keep[index] = true
+	}
+	indices, err := s.questionIndexRepo.ListIndices(ctx, constant.QuestionIndexPrefix)
+	if err != nil {
+		return err
+	}
+	for _, index := range indices {
+		if keep[index] {
+			continue
+		}
+		if err = s.questionIndexRepo.DeleteIndex(ctx, index); err != nil {
+			return err
+		}
+		s.log.Info("old index deleted", zap.String("index", index))
+	}
+	return nil
+}
@@ -10,18 +10,24 @@ import (
 )
 
 type TaskServer struct {
-	log       *log.Logger
-	scheduler *gocron.Scheduler
-	userTask  task.UserTask
+	log               *log.Logger
+	scheduler         *gocron.Scheduler
+	userTask          task.UserTask
+	questionTask      task.QuestionTask
+	mockInterviewTask task.MockInterviewTask
 }
 
 func NewTaskServer(
 	log *log.Logger,
 	userTask task.UserTask,
+	questionTask task.QuestionTask,
+	mockInterviewTask task.MockInterviewTask,
 ) *TaskServer {
 	return &TaskServer{
-		log:      log,
-		userTask: userTask,
+		log:               log,
+		userTask:          userTask,
+		questionTask:      questionTask,
+		mockInterviewTask: mockInterviewTask,
 	}
 }
 func (t *TaskServer) Start(ctx context.Context) error {
@@ -45,6 +51,61 @@ func (t *TaskServer) Start(ctx context.Context) error {
 		t.log.Error("CheckUser error", zap.Error(err))
 	}
 
+	// 每分钟将题目点赞数、收藏数刷回数据库
+	_, err = t.scheduler.Every("1m").Do(func() {
+		err := t.questionTask.SyncQuestionThumbFavourNum(ctx)
+		if err != nil {
+			t.log.Error("SyncQuestionThumbFavourNum error", zap.Error(err))
+		}
+	})
+	if err != nil {
+		t.log.Error("SyncQuestionThumbFavourNum error", zap.Error(err))
+	}
+
+	// 每分钟将题目、题库浏览量刷回数据库
+	_, err = t.scheduler.Every("1m").Do(func() {
+		err := t.questionTask.SyncViewNum(ctx)
+		if err != nil {
+			t.log.Error("SyncViewNum error", zap.Error(err))
+		}
+	})
+	if err != nil {
+		t.log.Error("SyncViewNum error", zap.Error(err))
+	}
+
+	// 每天清理已投递的题目变更事件
+	_, err = t.scheduler.Every("24h").Do(func() {
+		err := t.questionTask.CleanQuestionOutbox(ctx)
+		if err != nil {
+			t.log.Error("CleanQuestionOutbox error", zap.Error(err))
+		}
+	})
+	if err != nil {
+		t.log.Error("CleanQuestionOutbox error", zap.Error(err))
+	}
+
+	// 每天清理过期的搜索日志
+	_, err = t.scheduler.Every("24h").Do(func() {
+		err := t.questionTask.CleanQuestionSearchLog(ctx)
+		if err != nil {
+			t.log.Error("CleanQuestionSearchLog error", zap.Error(err))
+		}
+	})
+	if err != nil {
+		t.log.Error("CleanQuestionSearchLog error", zap.Error(err))
+	}
+
+	// 每分钟放弃超时未活动的模拟面试
+	_, err = t.scheduler.Every("1m").Do(func() {
+		err := t.mockInterviewTask.AbandonIdleMockInterview(ctx)
+		if err != nil {
+			t.log.Error("AbandonIdleMockInterview error", zap.Error(err))
+		}
+	})
+	if err != nil {
+		t.log.Error("AbandonIdleMockInterview error", zap.Error(err))
+	}
+
 	t.scheduler.StartBlocking()
 	return nil
 }
@@ -4,285 +4,382 @@ import (
 	v1 "app/api/v1"
 	"app/internal/model"
 	"app/internal/repository"
-	"app/pkg/aiServer/ai"
+	"app/pkg/constant"
+	"app/pkg/embedding"
+	"app/pkg/jwt"
+	"app/pkg/llm"
+	"app/pkg/utils"
 	"context"
 	"encoding/json"
-	"fmt"
+	"errors"
 	"github.com/gin-gonic/gin"
-	chatModel "github.com/volcengine/volcengine-go-sdk/service/arkruntime/model"
-	"github.com/volcengine/volcengine-go-sdk/volcengine"
+	"go.uber.org/zap"
 	"strings"
 	"time"
 )
 
 type MockInterviewService interface {
-	MockInterview(ctx context.Context, req *v1.MockInterviewEventRequest) (string, error)
-	AddMockInterview(ctx context.Context, req *v1.MockInterviewAddRequest, token string) (uint64, error)
-	GetMockInterview(ctx *gin.Context, v *v1.MockInterviewGetRequest) (v1.MockInterview, error)
-	ListMockInterview(ctx *gin.Context, v *v1.MockInterviewQueryRequest, token string) (*v1.PageMockInterview, error)
+	MockInterview(ctx context.Context, req *v1.MockInterviewEventRequest, loginUser *jwt.User) (string, error)
+	MockInterviewStream(ctx context.Context, req *v1.MockInterviewEventRequest, loginUser *jwt.User, onDelta func(delta string) error) (*v1.MockInterviewStreamResult, error)
+	AddMockInterview(ctx context.Context, req *v1.MockInterviewAddRequest, loginUser *jwt.User) (uint64, error)
+	GetMockInterview(ctx *gin.Context, v *v1.MockInterviewGetRequest, loginUser *jwt.User) (v1.MockInterview, error)
+	ListMockInterview(ctx *gin.Context, v *v1.MockInterviewQueryRequest, loginUser *jwt.User) (*v1.PageMockInterview, error)
+	GetMockInterviewReport(ctx context.Context, req *v1.MockInterviewReportRequest, loginUser *jwt.User) (*v1.MockInterviewReport, error)
+	ListMockInterviewMessage(ctx context.Context, req *v1.MockInterviewMessageQueryRequest, loginUser *jwt.User) (v1.QuestionQueryResponseData[v1.MockInterviewMessageVO], error)
+	ExportMockInterview(ctx context.Context, req *v1.MockInterviewExportRequest, loginUser *jwt.User) (*v1.MockInterviewExportFile, error)
+	ShareMockInterview(ctx context.Context, req *v1.MockInterviewShareRequest, loginUser *jwt.User) (*v1.MockInterviewShareVO, error)
+	ViewSharedMockInterview(ctx context.Context, req *v1.MockInterviewShareViewRequest) (*v1.MockInterviewExportFile, error)
 }
 
 func NewMockInterviewService(
 	service *Service,
 	mockInterviewRepository repository.MockInterviewRepository,
+	mockInterviewReportRepository repository.MockInterviewReportRepository,
+	mockInterviewQuestionRepository repository.MockInterviewQuestionRepository,
+	mockInterviewMessageRepository repository.MockInterviewMessageRepository,
+	promptTemplateRepository repository.PromptTemplateRepository,
+	questionRepository repository.QuestionRepository,
+	questionBankRepository repository.QuestionBankRepository,
+	llmClient llm.Client,
+	embeddingProvider embedding.Provider,
 ) MockInterviewService {
 	return &mockInterviewService{
-		Service:                 service,
-		mockInterviewRepository: mockInterviewRepository,
+		Service:                         service,
+		mockInterviewRepository:         mockInterviewRepository,
+		mockInterviewReportRepository:   mockInterviewReportRepository,
+		mockInterviewQuestionRepository: mockInterviewQuestionRepository,
+		mockInterviewMessageRepository:  mockInterviewMessageRepository,
+		promptTemplateRepository:        promptTemplateRepository,
+		questionRepository:              questionRepository,
+		questionBankRepository:          questionBankRepository,
+		llmClient:                       llmClient,
+		embeddingProvider:               embeddingProvider,
 	}
 }
 
 type mockInterviewService struct {
 	*Service
-	mockInterviewRepository repository.MockInterviewRepository
+	mockInterviewRepository         repository.MockInterviewRepository
+	mockInterviewReportRepository   repository.MockInterviewReportRepository
+	mockInterviewQuestionRepository repository.MockInterviewQuestionRepository
+	mockInterviewMessageRepository  repository.MockInterviewMessageRepository
+	promptTemplateRepository        repository.PromptTemplateRepository
+	questionRepository              repository.QuestionRepository
+	questionBankRepository          repository.QuestionBankRepository
+	llmClient                       llm.Client
+	embeddingProvider               embedding.Provider
 }
 
-func (m mockInterviewService) ListMockInterview(ctx *gin.Context, req *v1.MockInterviewQueryRequest, token string) (*v1.PageMockInterview, error) {
-	// 解析 token
-	claims, err := m.jwt.ParseToken(token)
-	if err != nil {
-		return nil, err
-	}
+func (m mockInterviewService) ListMockInterview(ctx *gin.Context, req *v1.MockInterviewQueryRequest, loginUser *jwt.User) (*v1.PageMockInterview, error) {
 	// 获取用户 ID
-	userId := claims.User.ID
+	userId := loginUser.ID
 
 	mockInterviews, err := m.mockInterviewRepository.ListMockInterview(ctx, userId)
 	if err != nil {
 		return nil, err
 	}
 	var records []v1.MockInterview
-	for _, mockInterview := range mockInterviews {
-		records = append(records, v1.MockInterview{
-			CreateTime:     mockInterview.CreateTime,
-			Difficulty:     mockInterview.Difficulty,
-			ID:             mockInterview.ID,
-			IsDelete:       mockInterview.IsDelete,
-			JobPosition:    mockInterview.JobPosition,
-			Messages:       mockInterview.Messages,
-			Status:         mockInterview.Status,
-			UpdateTime:     mockInterview.UpdateTime,
-			UserID:         mockInterview.UserID,
-			WorkExperience: mockInterview.WorkExperience,
-		})
+	for i := range mockInterviews {
+		records = append(records, toMockInterviewVO(&mockInterviews[i]))
 	}
 	return &v1.PageMockInterview{
 		Records: records,
 	}, nil
 }
 
-func (m mockInterviewService) GetMockInterview(ctx *gin.Context, req *v1.MockInterviewGetRequest) (v1.MockInterview, error) {
+// GetMockInterview 获取模拟面试，只有本人或管理员可以查看
+func (m mockInterviewService) GetMockInterview(ctx *gin.Context, req *v1.MockInterviewGetRequest, loginUser *jwt.User) (v1.MockInterview, error) {
 	mockInterview, err := m.mockInterviewRepository.GetMockInterview(ctx, req.ID)
 	if err != nil {
 		return v1.MockInterview{}, err
 	}
+	if mockInterview.UserID != loginUser.ID && loginUser.UserRole != constant.AdminRole {
+		return v1.MockInterview{}, v1.ErrUnauthorized
+	}
+	vo := toMockInterviewVO(mockInterview)
+	if vo.Messages, err = m.mockInterviewDialogueJson(ctx, mockInterview.ID); err != nil {
+		return v1.MockInterview{}, err
+	}
+	return vo, nil
+}
 
-	return v1.MockInterview{
+// toMockInterviewVO 转换为模拟面试视图
+func toMockInterviewVO(mockInterview *model.MockInterview) v1.MockInterview {
+	vo := v1.MockInterview{
 		CreateTime:     mockInterview.CreateTime,
 		Difficulty:     mockInterview.Difficulty,
 		ID:             mockInterview.ID,
 		IsDelete:       mockInterview.IsDelete,
 		JobPosition:    mockInterview.JobPosition,
-		Messages:       mockInterview.Messages,
 		Status:         mockInterview.Status,
 		UpdateTime:     mockInterview.UpdateTime,
 		UserID:         mockInterview.UserID,
 		WorkExperience: mockInterview.WorkExperience,
-	}, nil
+		QuestionNum:    mockInterview.QuestionNum,
+		Persona:        mockInterview.Persona,
+		PromptVersion:  mockInterview.PromptTemplateVersion,
+	}
+	if mockInterview.QuestionBankID != nil {
+		questionBankId := utils.Uint64TOString(*mockInterview.QuestionBankID)
+		vo.QuestionBankID = &questionBankId
+	}
+	return vo
 }
 
 // AddMockInterview 添加模拟面试
-func (m mockInterviewService) AddMockInterview(ctx context.Context, req *v1.MockInterviewAddRequest, token string) (uint64, error) {
-	// 解析 token
-	claims, err := m.jwt.ParseToken(token)
-	if err != nil {
-		return 0, err
-	}
+func (m mockInterviewService) AddMockInterview(ctx context.Context, req *v1.MockInterviewAddRequest, loginUser *jwt.User) (uint64, error) {
 	// 获取用户 ID
-	userId := claims.User.ID
+	userId := loginUser.ID
 	// 创建 MockInterview
-	interview := v1.MockInterview{
-		CreateTime:     time.Time{},
+	interview := &model.MockInterview{
 		Difficulty:     req.Difficulty,
 		JobPosition:    req.JobPosition,
-		UpdateTime:     time.Time{},
 		UserID:         userId,
 		WorkExperience: req.WorkExperience,
-		Status:         0, // 待开始模拟面试
+		Status:         constant.MockInterviewStatusPending,
 	}
-	// 将数据添加到数据库
-	id, err := m.mockInterviewRepository.AddMockInterview(ctx, interview)
+	// 记录创建时面试官风格的最新模板版本，数据库中没有默认风格时使用内置模板
+	persona := req.Persona
+	if persona == "" {
+		persona = constant.MockInterviewPersonaStrict
+	}
+	promptTemplate, err := getPromptTemplate(ctx, m.promptTemplateRepository, constant.PromptSceneMockInterview, persona)
 	if err != nil {
 		return 0, err
 	}
-	return id, nil
-}
-
-func (m mockInterviewService) MockInterview(ctx context.Context, req *v1.MockInterviewEventRequest) (string, error) {
-	// 获取模拟模拟面试的信息
-	mockInterview, err := m.mockInterviewRepository.GetMockInterview(ctx, req.ID)
-	if err != nil {
-		return "", err
+	if promptTemplate == nil {
+		return 0, v1.ErrPromptPersonaNotFound
 	}
-	// 定义系统 Prompt
-	systemPrompt := fmt.Sprintf("你是一位严厉的程序员面试官，我是候选人，来应聘 %s 的 %s 岗位，面试难度为 %s。请你向我依次提出问题（最多 20 个问题），我也会依次回复。在这期间请完全保持真人面试官的口吻，比如适当引导学员、或者表达出你对学员回答的态度。\n"+
-		"必须满足如下要求：\n"+
-		"1. 当学员回复 “开始” 时，你要正式开始面试\n"+
-		"2. 当学员表示希望 “结束面试” 时，你要结束面试\n"+
-		"3. 此外，当你觉得这场面试可以结束时（比如候选人回答结果较差、不满足工作年限的招聘需求、或者候选人态度不礼貌），必须主动提出面试结束，不用继续询问更多问题了。并且要在回复中包含字符串【面试结束】\n"+
-		"4. 面试结束后，应该给出候选人整场面试的表现和总结。\n"+
-		"5. 使用纯文本回复", mockInterview.WorkExperience, mockInterview.JobPosition, mockInterview.Difficulty)
-
-	switch req.Event {
-	// 开始模拟面试
-	case "start":
-		// 定义用户 Prompt
-		userPrompt := "开始"
-		// 调用AI接口开始面试
-		var chatMessages []*chatModel.ChatCompletionMessage
-		// 添加系统预设
-		chatMessages = append(chatMessages, &chatModel.ChatCompletionMessage{
-			Role: chatModel.ChatMessageRoleSystem,
-			Content: &chatModel.ChatCompletionMessageContent{
-				StringValue: volcengine.String(systemPrompt),
-			},
-		})
-		// 添加用户预设
-		chatMessages = append(chatMessages, &chatModel.ChatCompletionMessage{
-			Role: chatModel.ChatMessageRoleUser,
-			Content: &chatModel.ChatCompletionMessageContent{
-				StringValue: volcengine.String(userPrompt),
-			},
-		})
-		result := ai.DoChat(chatMessages)
-		// 将AI的回复序列化成json
-		chatMessages = append(chatMessages, &chatModel.ChatCompletionMessage{
-			Role: chatModel.ChatMessageRoleAssistant,
-			Content: &chatModel.ChatCompletionMessageContent{
-				StringValue: volcengine.String(result),
-			},
-		})
-		jsonStr, err := json.Marshal(chatMessages)
-		if err != nil {
-			return "", err
-		}
-		// 将AI的回复记录到数据库
-		mockInterview.ID = req.ID
-		mockInterview.Messages = string(jsonStr)
-		mockInterview.Status = 1 // 进行中
-		err = m.mockInterviewRepository.UpdateMockInterview(ctx, mockInterview)
-		if err != nil {
-			return "", err
-		}
-		return result, nil
-	// 进行模拟面试
-	case "chat":
-		// 获取历史消息记录
-		historyInterview, err := m.mockInterviewRepository.GetMockInterview(ctx, req.ID)
+	interview.Persona = persona
+	if promptTemplate.ID != 0 {
+		interview.PromptTemplateID = &promptTemplate.ID
+		interview.PromptTemplateVersion = promptTemplate.Version
+	}
+	// 题库模式：按优先级加权抽题，排除近期练习过的题目
+	var questionIds []uint64
+	if req.QuestionBankID != nil && *req.QuestionBankID != "" {
+		questionBankId, err := utils.StringToUint64(*req.QuestionBankID)
 		if err != nil {
-			return "", err
+			return 0, v1.ParamsError
 		}
-		historyMessages := historyInterview.Messages
-		// 将历史消息记录反序列化
-		var historyChatMessages []model.MockInterviewMessage
-		err = json.Unmarshal([]byte(historyMessages), &historyChatMessages)
+		bank, err := m.questionBankRepository.GetByID(ctx, questionBankId)
 		if err != nil {
-			return "", err
+			return 0, err
 		}
-		var chatMessages []*chatModel.ChatCompletionMessage
-		for _, message := range historyChatMessages {
-			chatMessages = append(chatMessages, &chatModel.ChatCompletionMessage{
-				Role: message.Role,
-				Content: &chatModel.ChatCompletionMessageContent{
-					StringValue: volcengine.String(message.Content),
-				},
-			})
+		if bank.ReviewStatus != constant.ReviewStatusPass && loginUser.UserRole != constant.AdminRole {
+			return 0, v1.ErrNotFound
 		}
-		// 定义用户 Prompt
-		userPrompt := req.Message
-		chatMessages = append(chatMessages, &chatModel.ChatCompletionMessage{
-			Role: chatModel.ChatMessageRoleUser,
-			Content: &chatModel.ChatCompletionMessageContent{
-				StringValue: volcengine.String(userPrompt),
-			},
-		})
-		// 调用AI接口结束面试
-		result := ai.DoChat(chatMessages)
-		// 将AI的回复序列化成json
-		chatMessages = append(chatMessages, &chatModel.ChatCompletionMessage{
-			Role: chatModel.ChatMessageRoleAssistant,
-			Content: &chatModel.ChatCompletionMessageContent{
-				StringValue: volcengine.String(result),
-			},
-		})
-		jsonStr, err := json.Marshal(chatMessages)
-		if err != nil {
-			return "", err
+		questionNum := req.QuestionNum
+		if questionNum <= 0 {
+			questionNum = constant.MockInterviewDefaultQuestionNum
 		}
-		// 将AI的回复记录到数据库
-		if strings.Contains(result, "【面试结束】") {
-			mockInterview.Status = 2 // 结束
+		if questionNum > constant.MockInterviewMaxQuestionNum {
+			return 0, v1.ParamsError
 		}
-		mockInterview.ID = req.ID
-		mockInterview.Messages = string(jsonStr)
-		err = m.mockInterviewRepository.UpdateMockInterview(ctx, mockInterview)
-		if err != nil {
-			return "", err
+		if questionIds, err = m.sampleBankQuestions(ctx, questionBankId, userId, questionNum); err != nil {
+			return 0, err
 		}
-		return result, nil
-	// 结束模拟面试
-	case "end":
-		// 获取历史消息记录
-		historyInterview, err := m.mockInterviewRepository.GetMockInterview(ctx, req.ID)
-		if err != nil {
-			return "", err
+		if len(questionIds) == 0 {
+			return 0, v1.ErrMockInterviewBankEmpty
 		}
-		historyMessages := historyInterview.Messages
-		// 将历史消息记录反序列化
-		var historyChatMessages []model.MockInterviewMessage
-		err = json.Unmarshal([]byte(historyMessages), &historyChatMessages)
-		if err != nil {
-			return "", err
+		interview.QuestionBankID = &questionBankId
+		interview.QuestionNum = len(questionIds)
+	}
+	// 将数据添加到数据库
+	err = m.tm.Transaction(ctx, func(ctx context.Context) error {
+		if _, err := m.mockInterviewRepository.AddMockInterview(ctx, interview); err != nil {
+			return err
 		}
-		var chatMessages []*chatModel.ChatCompletionMessage
-		for _, message := range historyChatMessages {
-			chatMessages = append(chatMessages, &chatModel.ChatCompletionMessage{
-				Role: message.Role,
-				Content: &chatModel.ChatCompletionMessageContent{
-					StringValue: volcengine.String(message.Content),
-				},
+		questions := make([]model.MockInterviewQuestion, 0, len(questionIds))
+		for i, questionId := range questionIds {
+			questions = append(questions, model.MockInterviewQuestion{
+				MockInterviewID: interview.ID,
+				UserID:          userId,
+				QuestionID:      questionId,
+				Sequence:        i + 1,
+				CreateTime:      time.Now(),
 			})
 		}
-		// 定义用户 Prompt
-		userPrompt := "结束"
-		chatMessages = append(chatMessages, &chatModel.ChatCompletionMessage{
-			Role: chatModel.ChatMessageRoleUser,
-			Content: &chatModel.ChatCompletionMessageContent{
-				StringValue: volcengine.String(userPrompt),
-			},
-		})
-		// 调用AI接口结束面试
-		result := ai.DoChat(chatMessages)
-		// 将AI的回复序列化成json
-		chatMessages = append(chatMessages, &chatModel.ChatCompletionMessage{
-			Role: chatModel.ChatMessageRoleAssistant,
-			Content: &chatModel.ChatCompletionMessageContent{
-				StringValue: volcengine.String(result),
-			},
-		})
-		jsonStr, err := json.Marshal(chatMessages)
-		if err != nil {
-			return "", err
+		return m.mockInterviewQuestionRepository.BatchCreate(ctx, questions)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return interview.ID, nil
+}
+
+// MockInterview 处理模拟面试事件：开始、回答和结束时返回面试官的回复，暂停、继续和放弃只变更状态，返回空字符串
+func (m mockInterviewService) MockInterview(ctx context.Context, req *v1.MockInterviewEventRequest, loginUser *jwt.User) (string, error) {
+	if !isMockInterviewEvent(req.Event) {
+		return "", v1.ParamsError
+	}
+	// 获取模拟模拟面试的信息，并校验状态转换
+	mockInterview, err := m.getOwnMockInterview(ctx, req.ID, loginUser)
+	if err != nil {
+		return "", err
+	}
+	nextStatus, err := nextMockInterviewStatus(mockInterview.Status, req.Event)
+	if err != nil {
+		return "", err
+	}
+	if !isMockInterviewChatEvent(req.Event) {
+		return "", m.updateMockInterviewStatus(ctx, mockInterview, nextStatus)
+	}
+	questions, err := m.listMockInterviewQuestions(ctx, mockInterview)
+	if err != nil {
+		return "", err
+	}
+	conversation, err := m.buildMockInterviewConversation(ctx, mockInterview, questions, req)
+	if err != nil {
+		return "", err
+	}
+	m.compactMockInterviewContext(ctx, mockInterview.ID, conversation)
+	// 调用AI接口
+	resp, err := m.llmClient.Chat(ctx, &llm.Request{Messages: toLLMMessages(conversation.request)})
+	if err != nil {
+		m.logger.WithContext(ctx).Error("mock interview chat error", zap.Uint64("id", req.ID), zap.Error(err))
+		return "", v1.ErrMockInterviewChat
+	}
+	if err = m.saveMockInterviewReply(ctx, mockInterview, questions, req.Event, conversation, resp); err != nil {
+		return "", err
+	}
+	return resp.Content, nil
+}
+
+// MockInterviewStream 流式进行模拟面试，每收到一段回复调用一次 onDelta；
+// 只有完整收到回复后才写入消息记录，中途失败或客户端断开时面试记录保持不变
+func (m mockInterviewService) MockInterviewStream(ctx context.Context, req *v1.MockInterviewEventRequest, loginUser *jwt.User, onDelta func(delta string) error) (*v1.MockInterviewStreamResult, error) {
+	if !isMockInterviewChatEvent(req.Event) {
+		return nil, v1.ParamsError
+	}
+	mockInterview, err := m.getOwnMockInterview(ctx, req.ID, loginUser)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = nextMockInterviewStatus(mockInterview.Status, req.Event); err != nil {
+		return nil, err
+	}
+	questions, err := m.listMockInterviewQuestions(ctx, mockInterview)
+	if err != nil {
+		return nil, err
+	}
+	conversation, err := m.buildMockInterviewConversation(ctx, mockInterview, questions, req)
+	if err != nil {
+		return nil, err
+	}
+	m.compactMockInterviewContext(ctx, mockInterview.ID, conversation)
+	resp, err := m.llmClient.ChatStream(ctx, &llm.Request{Messages: toLLMMessages(conversation.request)}, onDelta)
+	if err != nil {
+		if errors.Is(err, llm.ErrCanceled) {
+			return nil, err
 		}
-		// 将AI的回复记录到数据库
-		mockInterview.ID = req.ID
-		mockInterview.Messages = string(jsonStr)
-		mockInterview.Status = 2 // 结束
-		err = m.mockInterviewRepository.UpdateMockInterview(ctx, mockInterview)
-		if err != nil {
+		m.logger.WithContext(ctx).Error("mock interview chat stream error", zap.Uint64("id", req.ID), zap.Error(err))
+		return nil, v1.ErrMockInterviewChat
+	}
+	// 回复已完整推送，客户端此时断开也要保存，避免丢失本轮对话
+	if err = m.saveMockInterviewReply(context.WithoutCancel(ctx), mockInterview, questions, req.Event, conversation, resp); err != nil {
+		return nil, err
+	}
+	return &v1.MockInterviewStreamResult{
+		Content: resp.Content,
+		Status:  mockInterview.Status,
+	}, nil
+}
+
+// getOwnMockInterview 获取当前用户自己的模拟面试，面试事件只能由本人发起
+func (m mockInterviewService) getOwnMockInterview(ctx context.Context, id uint64, loginUser *jwt.User) (*model.MockInterview, error) {
+	mockInterview, err := m.mockInterviewRepository.GetMockInterview(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if mockInterview.UserID != loginUser.ID {
+		return nil, v1.ErrUnauthorized
+	}
+	return mockInterview, nil
+}
+
+// updateMockInterviewStatus 以读取时的状态为条件保存面试，期间状态被其他请求或超时任务修改时返回错误
+func (m mockInterviewService) updateMockInterviewStatus(ctx context.Context, mockInterview *model.MockInterview, status int) error {
+	fromStatus := mockInterview.Status
+	mockInterview.Status = status
+	ok, err := m.mockInterviewRepository.UpdateMockInterviewByStatus(ctx, mockInterview, fromStatus)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return v1.ErrMockInterviewStateChanged
+	}
+	return nil
+}
+
+// mockInterviewSystemPrompt 使用创建面试时记录的模板版本构造面试官的系统 Prompt，没有记录版本时使用该风格的内置模板
+func (m mockInterviewService) mockInterviewSystemPrompt(ctx context.Context, mockInterview *model.MockInterview, questions []repository.MockInterviewQuestionDetail) (string, error) {
+	var promptTemplate *model.PromptTemplate
+	if mockInterview.PromptTemplateID != nil {
+		var err error
+		if promptTemplate, err = m.promptTemplateRepository.GetByID(ctx, *mockInterview.PromptTemplateID); err != nil {
 			return "", err
 		}
-		return result, nil
+	} else if promptTemplate = defaultPromptTemplate(constant.PromptSceneMockInterview, mockInterview.Persona); promptTemplate == nil {
+		promptTemplate = defaultPromptTemplate(constant.PromptSceneMockInterview, constant.MockInterviewPersonaStrict)
+	}
+	return renderPromptTemplate(promptTemplate.Content, mockInterviewPromptVars{
+		WorkExperience: mockInterview.WorkExperience,
+		JobPosition:    mockInterview.JobPosition,
+		Difficulty:     mockInterview.Difficulty,
+		Questions:      mockInterviewPromptQuestions(questions),
+	})
+}
+
+// toLLMMessages 转换为发送给大模型的消息
+func toLLMMessages(messages []model.MockInterviewContextMessage) []llm.Message {
+	result := make([]llm.Message, 0, len(messages))
+	for _, message := range messages {
+		result = append(result, llm.Message{Role: message.Role, Content: message.Content})
 	}
+	return result
+}
 
-	return "", nil
+// saveMockInterviewReply 将本轮消息和 AI 的回复追加到消息表，更新压缩上下文，并根据事件更新面试状态
+func (m mockInterviewService) saveMockInterviewReply(ctx context.Context, mockInterview *model.MockInterview, questions []repository.MockInterviewQuestionDetail, event string, conversation *mockInterviewConversation, resp *llm.Response) error {
+	result := resp.Content
+	conversation.append(model.MockInterviewMessage{
+		Role:             llm.RoleAssistant,
+		Content:          result,
+		QuestionID:       replyQuestionId(result, questions, conversation.context),
+		Tokens:           resp.Usage.CompletionTokens,
+		PromptTokens:     resp.Usage.PromptTokens,
+		CompletionTokens: resp.Usage.CompletionTokens,
+		LatencyMs:        resp.Latency.Milliseconds(),
+		ModelName:        resp.Model,
+	})
+	contextJson, err := json.Marshal(conversation.context)
+	if err != nil {
+		return err
+	}
+	status, err := nextMockInterviewStatus(mockInterview.Status, event)
+	if err != nil {
+		return err
+	}
+	// 面试官主动结束面试
+	if event == constant.MockInterviewEventChat && strings.Contains(result, constant.MockInterviewEndMarker) {
+		status = constant.MockInterviewStatusFinished
+	}
+	// 将AI的回复记录到数据库
+	mockInterview.Context = string(contextJson)
+	mockInterview.SummarizedNum = conversation.summarizedNum
+	err = m.tm.Transaction(ctx, func(ctx context.Context) error {
+		if err := m.updateMockInterviewStatus(ctx, mockInterview, status); err != nil {
+			return err
+		}
+		return m.mockInterviewMessageRepository.Append(ctx, mockInterview.ID, conversation.pending)
+	})
+	if err != nil {
+		return err
+	}
+	// 面试结束后生成评估报告
+	if mockInterview.Status == constant.MockInterviewStatusFinished {
+		m.generateMockInterviewReportAsync(mockInterview)
+	}
+	return nil
 }
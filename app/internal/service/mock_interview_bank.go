@@ -0,0 +1,144 @@
+package service
+
+import (
+	"app/internal/model"
+	"app/internal/repository"
+	"app/pkg/constant"
+	"app/pkg/llm"
+	"context"
+	"math"
+	"math/rand"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// mockInterviewQuestionMarker 题库模式下面试官提问时标注的题号，如【第 3 题】
+var mockInterviewQuestionMarker = regexp.MustCompile(`【第\s*(\d+)\s*题】`)
+
+// sampleBankQuestions 从题库中按优先级加权随机抽取题目，优先抽取近期未练习过的题目，不足时再从练习过的题目中补充
+func (m mockInterviewService) sampleBankQuestions(ctx context.Context, questionBankId uint64, userId uint64, num int) ([]uint64, error) {
+	candidates, err := m.mockInterviewQuestionRepository.ListCandidate(ctx, questionBankId)
+	if err != nil {
+		return nil, err
+	}
+	since := time.Now().AddDate(0, 0, -constant.MockInterviewRecentPracticeDays)
+	practicedIds, err := m.mockInterviewQuestionRepository.ListPracticedQuestionId(ctx, userId, since)
+	if err != nil {
+		return nil, err
+	}
+	practicedSet := make(map[uint64]struct{}, len(practicedIds))
+	for _, id := range practicedIds {
+		practicedSet[id] = struct{}{}
+	}
+	var fresh, practiced []repository.MockInterviewQuestionCandidate
+	for _, candidate := range candidates {
+		if _, ok := practicedSet[candidate.QuestionID]; ok {
+			practiced = append(practiced, candidate)
+		} else {
+			fresh = append(fresh, candidate)
+		}
+	}
+	questionIds := weightedSample(fresh, num)
+	if len(questionIds) < num {
+		questionIds = append(questionIds, weightedSample(practiced, num-len(questionIds))...)
+	}
+	return questionIds, nil
+}
+
+// weightedSample 按优先级加权无放回抽样（A-ES 算法），权重为优先级加 1，负优先级按 0 处理
+func weightedSample(candidates []repository.MockInterviewQuestionCandidate, num int) []uint64 {
+	type keyed struct {
+		id  uint64
+		key float64
+	}
+	items := make([]keyed, 0, len(candidates))
+	for _, candidate := range candidates {
+		weight := float64(max(candidate.Priority, 0) + 1)
+		items = append(items, keyed{id: candidate.QuestionID, key: math.Pow(rand.Float64(), 1/weight)})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].key > items[j].key
+	})
+	ids := make([]uint64, 0, min(num, len(items)))
+	for i := 0; i < num && i < len(items); i++ {
+		ids = append(ids, items[i].id)
+	}
+	return ids
+}
+
+// listMockInterviewQuestions 获取题库模式下抽取的题目，非题库模式返回空
+func (m mockInterviewService) listMockInterviewQuestions(ctx context.Context, mockInterview *model.MockInterview) ([]repository.MockInterviewQuestionDetail, error) {
+	if mockInterview.QuestionBankID == nil {
+		return nil, nil
+	}
+	return m.mockInterviewQuestionRepository.ListByMockInterviewId(ctx, mockInterview.ID)
+}
+
+// mockInterviewPromptQuestions 构造系统 Prompt 中的题目列表，题目描述和参考答案按最大长度截断
+func mockInterviewPromptQuestions(questions []repository.MockInterviewQuestionDetail) []mockInterviewPromptQuestion {
+	result := make([]mockInterviewPromptQuestion, 0, len(questions))
+	for _, question := range questions {
+		title := strings.TrimSpace(derefString(question.Title))
+		promptQuestion := mockInterviewPromptQuestion{Sequence: question.Sequence, Title: title}
+		if content := strings.TrimSpace(derefString(question.Content)); content != title {
+			promptQuestion.Content = truncateRunes(content, constant.MockInterviewReferenceAnswerMaxLen)
+		}
+		if answer := strings.TrimSpace(derefString(question.Answer)); answer != "" {
+			promptQuestion.Answer = truncateRunes(answer, constant.MockInterviewReferenceAnswerMaxLen)
+		}
+		result = append(result, promptQuestion)
+	}
+	return result
+}
+
+// currentQuestionId 获取最近一次面试官提问对应的题目
+func currentQuestionId(messages []model.MockInterviewContextMessage) *uint64 {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == llm.RoleAssistant {
+			return messages[i].QuestionID
+		}
+	}
+	return nil
+}
+
+// replyQuestionId 获取面试官回复对应的题目：回复中标注了题号时取最后一个题号，结束面试时不关联，否则沿用当前题目
+func replyQuestionId(reply string, questions []repository.MockInterviewQuestionDetail, messages []model.MockInterviewContextMessage) *uint64 {
+	if len(questions) == 0 {
+		return nil
+	}
+	if matches := mockInterviewQuestionMarker.FindAllStringSubmatch(reply, -1); len(matches) > 0 {
+		sequence, err := strconv.Atoi(matches[len(matches)-1][1])
+		if err == nil {
+			for _, question := range questions {
+				if question.Sequence == sequence {
+					questionId := question.QuestionID
+					return &questionId
+				}
+			}
+		}
+	}
+	if strings.Contains(reply, constant.MockInterviewEndMarker) {
+		return nil
+	}
+	return currentQuestionId(messages)
+}
+
+// truncateRunes 按字符截断字符串
+func truncateRunes(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n]) + "…"
+}
+
+// derefString 取字符串指针的值，nil 时返回空字符串
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
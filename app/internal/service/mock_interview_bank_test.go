@@ -0,0 +1,52 @@
+package service
+
+import (
+	"app/internal/repository"
+	"testing"
+)
+
+func TestWeightedSample(t *testing.T) {
+	candidates := []repository.MockInterviewQuestionCandidate{
+		{QuestionID: 1, Priority: 9},
+		{QuestionID: 2, Priority: 0},
+		{QuestionID: 3, Priority: -5},
+		{QuestionID: 4, Priority: 0},
+	}
+	tests := []struct {
+		candidates []repository.MockInterviewQuestionCandidate
+		num        int
+		wantLen    int
+	}{
+		{nil, 3, 0},
+		{candidates, 0, 0},
+		{candidates, 2, 2},
+		{candidates, 4, 4},
+		{candidates, 10, 4},
+	}
+	for _, tt := range tests {
+		ids := weightedSample(tt.candidates, tt.num)
+		if len(ids) != tt.wantLen {
+			t.Fatalf("weightedSample(%d candidates, %d) returned %d ids, want %d", len(tt.candidates), tt.num, len(ids), tt.wantLen)
+		}
+		seen := make(map[uint64]bool)
+		for _, id := range ids {
+			if seen[id] || id < 1 || id > 4 {
+				t.Fatalf("weightedSample() = %v, want distinct candidate ids", ids)
+			}
+			seen[id] = true
+		}
+	}
+
+	// 权重 10 的题目作为第一题的概率约为 10/13，负优先级与优先级 0 权重相同
+	const rounds = 2000
+	first := make(map[uint64]int)
+	for i := 0; i < rounds; i++ {
+		first[weightedSample(candidates, 1)[0]]++
+	}
+	if first[1] < rounds*6/10 {
+		t.Errorf("high priority question picked first %d/%d times, want at least 60%%", first[1], rounds)
+	}
+	if first[3] == 0 {
+		t.Errorf("negative priority question never picked first in %d rounds", rounds)
+	}
+}
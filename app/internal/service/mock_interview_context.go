@@ -0,0 +1,197 @@
+package service
+
+import (
+	v1 "app/api/v1"
+	"app/internal/model"
+	"app/internal/repository"
+	"app/pkg/constant"
+	"app/pkg/llm"
+	"context"
+	"encoding/json"
+	"fmt"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+// mockInterviewSummaryPrefix 滚动摘要消息的前缀
+const mockInterviewSummaryPrefix = "以下是此前面试过程的摘要，原始对话已省略：\n"
+
+// mockInterviewSummaryPrompt 将较早对话合并为滚动摘要的系统 Prompt
+var mockInterviewSummaryPrompt = fmt.Sprintf("你是模拟面试的记录员。请将已有摘要和新增的面试对话合并为一段新的摘要，供面试官继续面试时参考。\n"+
+	"要求：\n"+
+	"1. 保留面试官已经提出的问题（带有【第 N 题】题号的要保留题号）、候选人回答的要点和明显错误、面试官的评价和追问方向\n"+
+	"2. 不要编造对话中没有的内容，不要给出新的问题\n"+
+	"3. 使用纯文本，不超过 %d 字", constant.MockInterviewSummaryMaxLen)
+
+// mockInterviewConversation 一轮面试对话的消息：pending 为本轮新增、待追加到消息表的消息，context 为持久化的压缩上下文，
+// request 为本轮实际发送给 AI 的消息，通常与 context 相同
+type mockInterviewConversation struct {
+	pending       []model.MockInterviewMessage
+	context       []model.MockInterviewContextMessage
+	request       []model.MockInterviewContextMessage
+	summarizedNum int
+}
+
+// append 将消息同时追加到待写入的消息和压缩上下文，未给出 token 数时按估算值记录
+func (c *mockInterviewConversation) append(message model.MockInterviewMessage) {
+	if message.Tokens <= 0 {
+		message.Tokens = llm.EstimateMessageTokens(llm.Message{Role: message.Role, Content: message.Content})
+	}
+	if message.CreateTime.IsZero() {
+		message.CreateTime = time.Now()
+	}
+	c.pending = append(c.pending, message)
+	c.context = append(c.context, toMockInterviewContextMessage(message))
+	c.request = c.context
+}
+
+// toMockInterviewContextMessage 转换为压缩上下文中的消息
+func toMockInterviewContextMessage(message model.MockInterviewMessage) model.MockInterviewContextMessage {
+	return model.MockInterviewContextMessage{
+		Role:       message.Role,
+		Content:    message.Content,
+		QuestionID: message.QuestionID,
+		Tokens:     message.Tokens,
+	}
+}
+
+// buildMockInterviewConversation 根据事件构造本轮对话：开始时为系统预设加 “开始”，其余为历史消息加本轮用户消息；
+// 系统预设由创建面试时记录的模板渲染，用户消息关联到正在回答的题目
+func (m mockInterviewService) buildMockInterviewConversation(ctx context.Context, mockInterview *model.MockInterview, questions []repository.MockInterviewQuestionDetail, req *v1.MockInterviewEventRequest) (*mockInterviewConversation, error) {
+	conversation := &mockInterviewConversation{}
+	var userPrompt string
+	switch req.Event {
+	// 开始模拟面试
+	case constant.MockInterviewEventStart:
+		userPrompt = "开始"
+		// 添加系统预设
+		systemPrompt, err := m.mockInterviewSystemPrompt(ctx, mockInterview, questions)
+		if err != nil {
+			return nil, err
+		}
+		conversation.append(model.MockInterviewMessage{
+			Role:    llm.RoleSystem,
+			Content: systemPrompt,
+		})
+	// 进行或结束模拟面试
+	case constant.MockInterviewEventChat, constant.MockInterviewEventEnd:
+		userPrompt = req.Message
+		if req.Event == constant.MockInterviewEventEnd {
+			userPrompt = "结束"
+		}
+		// 将压缩上下文反序列化，没有压缩上下文的旧记录以完整记录作为上下文
+		if mockInterview.Context != "" {
+			if err := json.Unmarshal([]byte(mockInterview.Context), &conversation.context); err != nil {
+				return nil, err
+			}
+		} else {
+			history, err := m.mockInterviewMessageRepository.ListByMockInterviewId(ctx, mockInterview.ID)
+			if err != nil {
+				return nil, err
+			}
+			for _, message := range history {
+				conversation.context = append(conversation.context, toMockInterviewContextMessage(message))
+			}
+		}
+		conversation.request = conversation.context
+		conversation.summarizedNum = mockInterview.SummarizedNum
+	}
+	// 添加用户 Prompt
+	conversation.append(model.MockInterviewMessage{
+		Role:       llm.RoleUser,
+		Content:    userPrompt,
+		QuestionID: currentQuestionId(conversation.context),
+	})
+	return conversation, nil
+}
+
+// compactMockInterviewContext 上下文超过 token 预算时，保留系统 Prompt 和最近几轮对话，将更早的对话与已有摘要合并为新的滚动摘要；
+// 生成摘要失败时本轮只发送最近几轮对话，压缩上下文保持不变，下一轮再尝试
+func (m mockInterviewService) compactMockInterviewContext(ctx context.Context, mockInterviewId uint64, conversation *mockInterviewConversation) {
+	if countMockInterviewTokens(conversation.context) <= constant.MockInterviewContextMaxTokens {
+		return
+	}
+	var head []model.MockInterviewContextMessage
+	body := conversation.context
+	if len(body) > 0 && body[0].Role == llm.RoleSystem && !body[0].Summary {
+		head, body = body[:1], body[1:]
+	}
+	var previous []model.MockInterviewContextMessage
+	if len(body) > 0 && body[0].Summary {
+		previous, body = body[:1], body[1:]
+	}
+	// 保留最近几轮对话和本轮的用户消息
+	keep := 2*constant.MockInterviewContextKeepTurns + 1
+	if len(body) <= keep {
+		return
+	}
+	older, recent := body[:len(body)-keep], body[len(body)-keep:]
+	summary, err := m.summarizeMockInterview(ctx, previous, older)
+	if err != nil {
+		m.logger.WithContext(ctx).Warn("summarize mock interview context error", zap.Uint64("id", mockInterviewId), zap.Error(err))
+		conversation.request = concatMockInterviewMessages(head, previous, recent)
+		return
+	}
+	content := mockInterviewSummaryPrefix + summary
+	summaryMessage := model.MockInterviewContextMessage{
+		Role:    llm.RoleSystem,
+		Content: content,
+		Tokens:  llm.EstimateMessageTokens(llm.Message{Role: llm.RoleSystem, Content: content}),
+		Summary: true,
+	}
+	conversation.context = concatMockInterviewMessages(head, []model.MockInterviewContextMessage{summaryMessage}, recent)
+	conversation.request = conversation.context
+	conversation.summarizedNum += len(older)
+}
+
+// summarizeMockInterview 将已有摘要和较早的对话合并为新的摘要
+func (m mockInterviewService) summarizeMockInterview(ctx context.Context, previous []model.MockInterviewContextMessage, older []model.MockInterviewContextMessage) (string, error) {
+	var sb strings.Builder
+	if len(previous) > 0 {
+		fmt.Fprintf(&sb, "已有摘要：\n%s\n\n", strings.TrimPrefix(previous[0].Content, mockInterviewSummaryPrefix))
+	}
+	sb.WriteString("新增对话：\n")
+	for _, message := range older {
+		switch message.Role {
+		case llm.RoleAssistant:
+			fmt.Fprintf(&sb, "面试官：%s\n", message.Content)
+		case llm.RoleUser:
+			fmt.Fprintf(&sb, "候选人：%s\n", message.Content)
+		}
+	}
+	temperature := float32(0.2)
+	resp, err := m.llmClient.Chat(ctx, &llm.Request{
+		Messages: []llm.Message{
+			{Role: llm.RoleSystem, Content: mockInterviewSummaryPrompt},
+			{Role: llm.RoleUser, Content: sb.String()},
+		},
+		Temperature: &temperature,
+	})
+	if err != nil {
+		return "", err
+	}
+	return truncateRunes(strings.TrimSpace(resp.Content), constant.MockInterviewSummaryMaxLen), nil
+}
+
+// countMockInterviewTokens 统计消息的 token 数，旧记录没有 token 数时按估算值计算
+func countMockInterviewTokens(messages []model.MockInterviewContextMessage) int {
+	total := 0
+	for _, message := range messages {
+		if message.Tokens > 0 {
+			total += message.Tokens
+		} else {
+			total += llm.EstimateMessageTokens(llm.Message{Role: message.Role, Content: message.Content})
+		}
+	}
+	return total
+}
+
+// concatMockInterviewMessages 拼接消息到新的切片，避免与原切片共用底层数组
+func concatMockInterviewMessages(parts ...[]model.MockInterviewContextMessage) []model.MockInterviewContextMessage {
+	var result []model.MockInterviewContextMessage
+	for _, part := range parts {
+		result = append(result, part...)
+	}
+	return result
+}
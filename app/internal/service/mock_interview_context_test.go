@@ -0,0 +1,136 @@
+package service
+
+import (
+	"app/internal/model"
+	"app/pkg/constant"
+	"app/pkg/llm"
+	"app/pkg/log"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// newTestConversation 构造系统 Prompt 加 n 条交替的面试官、候选人消息，每条消息的 token 数为 tokens
+func newTestConversation(n int, tokens int, summary string) *mockInterviewConversation {
+	messages := []model.MockInterviewContextMessage{{Role: llm.RoleSystem, Content: "system", Tokens: 10}}
+	if summary != "" {
+		messages = append(messages, model.MockInterviewContextMessage{Role: llm.RoleSystem, Content: mockInterviewSummaryPrefix + summary, Tokens: 10, Summary: true})
+	}
+	for i := 0; i < n; i++ {
+		role := llm.RoleAssistant
+		if i%2 == 1 {
+			role = llm.RoleUser
+		}
+		messages = append(messages, model.MockInterviewContextMessage{Role: role, Content: fmt.Sprintf("message %d", i), Tokens: tokens})
+	}
+	return &mockInterviewConversation{context: messages, request: messages, summarizedNum: 3}
+}
+
+func TestCompactMockInterviewContext(t *testing.T) {
+	keep := 2*constant.MockInterviewContextKeepTurns + 1
+	unavailable := &llm.Error{Provider: "fake", Kind: llm.ErrUnavailable, Err: errors.New("busy")}
+	tests := []struct {
+		name             string
+		conversation     *mockInterviewConversation
+		reply            llm.FakeReply
+		wantCalls        int
+		wantContext      []string
+		wantRequest      []string
+		wantSummarized   int
+		wantPromptPrefix string
+	}{
+		{
+			name:           "within budget",
+			conversation:   newTestConversation(keep+2, 10, ""),
+			wantContext:    []string{"system"},
+			wantSummarized: 3,
+		},
+		{
+			name:           "too few messages to compact",
+			conversation:   newTestConversation(keep, constant.MockInterviewContextMaxTokens, ""),
+			wantContext:    []string{"system"},
+			wantSummarized: 3,
+		},
+		{
+			name:             "summarize older messages",
+			conversation:     newTestConversation(keep+3, constant.MockInterviewContextMaxTokens/keep, ""),
+			reply:            llm.FakeReply{Content: " 新摘要 "},
+			wantCalls:        1,
+			wantContext:      []string{"system", mockInterviewSummaryPrefix + "新摘要", "message 3"},
+			wantRequest:      []string{"system", mockInterviewSummaryPrefix + "新摘要", "message 3"},
+			wantSummarized:   6,
+			wantPromptPrefix: "新增对话：\n面试官：message 0\n候选人：message 1\n面试官：message 2\n",
+		},
+		{
+			name:             "merge previous summary",
+			conversation:     newTestConversation(keep+1, constant.MockInterviewContextMaxTokens/keep, "旧摘要"),
+			reply:            llm.FakeReply{Content: "新摘要"},
+			wantCalls:        1,
+			wantContext:      []string{"system", mockInterviewSummaryPrefix + "新摘要", "message 1"},
+			wantRequest:      []string{"system", mockInterviewSummaryPrefix + "新摘要", "message 1"},
+			wantSummarized:   4,
+			wantPromptPrefix: "已有摘要：\n旧摘要\n\n新增对话：\n面试官：message 0\n",
+		},
+		{
+			name:           "summary error only trims request",
+			conversation:   newTestConversation(keep+3, constant.MockInterviewContextMaxTokens/keep, "旧摘要"),
+			reply:          llm.FakeReply{Err: unavailable},
+			wantCalls:      1,
+			wantContext:    []string{"system", mockInterviewSummaryPrefix + "旧摘要", "message 0"},
+			wantRequest:    []string{"system", mockInterviewSummaryPrefix + "旧摘要", "message 3"},
+			wantSummarized: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := llm.NewFakeClient(tt.reply)
+			m := mockInterviewService{
+				Service:   &Service{logger: &log.Logger{Logger: zap.NewNop()}},
+				llmClient: fake,
+			}
+			before := len(tt.conversation.context)
+			m.compactMockInterviewContext(context.Background(), 1, tt.conversation)
+
+			requests := fake.Requests()
+			if len(requests) != tt.wantCalls {
+				t.Fatalf("calls = %d, want %d", len(requests), tt.wantCalls)
+			}
+			if tt.wantPromptPrefix != "" && !strings.HasPrefix(requests[0].Messages[1].Content, tt.wantPromptPrefix) {
+				t.Errorf("summary prompt = %q, want prefix %q", requests[0].Messages[1].Content, tt.wantPromptPrefix)
+			}
+			assertMessagePrefix(t, "context", tt.conversation.context, tt.wantContext)
+			if tt.wantRequest != nil {
+				assertMessagePrefix(t, "request", tt.conversation.request, tt.wantRequest)
+				if got := len(tt.conversation.request); got > len(tt.wantRequest)-1+keep {
+					t.Errorf("request has %d messages, want at most %d", got, len(tt.wantRequest)-1+keep)
+				}
+			} else if len(tt.conversation.context) != before || len(tt.conversation.request) != before {
+				t.Errorf("conversation changed: context %d, request %d, want %d", len(tt.conversation.context), len(tt.conversation.request), before)
+			}
+			if tt.conversation.summarizedNum != tt.wantSummarized {
+				t.Errorf("summarizedNum = %d, want %d", tt.conversation.summarizedNum, tt.wantSummarized)
+			}
+			last := tt.conversation.request[len(tt.conversation.request)-1]
+			if last.Content != tt.conversation.context[len(tt.conversation.context)-1].Content {
+				t.Errorf("last request message = %q, want latest message kept", last.Content)
+			}
+		})
+	}
+}
+
+// assertMessagePrefix 检查消息列表以 want 中的内容开头
+func assertMessagePrefix(t *testing.T, name string, messages []model.MockInterviewContextMessage, want []string) {
+	t.Helper()
+	if len(messages) < len(want) {
+		t.Fatalf("%s has %d messages, want at least %d", name, len(messages), len(want))
+	}
+	for i, content := range want {
+		if messages[i].Content != content {
+			t.Errorf("%s[%d] = %q, want %q", name, i, messages[i].Content, content)
+		}
+	}
+}
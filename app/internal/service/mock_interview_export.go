@@ -0,0 +1,310 @@
+package service
+
+import (
+	v1 "app/api/v1"
+	"app/internal/model"
+	"app/pkg/constant"
+	"app/pkg/jwt"
+	"app/pkg/llm"
+	"app/pkg/utils"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+// mockInterviewShareSubjectPrefix 分享 token 中分享对象标识的前缀
+const mockInterviewShareSubjectPrefix = "mockInterview:"
+
+// mockInterviewExportData 导出模板的数据
+type mockInterviewExportData struct {
+	JobPosition    string
+	WorkExperience string
+	Difficulty     string
+	StartTime      time.Time
+	Duration       string
+	Messages       []mockInterviewExportMessage
+	// 评估报告，未生成时为空
+	Report *v1.MockInterviewReportDetail
+	// 面试官的最终总结
+	Summary    string
+	ExportTime time.Time
+}
+
+// mockInterviewExportMessage 导出的一条对话
+type mockInterviewExportMessage struct {
+	Speaker string
+	Content string
+}
+
+var mockInterviewExportFuncs = map[string]any{
+	"quote": func(s string) string {
+		return "> " + strings.ReplaceAll(strings.TrimSpace(s), "\n", "\n> ")
+	},
+	"datetime": func(t time.Time) string {
+		return t.Format("2006-01-02 15:04")
+	},
+}
+
+var mockInterviewMarkdownTemplate = texttemplate.Must(texttemplate.New("markdown").Funcs(mockInterviewExportFuncs).Parse(`# 模拟面试记录：{{.JobPosition}}
+
+| 项目 | 内容 |
+| --- | --- |
+| 应聘岗位 | {{.JobPosition}} |
+| 工作年限 | {{.WorkExperience}} |
+| 面试难度 | {{.Difficulty}} |
+| 开始时间 | {{datetime .StartTime}} |
+| 面试时长 | {{.Duration}} |
+
+## 面试过程
+{{range .Messages}}
+**{{.Speaker}}**：
+
+{{quote .Content}}
+{{end}}
+## 面试总结
+{{if .Report}}
+总分：**{{.Report.OverallScore}}**（技术深度 {{.Report.Dimensions.TechnicalDepth.Score}} / 沟通表达 {{.Report.Dimensions.Communication.Score}} / 问题解决 {{.Report.Dimensions.ProblemSolving.Score}}）
+
+{{.Report.Summary}}
+{{if .Report.Strengths}}
+**优势**
+{{range .Report.Strengths}}
+- {{.}}{{end}}
+{{end}}{{if .Report.Weaknesses}}
+**不足**
+{{range .Report.Weaknesses}}
+- {{.}}{{end}}
+{{end}}{{else}}
+{{quote .Summary}}
+{{end}}
+---
+
+导出时间：{{datetime .ExportTime}}
+`))
+
+var mockInterviewHTMLTemplate = htmltemplate.Must(htmltemplate.New("html").Funcs(mockInterviewExportFuncs).Parse(`<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>模拟面试记录：{{.JobPosition}}</title>
+<style>
+  body { max-width: 800px; margin: 0 auto; padding: 32px 24px; font: 15px/1.7 -apple-system, "PingFang SC", "Microsoft YaHei", sans-serif; color: #1f2328; }
+  h1 { font-size: 24px; margin-bottom: 16px; }
+  h2 { font-size: 18px; margin-top: 32px; padding-bottom: 6px; border-bottom: 1px solid #d0d7de; }
+  table { border-collapse: collapse; }
+  td { padding: 4px 16px 4px 0; }
+  td:first-child { color: #656d76; }
+  .message { margin: 12px 0; padding: 10px 14px; border-radius: 6px; break-inside: avoid; }
+  .assistant { background: #f6f8fa; }
+  .user { background: #ddf4ff; }
+  .speaker { font-weight: 600; margin-bottom: 4px; }
+  .content, .summary { white-space: pre-wrap; }
+  .score { font-size: 20px; font-weight: 600; }
+  footer { margin-top: 32px; color: #656d76; font-size: 13px; }
+  @page { margin: 16mm; }
+  @media print { body { padding: 0; } .message { border: 1px solid #d0d7de; } }
+</style>
+</head>
+<body>
+<h1>模拟面试记录：{{.JobPosition}}</h1>
+<table>
+  <tr><td>应聘岗位</td><td>{{.JobPosition}}</td></tr>
+  <tr><td>工作年限</td><td>{{.WorkExperience}}</td></tr>
+  <tr><td>面试难度</td><td>{{.Difficulty}}</td></tr>
+  <tr><td>开始时间</td><td>{{datetime .StartTime}}</td></tr>
+  <tr><td>面试时长</td><td>{{.Duration}}</td></tr>
+</table>
+<h2>面试过程</h2>
+{{range .Messages}}<div class="message {{if eq .Speaker "面试官"}}assistant{{else}}user{{end}}">
+  <div class="speaker">{{.Speaker}}</div>
+  <div class="content">{{.Content}}</div>
+</div>
+{{end}}<h2>面试总结</h2>
+{{if .Report}}<p class="score">总分 {{.Report.OverallScore}}</p>
+<p>技术深度 {{.Report.Dimensions.TechnicalDepth.Score}} / 沟通表达 {{.Report.Dimensions.Communication.Score}} / 问题解决 {{.Report.Dimensions.ProblemSolving.Score}}</p>
+<div class="summary">{{.Report.Summary}}</div>
+{{if .Report.Strengths}}<h3>优势</h3>
+<ul>{{range .Report.Strengths}}<li>{{.}}</li>{{end}}</ul>
+{{end}}{{if .Report.Weaknesses}}<h3>不足</h3>
+<ul>{{range .Report.Weaknesses}}<li>{{.}}</li>{{end}}</ul>
+{{end}}{{else}}<div class="summary">{{.Summary}}</div>
+{{end}}<footer>导出时间：{{datetime .ExportTime}}</footer>
+</body>
+</html>
+`))
+
+// ExportMockInterview 导出已结束的模拟面试记录，只有本人或管理员可以导出
+func (m mockInterviewService) ExportMockInterview(ctx context.Context, req *v1.MockInterviewExportRequest, loginUser *jwt.User) (*v1.MockInterviewExportFile, error) {
+	format := req.Format
+	if format == "" {
+		format = constant.MockInterviewExportMarkdown
+	}
+	mockInterview, err := m.mockInterviewRepository.GetMockInterview(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if mockInterview.UserID != loginUser.ID && loginUser.UserRole != constant.AdminRole {
+		return nil, v1.ErrUnauthorized
+	}
+	return m.renderMockInterview(ctx, mockInterview, format)
+}
+
+// ShareMockInterview 为本人已结束的模拟面试生成有时效的分享链接，持有链接即可免登录查看
+func (m mockInterviewService) ShareMockInterview(ctx context.Context, req *v1.MockInterviewShareRequest, loginUser *jwt.User) (*v1.MockInterviewShareVO, error) {
+	expireHours := req.ExpireHours
+	if expireHours == 0 {
+		expireHours = constant.MockInterviewShareDefaultExpireHours
+	}
+	if expireHours < 0 || expireHours > constant.MockInterviewShareMaxExpireHours {
+		return nil, v1.ParamsError
+	}
+	mockInterview, err := m.getOwnMockInterview(ctx, req.ID, loginUser)
+	if err != nil {
+		return nil, err
+	}
+	if mockInterview.Status != constant.MockInterviewStatusFinished {
+		return nil, v1.ErrMockInterviewNotFinished
+	}
+	expireTime := time.Now().Add(time.Duration(expireHours) * time.Hour)
+	token, err := m.jwt.GenShareToken(mockInterviewShareSubjectPrefix+utils.Uint64TOString(mockInterview.ID), expireTime)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.MockInterviewShareVO{
+		Token:      token,
+		Path:       "/api/mockInterview/share/view?token=" + token,
+		ExpireTime: expireTime,
+	}, nil
+}
+
+// ViewSharedMockInterview 通过分享链接查看模拟面试记录
+func (m mockInterviewService) ViewSharedMockInterview(ctx context.Context, req *v1.MockInterviewShareViewRequest) (*v1.MockInterviewExportFile, error) {
+	format := req.Format
+	if format == "" {
+		format = constant.MockInterviewExportHTML
+	}
+	subject, err := m.jwt.ParseShareToken(req.Token)
+	if err != nil || !strings.HasPrefix(subject, mockInterviewShareSubjectPrefix) {
+		return nil, v1.ErrMockInterviewShareInvalid
+	}
+	id, err := utils.StringToUint64(strings.TrimPrefix(subject, mockInterviewShareSubjectPrefix))
+	if err != nil {
+		return nil, v1.ErrMockInterviewShareInvalid
+	}
+	mockInterview, err := m.mockInterviewRepository.GetMockInterview(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, v1.ErrMockInterviewShareInvalid
+		}
+		return nil, err
+	}
+	if mockInterview.IsDelete != 0 {
+		return nil, v1.ErrMockInterviewShareInvalid
+	}
+	return m.renderMockInterview(ctx, mockInterview, format)
+}
+
+// renderMockInterview 将已结束的面试渲染为 Markdown 或自包含的 HTML（可直接打印为 PDF）
+func (m mockInterviewService) renderMockInterview(ctx context.Context, mockInterview *model.MockInterview, format string) (*v1.MockInterviewExportFile, error) {
+	if format != constant.MockInterviewExportMarkdown && format != constant.MockInterviewExportHTML {
+		return nil, v1.ParamsError
+	}
+	if mockInterview.Status != constant.MockInterviewStatusFinished {
+		return nil, v1.ErrMockInterviewNotFinished
+	}
+	data, err := m.mockInterviewExportData(ctx, mockInterview)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	file := &v1.MockInterviewExportFile{}
+	if format == constant.MockInterviewExportHTML {
+		err = mockInterviewHTMLTemplate.Execute(&buf, data)
+		file.FileName = fmt.Sprintf("mock-interview-%d.html", mockInterview.ID)
+		file.ContentType = "text/html; charset=utf-8"
+	} else {
+		err = mockInterviewMarkdownTemplate.Execute(&buf, data)
+		file.FileName = fmt.Sprintf("mock-interview-%d.md", mockInterview.ID)
+		file.ContentType = "text/markdown; charset=utf-8"
+	}
+	if err != nil {
+		return nil, err
+	}
+	file.Content = buf.Bytes()
+	return file, nil
+}
+
+// mockInterviewExportData 整理导出所需的数据：对话不含系统 Prompt，总结优先使用评估报告，没有报告时使用面试官的最后一次回复
+func (m mockInterviewService) mockInterviewExportData(ctx context.Context, mockInterview *model.MockInterview) (*mockInterviewExportData, error) {
+	history, err := m.mockInterviewMessageRepository.ListByMockInterviewId(ctx, mockInterview.ID)
+	if err != nil {
+		return nil, err
+	}
+	data := &mockInterviewExportData{
+		JobPosition:    mockInterview.JobPosition,
+		WorkExperience: mockInterview.WorkExperience,
+		Difficulty:     mockInterview.Difficulty,
+		StartTime:      mockInterview.CreateTime,
+		ExportTime:     time.Now(),
+	}
+	var first, last time.Time
+	for _, message := range history {
+		var speaker string
+		switch message.Role {
+		case llm.RoleAssistant:
+			speaker = "面试官"
+			data.Summary = strings.TrimSpace(strings.ReplaceAll(message.Content, constant.MockInterviewEndMarker, ""))
+		case llm.RoleUser:
+			speaker = "候选人"
+		default:
+			continue
+		}
+		if first.IsZero() {
+			first = message.CreateTime
+		}
+		last = message.CreateTime
+		data.Messages = append(data.Messages, mockInterviewExportMessage{Speaker: speaker, Content: message.Content})
+	}
+	// 迁移的历史消息没有逐条的时间，按面试的创建和最后更新时间计算
+	if !first.Before(last) {
+		first, last = mockInterview.CreateTime, mockInterview.UpdateTime
+	}
+	if !first.IsZero() {
+		data.StartTime = first
+	}
+	data.Duration = formatMockInterviewDuration(last.Sub(first))
+
+	report, err := m.mockInterviewReportRepository.GetByMockInterviewId(ctx, mockInterview.ID)
+	if err != nil {
+		return nil, err
+	}
+	if report != nil {
+		vo, err := toMockInterviewReportVO(report)
+		if err != nil {
+			m.logger.WithContext(ctx).Warn("parse mock interview report error", zap.Uint64("id", mockInterview.ID), zap.Error(err))
+		} else {
+			data.Report = &vo.MockInterviewReportDetail
+		}
+	}
+	return data, nil
+}
+
+// formatMockInterviewDuration 将面试时长格式化为 “1 小时 5 分钟”
+func formatMockInterviewDuration(d time.Duration) string {
+	if d < time.Minute {
+		return "不足 1 分钟"
+	}
+	minutes := int(d.Minutes())
+	if minutes < 60 {
+		return fmt.Sprintf("%d 分钟", minutes)
+	}
+	return fmt.Sprintf("%d 小时 %d 分钟", minutes/60, minutes%60)
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	v1 "app/api/v1"
+	"app/internal/model"
+	"app/pkg/constant"
+	"app/pkg/jwt"
+	"app/pkg/llm"
+	"app/pkg/utils"
+	"context"
+	"encoding/json"
+)
+
+// ListMockInterviewMessage 分页获取模拟面试的对话记录，只有本人或管理员可以查看，不含系统 Prompt
+func (m mockInterviewService) ListMockInterviewMessage(ctx context.Context, req *v1.MockInterviewMessageQueryRequest, loginUser *jwt.User) (v1.QuestionQueryResponseData[v1.MockInterviewMessageVO], error) {
+	if req.Current == nil || req.PageSize == nil || *req.Current <= 0 || *req.PageSize <= 0 || *req.PageSize > constant.MockInterviewMessageMaxPageSize {
+		return v1.QuestionQueryResponseData[v1.MockInterviewMessageVO]{}, v1.ParamsError
+	}
+	mockInterview, err := m.mockInterviewRepository.GetMockInterview(ctx, req.MockInterviewID)
+	if err != nil {
+		return v1.QuestionQueryResponseData[v1.MockInterviewMessageVO]{}, err
+	}
+	if mockInterview.UserID != loginUser.ID && loginUser.UserRole != constant.AdminRole {
+		return v1.QuestionQueryResponseData[v1.MockInterviewMessageVO]{}, v1.ErrUnauthorized
+	}
+	current := req.Current
+	size := req.PageSize
+	messages, total, err := m.mockInterviewMessageRepository.ListDialogueByPage(ctx, mockInterview.ID, *current, *size)
+	if err != nil {
+		return v1.QuestionQueryResponseData[v1.MockInterviewMessageVO]{}, err
+	}
+	records := make([]v1.MockInterviewMessageVO, 0, len(messages))
+	for _, message := range messages {
+		records = append(records, toMockInterviewMessageVO(message))
+	}
+	pages := utils.GetPages(total, *size)
+	return v1.QuestionQueryResponseData[v1.MockInterviewMessageVO]{
+		Records: records,
+		Total:   &total,
+		Pages:   &pages,
+		Size:    size,
+		Current: current,
+	}, nil
+}
+
+// mockInterviewDialogueJson 将对话记录序列化为 JSON 对象数组，兼容原先 Messages 字段的格式，不含系统 Prompt
+func (m mockInterviewService) mockInterviewDialogueJson(ctx context.Context, mockInterviewId uint64) (string, error) {
+	history, err := m.mockInterviewMessageRepository.ListByMockInterviewId(ctx, mockInterviewId)
+	if err != nil {
+		return "", err
+	}
+	dialogue := make([]model.MockInterviewContextMessage, 0, len(history))
+	for _, message := range history {
+		if message.Role == llm.RoleSystem {
+			continue
+		}
+		dialogue = append(dialogue, toMockInterviewContextMessage(message))
+	}
+	jsonStr, err := json.Marshal(dialogue)
+	if err != nil {
+		return "", err
+	}
+	return string(jsonStr), nil
+}
+
+// toMockInterviewMessageVO 转换为对话消息视图
+func toMockInterviewMessageVO(message model.MockInterviewMessage) v1.MockInterviewMessageVO {
+	vo := v1.MockInterviewMessageVO{
+		Seq:              message.Seq,
+		Role:             message.Role,
+		Content:          message.Content,
+		Tokens:           message.Tokens,
+		PromptTokens:     message.PromptTokens,
+		CompletionTokens: message.CompletionTokens,
+		LatencyMs:        message.LatencyMs,
+		ModelName:        message.ModelName,
+		CreateTime:       message.CreateTime,
+	}
+	if message.QuestionID != nil {
+		questionId := utils.Uint64TOString(*message.QuestionID)
+		vo.QuestionID = &questionId
+	}
+	return vo
+}
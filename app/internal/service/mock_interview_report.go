@@ -0,0 +1,264 @@
+package service
+
+import (
+	v1 "app/api/v1"
+	"app/internal/model"
+	"app/pkg/constant"
+	"app/pkg/jwt"
+	"app/pkg/llm"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/xeipuuv/gojsonschema"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+// mockInterviewReportTimeout 面试结束后在后台生成评估报告的超时时间
+const mockInterviewReportTimeout = 3 * time.Minute
+
+// mockInterviewReportSchema 评估报告的 JSON Schema，与 v1.MockInterviewReportDetail 对应
+const mockInterviewReportSchema = `{
+  "type": "object",
+  "additionalProperties": false,
+  "required": ["overallScore", "dimensions", "questionAssessments", "strengths", "weaknesses", "recommendedTopics", "summary"],
+  "definitions": {
+    "score": {"type": "integer", "minimum": 0, "maximum": 100},
+    "dimension": {
+      "type": "object",
+      "additionalProperties": false,
+      "required": ["score", "comment"],
+      "properties": {
+        "score": {"$ref": "#/definitions/score"},
+        "comment": {"type": "string", "minLength": 1}
+      }
+    }
+  },
+  "properties": {
+    "overallScore": {"$ref": "#/definitions/score"},
+    "dimensions": {
+      "type": "object",
+      "additionalProperties": false,
+      "required": ["technicalDepth", "communication", "problemSolving"],
+      "properties": {
+        "technicalDepth": {"$ref": "#/definitions/dimension"},
+        "communication": {"$ref": "#/definitions/dimension"},
+        "problemSolving": {"$ref": "#/definitions/dimension"}
+      }
+    },
+    "questionAssessments": {
+      "type": "array",
+      "maxItems": 30,
+      "items": {
+        "type": "object",
+        "additionalProperties": false,
+        "required": ["question", "answerSummary", "score", "comment"],
+        "properties": {
+          "question": {"type": "string", "minLength": 1},
+          "answerSummary": {"type": "string"},
+          "score": {"$ref": "#/definitions/score"},
+          "comment": {"type": "string"}
+        }
+      }
+    },
+    "strengths": {"type": "array", "maxItems": 10, "items": {"type": "string", "minLength": 1}},
+    "weaknesses": {"type": "array", "maxItems": 10, "items": {"type": "string", "minLength": 1}},
+    "recommendedTopics": {"type": "array", "maxItems": 10, "items": {"type": "string", "minLength": 1}},
+    "summary": {"type": "string", "minLength": 1}
+  }
+}`
+
+var mockInterviewReportSchemaLoader = gojsonschema.NewStringLoader(mockInterviewReportSchema)
+
+// mockInterviewReportPrompt 生成评估报告的系统 Prompt
+var mockInterviewReportPrompt = "你是一位资深的技术面试评估专家。接下来我会给你一场模拟面试的完整记录，请你对候选人的表现进行客观评估。\n" +
+	"评估要求：\n" +
+	"1. 从技术深度（technicalDepth）、沟通表达（communication）、问题解决（problemSolving）三个维度打分，分数为 0-100 的整数，并给出评语\n" +
+	"2. 对面试官提出的每一道题目给出回答概要、得分和评语\n" +
+	"3. 总结候选人的优势（strengths）和不足（weaknesses）\n" +
+	"4. 给出建议加强的知识点（recommendedTopics），每个知识点用简短的关键词描述，例如 “Redis 持久化”\n" +
+	"5. 只输出一个 JSON 对象，不要输出 Markdown 代码块或任何其他内容，JSON 必须符合以下 JSON Schema：\n" +
+	mockInterviewReportSchema
+
+// GetMockInterviewReport 获取模拟面试评估报告，只有本人或管理员可以查看；报告尚未生成时返回生成中，没有进行中的生成任务时在后台重新生成
+func (m mockInterviewService) GetMockInterviewReport(ctx context.Context, req *v1.MockInterviewReportRequest, loginUser *jwt.User) (*v1.MockInterviewReport, error) {
+	mockInterview, err := m.mockInterviewRepository.GetMockInterview(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if mockInterview.UserID != loginUser.ID && loginUser.UserRole != constant.AdminRole {
+		return nil, v1.ErrUnauthorized
+	}
+	if mockInterview.Status != constant.MockInterviewStatusFinished {
+		return nil, v1.ErrMockInterviewNotFinished
+	}
+	report, err := m.mockInterviewReportRepository.GetByMockInterviewId(ctx, mockInterview.ID)
+	if err != nil {
+		return nil, err
+	}
+	if report == nil {
+		m.generateMockInterviewReportAsync(mockInterview)
+		return nil, v1.ErrMockInterviewReportGenerating
+	}
+	return toMockInterviewReportVO(report)
+}
+
+// generateMockInterviewReportAsync 在后台生成评估报告，已有生成中标记时跳过，生成结束后清除标记，失败时查看报告会重新生成
+func (m mockInterviewService) generateMockInterviewReportAsync(mockInterview *model.MockInterview) {
+	ok, err := m.mockInterviewReportRepository.MarkGenerating(context.Background(), mockInterview.ID, mockInterviewReportTimeout)
+	if err != nil {
+		m.logger.Error("mark mock interview report generating error", zap.Uint64("id", mockInterview.ID), zap.Error(err))
+		return
+	}
+	if !ok {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), mockInterviewReportTimeout)
+		defer cancel()
+		defer func() {
+			if err := m.mockInterviewReportRepository.ClearGenerating(context.Background(), mockInterview.ID); err != nil {
+				m.logger.Error("clear mock interview report generating error", zap.Uint64("id", mockInterview.ID), zap.Error(err))
+			}
+		}()
+		if _, err := m.generateMockInterviewReport(ctx, mockInterview); err != nil {
+			m.logger.Error("generate mock interview report error", zap.Uint64("id", mockInterview.ID), zap.Error(err))
+		}
+	}()
+}
+
+// generateMockInterviewReport 根据面试记录生成评估报告并保存；模型输出不符合 JSON Schema 时带上错误信息重试
+func (m mockInterviewService) generateMockInterviewReport(ctx context.Context, mockInterview *model.MockInterview) (*model.MockInterviewReport, error) {
+	history, err := m.mockInterviewMessageRepository.ListByMockInterviewId(ctx, mockInterview.ID)
+	if err != nil {
+		return nil, err
+	}
+	messages := []llm.Message{
+		{Role: llm.RoleSystem, Content: mockInterviewReportPrompt},
+		{Role: llm.RoleUser, Content: mockInterviewTranscript(mockInterview, history)},
+	}
+	temperature := float32(0.2)
+	var detail v1.MockInterviewReportDetail
+	var modelName string
+	for attempt := 1; ; attempt++ {
+		resp, err := m.llmClient.Chat(ctx, &llm.Request{Messages: messages, Temperature: &temperature})
+		if err != nil {
+			return nil, err
+		}
+		content := extractJSONObject(resp.Content)
+		problems := validateMockInterviewReport(content)
+		if len(problems) == 0 {
+			if err = json.Unmarshal([]byte(content), &detail); err != nil {
+				return nil, err
+			}
+			modelName = resp.Model
+			break
+		}
+		if attempt >= constant.MockInterviewReportMaxAttempts {
+			return nil, fmt.Errorf("report does not match schema after %d attempts: %s", attempt, strings.Join(problems, "; "))
+		}
+		m.logger.WithContext(ctx).Warn("mock interview report does not match schema, retry",
+			zap.Uint64("id", mockInterview.ID), zap.Int("attempt", attempt), zap.Strings("problems", problems))
+		messages = append(messages,
+			llm.Message{Role: llm.RoleAssistant, Content: resp.Content},
+			llm.Message{Role: llm.RoleUser, Content: "你的输出不符合 JSON Schema：" + strings.Join(problems, "；") + "。请修正后只输出 JSON 对象。"},
+		)
+	}
+
+	detailJson, err := json.Marshal(detail)
+	if err != nil {
+		return nil, err
+	}
+	recommendedJson, err := json.Marshal(m.recommendQuestions(ctx, &detail))
+	if err != nil {
+		return nil, err
+	}
+	report := &model.MockInterviewReport{
+		MockInterviewID:      mockInterview.ID,
+		UserID:               mockInterview.UserID,
+		OverallScore:         detail.OverallScore,
+		TechnicalDepth:       detail.Dimensions.TechnicalDepth.Score,
+		Communication:        detail.Dimensions.Communication.Score,
+		ProblemSolving:       detail.Dimensions.ProblemSolving.Score,
+		Detail:               string(detailJson),
+		RecommendedQuestions: string(recommendedJson),
+		ModelName:            modelName,
+		CreateTime:           time.Now(),
+	}
+	if err = m.mockInterviewReportRepository.Save(ctx, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// recommendQuestions 根据不足和建议加强的知识点从题库中检索相似的已审核题目，检索失败时不推荐
+func (m mockInterviewService) recommendQuestions(ctx context.Context, detail *v1.MockInterviewReportDetail) []v1.SimilarQuestionVO {
+	recommended := make([]v1.SimilarQuestionVO, 0)
+	text := strings.Join(append(append([]string{}, detail.RecommendedTopics...), detail.Weaknesses...), "\n")
+	vector, err := m.embeddingProvider.Embed(ctx, text)
+	if err != nil || vector == nil {
+		return recommended
+	}
+	questions, err := m.questionRepository.SearchSimilarQuestion(ctx, vector, constant.MockInterviewRecommendSize, 0, true)
+	if err != nil {
+		m.logger.WithContext(ctx).Warn("recommend questions for mock interview report error", zap.Error(err))
+		return recommended
+	}
+	return append(recommended, questions...)
+}
+
+// mockInterviewTranscript 将面试记录整理为纯文本，不含系统 Prompt
+func mockInterviewTranscript(mockInterview *model.MockInterview, history []model.MockInterviewMessage) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "应聘岗位：%s\n工作年限：%s\n面试难度：%s\n\n面试记录：\n", mockInterview.JobPosition, mockInterview.WorkExperience, mockInterview.Difficulty)
+	for _, message := range history {
+		switch message.Role {
+		case llm.RoleAssistant:
+			fmt.Fprintf(&sb, "面试官：%s\n", message.Content)
+		case llm.RoleUser:
+			fmt.Fprintf(&sb, "候选人：%s\n", message.Content)
+		}
+	}
+	return sb.String()
+}
+
+// extractJSONObject 去掉模型可能附带的代码块标记和前后说明，取出第一个 { 到最后一个 } 之间的内容
+func extractJSONObject(content string) string {
+	start, end := strings.Index(content, "{"), strings.LastIndex(content, "}")
+	if start < 0 || end < start {
+		return content
+	}
+	return content[start : end+1]
+}
+
+// validateMockInterviewReport 按 JSON Schema 校验评估报告，返回不符合的原因
+func validateMockInterviewReport(content string) []string {
+	result, err := gojsonschema.Validate(mockInterviewReportSchemaLoader, gojsonschema.NewStringLoader(content))
+	if err != nil {
+		return []string{"不是合法的 JSON：" + err.Error()}
+	}
+	problems := make([]string, 0, len(result.Errors()))
+	for _, e := range result.Errors() {
+		problems = append(problems, e.String())
+	}
+	return problems
+}
+
+// toMockInterviewReportVO 转换为评估报告视图
+func toMockInterviewReportVO(report *model.MockInterviewReport) (*v1.MockInterviewReport, error) {
+	vo := &v1.MockInterviewReport{
+		MockInterviewID:      report.MockInterviewID,
+		RecommendedQuestions: make([]v1.SimilarQuestionVO, 0),
+		CreateTime:           report.CreateTime,
+	}
+	if err := json.Unmarshal([]byte(report.Detail), &vo.MockInterviewReportDetail); err != nil {
+		return nil, err
+	}
+	if report.RecommendedQuestions != "" {
+		if err := json.Unmarshal([]byte(report.RecommendedQuestions), &vo.RecommendedQuestions); err != nil {
+			return nil, err
+		}
+	}
+	return vo, nil
+}
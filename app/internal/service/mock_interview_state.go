@@ -0,0 +1,66 @@
+package service
+
+import (
+	v1 "app/api/v1"
+	"app/pkg/constant"
+)
+
+// mockInterviewTransitions 模拟面试状态机：事件 -> 允许的当前状态 -> 事件处理后的状态
+// 待开始 -> 进行中 <-> 已暂停 -> 已结束/已放弃，已结束和已放弃为终态
+var mockInterviewTransitions = map[string]map[int]int{
+	constant.MockInterviewEventStart: {
+		constant.MockInterviewStatusPending: constant.MockInterviewStatusInProgress,
+	},
+	constant.MockInterviewEventChat: {
+		constant.MockInterviewStatusInProgress: constant.MockInterviewStatusInProgress,
+	},
+	constant.MockInterviewEventPause: {
+		constant.MockInterviewStatusInProgress: constant.MockInterviewStatusPaused,
+	},
+	constant.MockInterviewEventResume: {
+		constant.MockInterviewStatusPaused: constant.MockInterviewStatusInProgress,
+	},
+	constant.MockInterviewEventEnd: {
+		constant.MockInterviewStatusInProgress: constant.MockInterviewStatusFinished,
+		constant.MockInterviewStatusPaused:     constant.MockInterviewStatusFinished,
+	},
+	constant.MockInterviewEventAbandon: {
+		constant.MockInterviewStatusPending:    constant.MockInterviewStatusAbandoned,
+		constant.MockInterviewStatusInProgress: constant.MockInterviewStatusAbandoned,
+		constant.MockInterviewStatusPaused:     constant.MockInterviewStatusAbandoned,
+	},
+}
+
+// isMockInterviewEvent 判断是否为支持的面试事件
+func isMockInterviewEvent(event string) bool {
+	_, ok := mockInterviewTransitions[event]
+	return ok
+}
+
+// isMockInterviewChatEvent 判断事件是否需要调用 AI 生成面试官回复
+func isMockInterviewChatEvent(event string) bool {
+	return event == constant.MockInterviewEventStart || event == constant.MockInterviewEventChat || event == constant.MockInterviewEventEnd
+}
+
+// nextMockInterviewStatus 根据当前状态和事件计算下一个状态，不允许的状态转换返回对应的错误
+func nextMockInterviewStatus(status int, event string) (int, error) {
+	transitions, ok := mockInterviewTransitions[event]
+	if !ok {
+		return status, v1.ParamsError
+	}
+	if next, ok := transitions[status]; ok {
+		return next, nil
+	}
+	switch status {
+	case constant.MockInterviewStatusFinished, constant.MockInterviewStatusAbandoned:
+		return status, v1.ErrMockInterviewClosed
+	}
+	switch event {
+	case constant.MockInterviewEventStart:
+		return status, v1.ErrMockInterviewAlreadyStarted
+	case constant.MockInterviewEventResume:
+		return status, v1.ErrMockInterviewNotPaused
+	default:
+		return status, v1.ErrMockInterviewNotInProgress
+	}
+}
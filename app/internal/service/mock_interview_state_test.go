@@ -0,0 +1,52 @@
+package service
+
+import (
+	v1 "app/api/v1"
+	"app/pkg/constant"
+	"errors"
+	"testing"
+)
+
+func TestNextMockInterviewStatus(t *testing.T) {
+	const (
+		pending    = constant.MockInterviewStatusPending
+		inProgress = constant.MockInterviewStatusInProgress
+		finished   = constant.MockInterviewStatusFinished
+		paused     = constant.MockInterviewStatusPaused
+		abandoned  = constant.MockInterviewStatusAbandoned
+	)
+	tests := []struct {
+		status  int
+		event   string
+		want    int
+		wantErr error
+	}{
+		{pending, constant.MockInterviewEventStart, inProgress, nil},
+		{inProgress, constant.MockInterviewEventChat, inProgress, nil},
+		{inProgress, constant.MockInterviewEventPause, paused, nil},
+		{paused, constant.MockInterviewEventResume, inProgress, nil},
+		{inProgress, constant.MockInterviewEventEnd, finished, nil},
+		{paused, constant.MockInterviewEventEnd, finished, nil},
+		{pending, constant.MockInterviewEventAbandon, abandoned, nil},
+		{inProgress, constant.MockInterviewEventAbandon, abandoned, nil},
+		{paused, constant.MockInterviewEventAbandon, abandoned, nil},
+
+		{inProgress, constant.MockInterviewEventStart, inProgress, v1.ErrMockInterviewAlreadyStarted},
+		{paused, constant.MockInterviewEventStart, paused, v1.ErrMockInterviewAlreadyStarted},
+		{inProgress, constant.MockInterviewEventResume, inProgress, v1.ErrMockInterviewNotPaused},
+		{pending, constant.MockInterviewEventChat, pending, v1.ErrMockInterviewNotInProgress},
+		{paused, constant.MockInterviewEventChat, paused, v1.ErrMockInterviewNotInProgress},
+		{paused, constant.MockInterviewEventPause, paused, v1.ErrMockInterviewNotInProgress},
+		{pending, constant.MockInterviewEventEnd, pending, v1.ErrMockInterviewNotInProgress},
+		{finished, constant.MockInterviewEventChat, finished, v1.ErrMockInterviewClosed},
+		{finished, constant.MockInterviewEventAbandon, finished, v1.ErrMockInterviewClosed},
+		{abandoned, constant.MockInterviewEventStart, abandoned, v1.ErrMockInterviewClosed},
+		{inProgress, "unknown", inProgress, v1.ParamsError},
+	}
+	for _, tt := range tests {
+		got, err := nextMockInterviewStatus(tt.status, tt.event)
+		if got != tt.want || !errors.Is(err, tt.wantErr) {
+			t.Errorf("nextMockInterviewStatus(%d, %q) = %d, %v, want %d, %v", tt.status, tt.event, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
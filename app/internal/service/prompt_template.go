@@ -0,0 +1,292 @@
+package service
+
+import (
+	v1 "app/api/v1"
+	"app/internal/model"
+	"app/internal/repository"
+	"app/pkg/constant"
+	"app/pkg/utils"
+	"context"
+	"go.uber.org/zap"
+	"regexp"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+)
+
+// promptPersonaPattern 风格标识只允许小写字母、数字和下划线
+var promptPersonaPattern = regexp.MustCompile(`^[a-z0-9_]{1,32}$`)
+
+// PromptTemplateService Prompt 模板服务接口
+type PromptTemplateService interface {
+	// 保存模板，生成新版本
+	SavePromptTemplate(ctx context.Context, req *v1.SavePromptTemplateRequest, userId uint64) (string, error)
+	// 获取模板版本
+	GetPromptTemplate(ctx context.Context, req *v1.GetPromptTemplateRequest) (v1.PromptTemplateVO, error)
+	// 分页获取模板版本
+	ListPromptTemplateByPage(ctx context.Context, req *v1.PromptTemplateQueryRequest) (v1.QuestionQueryResponseData[v1.PromptTemplateVO], error)
+	// 停用场景、风格下的全部版本
+	DeletePromptTemplate(ctx context.Context, req *v1.DeletePromptTemplateRequest) (bool, error)
+	// 获取创建模拟面试时可选的面试官风格
+	ListMockInterviewPersona(ctx context.Context) ([]v1.MockInterviewPersonaVO, error)
+}
+
+// NewPromptTemplateService 创建 Prompt 模板服务实例
+func NewPromptTemplateService(
+	service *Service,
+	promptTemplateRepository repository.PromptTemplateRepository,
+) PromptTemplateService {
+	return &promptTemplateService{
+		Service:                  service,
+		promptTemplateRepository: promptTemplateRepository,
+	}
+}
+
+type promptTemplateService struct {
+	*Service
+	promptTemplateRepository repository.PromptTemplateRepository
+}
+
+// mockInterviewPromptVars 模拟面试模板可用的变量
+type mockInterviewPromptVars struct {
+	WorkExperience string                        // 工作年限
+	JobPosition    string                        // 工作岗位
+	Difficulty     string                        // 面试难度
+	Questions      []mockInterviewPromptQuestion // 题库模式下按顺序抽取的题目，自由出题时为空
+}
+
+// mockInterviewPromptQuestion 模拟面试模板中的题目
+type mockInterviewPromptQuestion struct {
+	Sequence int    // 题号
+	Title    string // 标题
+	Content  string // 题目描述，与标题相同时为空
+	Answer   string // 参考答案
+}
+
+// generateQuestionPromptVars AI 生成题目模板可用的变量
+type generateQuestionPromptVars struct {
+	Number    int    // 题目数量
+	Direction string // 题目方向
+}
+
+// renderPromptTemplate 渲染模板，使用不存在的变量时返回错误
+func renderPromptTemplate(content string, data any) (string, error) {
+	tmpl, err := template.New("prompt").Option("missingkey=error").Parse(content)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	if err = tmpl.Execute(&sb, data); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// getPromptTemplate 获取场景、风格最新的启用版本，数据库中没有时使用内置模板，都没有时返回 nil
+func getPromptTemplate(ctx context.Context, promptTemplateRepository repository.PromptTemplateRepository, scene string, persona string) (*model.PromptTemplate, error) {
+	promptTemplate, err := promptTemplateRepository.GetLatest(ctx, scene, persona)
+	if err != nil {
+		return nil, err
+	}
+	if promptTemplate == nil {
+		promptTemplate = defaultPromptTemplate(scene, persona)
+	}
+	return promptTemplate, nil
+}
+
+// validatePromptTemplate 用示例变量试渲染模板，模拟面试模板需要同时支持自由出题和题库模式，并保留结束标记
+func validatePromptTemplate(scene string, content string) error {
+	switch scene {
+	case constant.PromptSceneMockInterview:
+		vars := mockInterviewPromptVars{WorkExperience: "3 年", JobPosition: "Java 后端", Difficulty: "中等"}
+		if _, err := renderPromptTemplate(content, vars); err != nil {
+			return err
+		}
+		vars.Questions = []mockInterviewPromptQuestion{
+			{Sequence: 1, Title: "什么是 Java 中的反射？", Answer: "在运行时获取类的信息并操作对象"},
+			{Sequence: 2, Title: "Redis 持久化", Content: "Redis 有哪些持久化方式？", Answer: "RDB 和 AOF"},
+		}
+		if _, err := renderPromptTemplate(content, vars); err != nil {
+			return err
+		}
+		if !strings.Contains(content, constant.MockInterviewEndMarker) {
+			return v1.ErrPromptTemplateInvalid
+		}
+	case constant.PromptSceneGenerateQuestion:
+		if _, err := renderPromptTemplate(content, generateQuestionPromptVars{Number: 10, Direction: "Java"}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isPromptScene 是否为支持的场景
+func isPromptScene(scene string) bool {
+	return scene == constant.PromptSceneMockInterview || scene == constant.PromptSceneGenerateQuestion
+}
+
+// SavePromptTemplate 保存模板，版本号在该场景、风格的最大版本号上加 1，已停用的风格保存后重新启用
+func (s *promptTemplateService) SavePromptTemplate(ctx context.Context, req *v1.SavePromptTemplateRequest, userId uint64) (string, error) {
+	if req.Scene == nil || req.Persona == nil || req.Name == nil || req.Content == nil {
+		return "", v1.ParamsError
+	}
+	scene, persona := *req.Scene, *req.Persona
+	name := strings.TrimSpace(*req.Name)
+	if !isPromptScene(scene) || !promptPersonaPattern.MatchString(persona) {
+		return "", v1.ParamsError
+	}
+	// 生成题目只使用默认风格
+	if scene == constant.PromptSceneGenerateQuestion && persona != constant.PromptPersonaDefault {
+		return "", v1.ParamsError
+	}
+	if name == "" || utf8.RuneCountInString(name) > 128 {
+		return "", v1.ParamsError
+	}
+	var description string
+	if req.Description != nil {
+		description = strings.TrimSpace(*req.Description)
+		if utf8.RuneCountInString(description) > 512 {
+			return "", v1.ParamsError
+		}
+	}
+	content := *req.Content
+	if strings.TrimSpace(content) == "" || utf8.RuneCountInString(content) > constant.PromptTemplateMaxLen {
+		return "", v1.ErrPromptTemplateInvalid
+	}
+	if err := validatePromptTemplate(scene, content); err != nil {
+		s.logger.WithContext(ctx).Info("invalid prompt template", zap.String("scene", scene), zap.String("persona", persona), zap.Error(err))
+		return "", v1.ErrPromptTemplateInvalid
+	}
+
+	promptTemplate := &model.PromptTemplate{
+		Scene:       scene,
+		Persona:     persona,
+		Name:        name,
+		Description: description,
+		Content:     content,
+		EditorID:    userId,
+	}
+	err := s.tm.Transaction(ctx, func(ctx context.Context) error {
+		version, err := s.promptTemplateRepository.GetLatestVersion(ctx, scene, persona)
+		if err != nil {
+			return err
+		}
+		promptTemplate.Version = version + 1
+		return s.promptTemplateRepository.Create(ctx, promptTemplate)
+	})
+	if err != nil {
+		return "", err
+	}
+	return utils.Uint64TOString(promptTemplate.ID), nil
+}
+
+// GetPromptTemplate 获取模板版本，已停用的版本也可以查看
+func (s *promptTemplateService) GetPromptTemplate(ctx context.Context, req *v1.GetPromptTemplateRequest) (v1.PromptTemplateVO, error) {
+	if req.ID == nil {
+		return v1.PromptTemplateVO{}, v1.ParamsError
+	}
+	id, err := utils.StringToUint64(*req.ID)
+	if err != nil {
+		return v1.PromptTemplateVO{}, v1.ParamsError
+	}
+	promptTemplate, err := s.promptTemplateRepository.GetByID(ctx, id)
+	if err != nil {
+		return v1.PromptTemplateVO{}, err
+	}
+	return toPromptTemplateVO(promptTemplate), nil
+}
+
+// ListPromptTemplateByPage 分页获取模板版本
+func (s *promptTemplateService) ListPromptTemplateByPage(ctx context.Context, req *v1.PromptTemplateQueryRequest) (v1.QuestionQueryResponseData[v1.PromptTemplateVO], error) {
+	if req.Current == nil || req.PageSize == nil || *req.Current <= 0 || *req.PageSize <= 0 {
+		return v1.QuestionQueryResponseData[v1.PromptTemplateVO]{}, v1.ParamsError
+	}
+	var scene, persona string
+	if req.Scene != nil {
+		scene = *req.Scene
+	}
+	if req.Persona != nil {
+		persona = *req.Persona
+	}
+	templates, total, err := s.promptTemplateRepository.ListByPage(ctx, scene, persona, *req.Current, *req.PageSize)
+	if err != nil {
+		return v1.QuestionQueryResponseData[v1.PromptTemplateVO]{}, err
+	}
+
+	records := make([]v1.PromptTemplateVO, 0, len(templates))
+	for i := range templates {
+		records = append(records, toPromptTemplateVO(&templates[i]))
+	}
+	pages := utils.GetPages(total, *req.PageSize)
+	return v1.QuestionQueryResponseData[v1.PromptTemplateVO]{
+		Records: records,
+		Total:   &total,
+		Pages:   &pages,
+		Size:    req.PageSize,
+		Current: req.Current,
+	}, nil
+}
+
+// DeletePromptTemplate 停用场景、风格下的全部版本，已创建的面试仍按记录的版本进行；内置风格停用后回退到内置模板
+func (s *promptTemplateService) DeletePromptTemplate(ctx context.Context, req *v1.DeletePromptTemplateRequest) (bool, error) {
+	if req.Scene == nil || req.Persona == nil {
+		return false, v1.ParamsError
+	}
+	affected, err := s.promptTemplateRepository.Disable(ctx, *req.Scene, *req.Persona)
+	if err != nil {
+		return false, err
+	}
+	if affected == 0 {
+		return false, v1.ErrNotFound
+	}
+	return true, nil
+}
+
+// ListMockInterviewPersona 获取每种面试官风格的最新版本，默认风格在数据库中没有时使用内置模板
+func (s *promptTemplateService) ListMockInterviewPersona(ctx context.Context) ([]v1.MockInterviewPersonaVO, error) {
+	templates, err := s.promptTemplateRepository.ListLatest(ctx, constant.PromptSceneMockInterview)
+	if err != nil {
+		return nil, err
+	}
+	personas := make([]v1.MockInterviewPersonaVO, 0, len(templates)+1)
+	hasDefault := false
+	for _, promptTemplate := range templates {
+		if promptTemplate.Persona == constant.MockInterviewPersonaStrict {
+			hasDefault = true
+		}
+		personas = append(personas, toMockInterviewPersonaVO(&promptTemplate))
+	}
+	if !hasDefault {
+		defaultTemplate := defaultPromptTemplate(constant.PromptSceneMockInterview, constant.MockInterviewPersonaStrict)
+		personas = append([]v1.MockInterviewPersonaVO{toMockInterviewPersonaVO(defaultTemplate)}, personas...)
+	}
+	return personas, nil
+}
+
+// toPromptTemplateVO 转换为模板视图
+func toPromptTemplateVO(promptTemplate *model.PromptTemplate) v1.PromptTemplateVO {
+	id := utils.Uint64TOString(promptTemplate.ID)
+	editorId := utils.Uint64TOString(promptTemplate.EditorID)
+	return v1.PromptTemplateVO{
+		ID:          &id,
+		Scene:       &promptTemplate.Scene,
+		Persona:     &promptTemplate.Persona,
+		Version:     &promptTemplate.Version,
+		Name:        &promptTemplate.Name,
+		Description: &promptTemplate.Description,
+		Content:     &promptTemplate.Content,
+		EditorID:    &editorId,
+		CreateTime:  &promptTemplate.CreateTime,
+		IsDelete:    &promptTemplate.IsDelete,
+	}
+}
+
+// toMockInterviewPersonaVO 转换为面试官风格视图
+func toMockInterviewPersonaVO(promptTemplate *model.PromptTemplate) v1.MockInterviewPersonaVO {
+	return v1.MockInterviewPersonaVO{
+		Persona:     promptTemplate.Persona,
+		Name:        promptTemplate.Name,
+		Description: promptTemplate.Description,
+		Version:     promptTemplate.Version,
+	}
+}
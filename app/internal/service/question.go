@@ -4,14 +4,25 @@ import (
 	v1 "app/api/v1"
 	"app/internal/model"
 	"app/internal/repository"
-	"app/pkg/aiServer/ai"
+	"app/pkg/constant"
+	"app/pkg/embedding"
+	"app/pkg/jwt"
+	"app/pkg/llm"
 	"app/pkg/utils"
 	"context"
-	"fmt"
-	chatModel "github.com/volcengine/volcengine-go-sdk/service/arkruntime/model"
-	"github.com/volcengine/volcengine-go-sdk/volcengine"
 	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// defaultQuestionSuggestSize 默认返回的题目建议数
+	defaultQuestionSuggestSize = 5
+	// maxQuestionSuggestSize 最多返回的题目建议数
+	maxQuestionSuggestSize = 10
+	// maxQuestionSuggestPrefixLen 搜索建议前缀的最大字符数
+	maxQuestionSuggestPrefixLen = 50
 )
 
 // QuestionService 定义了一个问题服务的接口
@@ -19,127 +30,361 @@ type QuestionService interface {
 	// 根据页码获取问题列表
 	ListQuestionByPage(ctx context.Context, req *v1.QuestionRequest) (v1.QuestionQueryResponseData[v1.Question], error)
 	// 添加问题
-	AddQuestion(ctx context.Context, req *v1.AddQuestionRequest, token string) (string, error)
+	AddQuestion(ctx context.Context, req *v1.AddQuestionRequest, loginUser *jwt.User) (string, error)
 	// 删除问题
 	DeleteQuestion(ctx context.Context, req *v1.DeleteQuestionRequest) (bool, error)
 	// 更新问题
-	UpdateQuestion(ctx context.Context, req *v1.UpdateQuestionRequest) (bool, error)
+	UpdateQuestion(ctx context.Context, req *v1.UpdateQuestionRequest, loginUser *jwt.User) (bool, error)
 	// 根据题库ID获取问题列表
 	ListQuestionByBankId(ctx context.Context, bankId uint64) (v1.PageQuestionVO, error)
 	// 根据问题ID获取问题
-	GetQuestionById(ctx context.Context, req *v1.GetQuestionRequest, isHot any, cacheKey any) (v1.QuestionVO, error)
+	GetQuestionById(ctx context.Context, req *v1.GetQuestionRequest, isHot any, cacheKey any, loginUser *jwt.User) (v1.QuestionVO, error)
+	// 为命中热点缓存的题目填充当前用户的点赞、收藏状态
+	FillQuestionUserState(ctx context.Context, question *v1.QuestionVO, loginUser *jwt.User) error
 	// 根据页码获取问题列表（VO）
-	ListQuestionVoByPage(ctx context.Context, req *v1.QuestionRequest) (v1.PageQuestionVO, error)
+	ListQuestionVoByPage(ctx context.Context, req *v1.QuestionRequest, loginUser *jwt.User) (v1.PageQuestionVO, error)
 	// 根据页码和关键词搜索问题列表（VO）
-	SearchQuestionVoByPage(ctx context.Context, req *v1.QuestionRequest) (v1.PageQuestionVO, error)
+	SearchQuestionVoByPage(ctx context.Context, req *v1.QuestionRequest, loginUser *jwt.User) (v1.PageQuestionVO, error)
+	// 获取与题目或文本相似的题目
+	ListSimilarQuestion(ctx context.Context, req *v1.SimilarQuestionRequest) ([]v1.SimilarQuestionVO, error)
+	// 获取与待添加题目疑似重复的题目
+	ListNearDuplicateQuestion(ctx context.Context, req *v1.AddQuestionRequest) ([]v1.SimilarQuestionVO, error)
+	// 根据前缀获取搜索建议
+	SuggestQuestion(ctx context.Context, req *v1.QuestionSuggestRequest) (*v1.QuestionSuggestVO, error)
 	// 批量删除问题
 	DeleteBatchQuestion(ctx context.Context, req *v1.BatchDeleteQuestionRequest) (bool, error)
-	// 通过 AI 生成题目
-	AddQuestionByAI(ctx context.Context, req *v1.AddQuestionByAIRequest, token string) (bool, error)
+	// 获取当前用户创建的问题列表（含审核信息）
+	ListMyQuestionByPage(ctx context.Context, req *v1.QuestionRequest, loginUser *jwt.User) (v1.QuestionQueryResponseData[v1.Question], error)
+	// 获取热门题目排行
+	ListHotQuestion(ctx context.Context, req *v1.HotQuestionRequest, loginUser *jwt.User) ([]v1.QuestionVO, error)
+	// 审核问题
+	ReviewQuestion(ctx context.Context, req *v1.ReviewRequest, reviewerId uint64) (bool, error)
+	// 批量审核问题
+	BatchReviewQuestion(ctx context.Context, req *v1.BatchReviewRequest, reviewerId uint64) (bool, error)
 }
 
 // NewQuestionService 创建一个新的问题服务实例
 func NewQuestionService(
 	service *Service,
 	questionRepository repository.QuestionRepository,
+	questionThumbRepository repository.QuestionThumbRepository,
+	questionFavourRepository repository.QuestionFavourRepository,
+	questionRevisionRepository repository.QuestionRevisionRepository,
+	questionOutboxRepository repository.QuestionOutboxRepository,
+	questionSearcher repository.QuestionSearcher,
+	questionSearchLogRepository repository.QuestionSearchLogRepository,
+	embeddingProvider embedding.Provider,
+	llmClient llm.Client,
 ) QuestionService {
 	return &questionService{
-		Service:            service,
-		questionRepository: questionRepository,
+		Service:                     service,
+		questionRepository:          questionRepository,
+		questionThumbRepository:     questionThumbRepository,
+		questionFavourRepository:    questionFavourRepository,
+		questionRevisionRepository:  questionRevisionRepository,
+		questionOutboxRepository:    questionOutboxRepository,
+		questionSearcher:            questionSearcher,
+		questionSearchLogRepository: questionSearchLogRepository,
+		embeddingProvider:           embeddingProvider,
+		llmClient:                   llmClient,
 	}
 }
 
 // questionService 实现了QuestionService接口
 type questionService struct {
 	*Service
-	questionRepository repository.QuestionRepository
+	questionRepository          repository.QuestionRepository
+	questionThumbRepository     repository.QuestionThumbRepository
+	questionFavourRepository    repository.QuestionFavourRepository
+	questionRevisionRepository  repository.QuestionRevisionRepository
+	questionOutboxRepository    repository.QuestionOutboxRepository
+	questionSearcher            repository.QuestionSearcher
+	questionSearchLogRepository repository.QuestionSearchLogRepository
+	embeddingProvider           embedding.Provider
+	llmClient                   llm.Client
 }
 
-func (s *questionService) AddQuestionByAI(ctx context.Context, req *v1.AddQuestionByAIRequest, token string) (bool, error) {
-	// 1.定义系统 Prompt
-	systemPrompt := "你是一位专业的程序员面试官，你要帮我生成 {数量} 道 {方向} 面试题，要求输出格式如下：\n" +
-		"\n" +
-		"1. 什么是 Java 中的反射？\n" +
-		"2. Java 8 中的 Stream API 有什么作用？\n" +
-		"3. xxxxxx\n" +
-		"\n" +
-		"除此之外，请不要输出任何多余的内容，不要输出开头、也不要输出结尾，只输出上面的列表。\n" +
-		"\n" +
-		"接下来我会给你要生成的题目{数量}、以及题目{方向}\n"
-	// 2.定义用户 Prompt
-	userPrompt := fmt.Sprintf("数量：%d\n方向：%s\n", req.Number, req.Direction)
-	// 3.调用 AI 生成题目
-	questions := ai.DoChat([]*chatModel.ChatCompletionMessage{
-		{
-			Role: chatModel.ChatMessageRoleSystem,
-			Content: &chatModel.ChatCompletionMessageContent{
-				StringValue: volcengine.String(systemPrompt),
-			},
-		},
-		{
-			Role: chatModel.ChatMessageRoleUser,
-			Content: &chatModel.ChatCompletionMessageContent{
-				StringValue: volcengine.String(userPrompt),
-			},
-		},
-	})
-	// 4.对题目进行预处理
-	lines := strings.Split(questions, "\n")
-	var result []string
-
-	for _, line := range lines {
-		// 移除序号
-		parts := strings.SplitN(line, ". ", 2)
-		if len(parts) > 1 {
-			cleaned := parts[1]
-			// 删除所有的 `
-			cleaned = strings.ReplaceAll(cleaned, "`", "")
-			// 去除首尾空格
-			cleaned = strings.TrimSpace(cleaned)
-			result = append(result, cleaned)
+// fillQuestionVOUserState 填充当前用户对题目的点赞、收藏状态，未登录时不填充
+func (s *questionService) fillQuestionVOUserState(ctx context.Context, questionVOList []v1.QuestionVO, loginUser *jwt.User) error {
+	if loginUser == nil || len(questionVOList) == 0 {
+		return nil
+	}
+	questionIds := make([]uint64, 0, len(questionVOList))
+	for _, q := range questionVOList {
+		id, err := utils.StringToUint64(*q.ID)
+		if err != nil {
+			return err
 		}
+		questionIds = append(questionIds, id)
+	}
+	thumbMap, err := s.questionThumbRepository.GetThumbQuestionIds(ctx, loginUser.ID, questionIds)
+	if err != nil {
+		return err
+	}
+	favourMap, err := s.questionFavourRepository.GetFavourQuestionIds(ctx, loginUser.ID, questionIds)
+	if err != nil {
+		return err
+	}
+	for i, id := range questionIds {
+		hasThumb := thumbMap[id]
+		hasFavour := favourMap[id]
+		questionVOList[i].HasThumb = &hasThumb
+		questionVOList[i].HasFavour = &hasFavour
+	}
+	return nil
+}
+
+// FillQuestionUserState 热点缓存为所有用户共享，不包含点赞、收藏状态，命中缓存后按当前用户重新填充
+func (s *questionService) FillQuestionUserState(ctx context.Context, question *v1.QuestionVO, loginUser *jwt.User) error {
+	question.HasThumb, question.HasFavour = nil, nil
+	questionVOList := []v1.QuestionVO{*question}
+	if err := s.fillQuestionVOUserState(ctx, questionVOList, loginUser); err != nil {
+		return err
+	}
+	*question = questionVOList[0]
+	return nil
+}
+
+// ListHotQuestion 获取最近一段时间内浏览次数最多的题目
+func (s *questionService) ListHotQuestion(ctx context.Context, req *v1.HotQuestionRequest, loginUser *jwt.User) ([]v1.QuestionVO, error) {
+	size := 10
+	if req.Size != nil && *req.Size > 0 {
+		size = *req.Size
+	}
+	if size > 50 {
+		size = 50
 	}
-	// 5.将生成的题目添加到数据库
-	tags := make([]string, 0)
-	tags = append(tags, req.Direction)
-	for _, question := range result {
-		var request = &v1.AddQuestionRequest{
-			Content: &question,
-			Title:   &question,
-			Tags:    tags,
+	// 排行中可能包含未通过审核的题目，多取一些再过滤
+	ids, err := s.questionRepository.GetHotQuestionIds(ctx, size*2)
+	if err != nil {
+		return nil, err
+	}
+	questions, err := s.questionRepository.GetPassQuestionByIds(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	questionMap := make(map[uint64]model.Question, len(questions))
+	for _, question := range questions {
+		questionMap[question.ID] = question
+	}
+
+	// 按排行顺序组装
+	questionVOList := make([]v1.QuestionVO, 0, size)
+	for _, id := range ids {
+		question, ok := questionMap[id]
+		if !ok {
+			continue
+		}
+		qid := utils.Uint64TOString(question.ID)
+		userId := utils.Uint64TOString(question.UserID)
+		tagList, err := utils.StringToStrings(*question.Tags)
+		if err != nil {
+			return nil, err
+		}
+		questionVOList = append(questionVOList, v1.QuestionVO{
+			CreateTime: &question.CreateTime,
+			ID:         &qid,
+			TagList:    tagList,
+			Title:      question.Title,
+			UpdateTime: &question.UpdateTime,
+			UserID:     &userId,
+
+			ViewNum:   &question.ViewNum,
+			ThumbNum:  &question.ThumbNum,
+			FavourNum: &question.FavourNum,
+		})
+		if len(questionVOList) >= size {
+			break
 		}
-		_, err := s.AddQuestion(ctx, request, token)
+	}
+	if err = s.fillQuestionVOUserState(ctx, questionVOList, loginUser); err != nil {
+		return nil, err
+	}
+	return questionVOList, nil
+}
+
+// ReviewQuestion 审核问题
+func (s *questionService) ReviewQuestion(ctx context.Context, req *v1.ReviewRequest, reviewerId uint64) (bool, error) {
+	if req.ID == nil || *req.ID == "" {
+		return false, v1.ParamsError
+	}
+	return s.BatchReviewQuestion(ctx, &v1.BatchReviewRequest{
+		IdList:        []string{*req.ID},
+		ReviewStatus:  req.ReviewStatus,
+		ReviewMessage: req.ReviewMessage,
+	}, reviewerId)
+}
+
+// BatchReviewQuestion 批量审核问题
+func (s *questionService) BatchReviewQuestion(ctx context.Context, req *v1.BatchReviewRequest, reviewerId uint64) (bool, error) {
+	if len(req.IdList) == 0 {
+		return false, v1.ParamsError
+	}
+	if err := checkReviewRequest(req.ReviewStatus, req.ReviewMessage); err != nil {
+		return false, err
+	}
+	ids := make([]uint64, 0, len(req.IdList))
+	for _, idStr := range req.IdList {
+		id, err := utils.StringToUint64(idStr)
 		if err != nil {
-			return false, err
+			return false, v1.ParamsError
 		}
+		ids = append(ids, id)
+	}
+	err := s.tm.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.questionRepository.UpdateReviewStatus(ctx, ids, *req.ReviewStatus, req.ReviewMessage, reviewerId); err != nil {
+			return err
+		}
+		return saveQuestionOutbox(ctx, s.questionOutboxRepository, constant.QuestionOutboxEventUpdate, ids...)
+	})
+	if err != nil {
+		return false, err
 	}
 	return true, nil
 }
 
+// checkReviewRequest 校验审核参数，拒绝时必须填写原因
+func checkReviewRequest(reviewStatus *int, reviewMessage *string) error {
+	if reviewStatus == nil {
+		return v1.ErrReviewStatus
+	}
+	switch *reviewStatus {
+	case constant.ReviewStatusPass:
+		return nil
+	case constant.ReviewStatusReject:
+		if reviewMessage == nil || strings.TrimSpace(*reviewMessage) == "" {
+			return v1.ErrReviewMessageRequired
+		}
+		return nil
+	default:
+		return v1.ErrReviewStatus
+	}
+}
+
+// ListMyQuestionByPage 获取当前用户创建的问题列表，作者可以据此查看审核结果
+func (s *questionService) ListMyQuestionByPage(ctx context.Context, req *v1.QuestionRequest, loginUser *jwt.User) (v1.QuestionQueryResponseData[v1.Question], error) {
+	userId := utils.Uint64TOString(loginUser.ID)
+	req.UserID = &userId
+	return s.ListQuestionByPage(ctx, req)
+}
+
 // DeleteBatchQuestion 批量删除问题
 func (s *questionService) DeleteBatchQuestion(ctx context.Context, req *v1.BatchDeleteQuestionRequest) (bool, error) {
 	// 检验参数的合法性
 	if len(req.QuestionIdList) == 0 {
 		return false, v1.ParamsError
 	}
-	err := s.questionRepository.DeleteBatchQuestion(ctx, req.QuestionIdList)
+	ids := make([]uint64, 0, len(req.QuestionIdList))
+	for _, idStr := range req.QuestionIdList {
+		id, err := utils.StringToUint64(idStr)
+		if err != nil {
+			return false, v1.ParamsError
+		}
+		ids = append(ids, id)
+	}
+	err := s.tm.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.questionRepository.DeleteBatchQuestion(ctx, ids); err != nil {
+			return err
+		}
+		return saveQuestionOutbox(ctx, s.questionOutboxRepository, constant.QuestionOutboxEventDelete, ids...)
+	})
 	if err != nil {
 		return false, err
 	}
 	return true, nil
 }
 
-// SearchQuestionVoByPage 根据页码和关键词搜索问题列表（VO）
-func (s *questionService) SearchQuestionVoByPage(ctx context.Context, req *v1.QuestionRequest) (v1.PageQuestionVO, error) {
-	current := req.Current
-	size := req.PageSize
-	questions, total, err := s.questionRepository.GetEsQuestion(ctx, req)
+// ListSimilarQuestion 获取与题目或文本相似的已公开题目，用于题目页的相关推荐
+func (s *questionService) ListSimilarQuestion(ctx context.Context, req *v1.SimilarQuestionRequest) ([]v1.SimilarQuestionVO, error) {
+	size := constant.SimilarQuestionDefaultSize
+	if req.Size != nil && *req.Size > 0 {
+		size = min(*req.Size, constant.SimilarQuestionMaxSize)
+	}
+	var text string
+	var excludeId uint64
+	switch {
+	case req.QuestionID != nil && *req.QuestionID != "":
+		id, err := utils.StringToUint64(*req.QuestionID)
+		if err != nil {
+			return nil, v1.ParamsError
+		}
+		questions, err := s.questionRepository.GetPassQuestionByIds(ctx, []uint64{id})
+		if err != nil {
+			return nil, err
+		}
+		if len(questions) == 0 {
+			return nil, v1.ErrNotFound
+		}
+		text, excludeId = questions[0].EmbeddingText(), id
+	case req.Text != nil && strings.TrimSpace(*req.Text) != "":
+		text = *req.Text
+	default:
+		return nil, v1.ParamsError
+	}
+	vector, err := s.embeddingProvider.Embed(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+	return s.questionRepository.SearchSimilarQuestion(ctx, vector, size, excludeId, true)
+}
+
+// ListNearDuplicateQuestion 获取与待添加题目相似度超过阈值的题目，只比对审核通过的题目，避免泄露未审核的内容
+func (s *questionService) ListNearDuplicateQuestion(ctx context.Context, req *v1.AddQuestionRequest) ([]v1.SimilarQuestionVO, error) {
+	var title, content string
+	if req.Title != nil {
+		title = *req.Title
+	}
+	if req.Content != nil {
+		content = *req.Content
+	}
+	vector, err := s.embeddingProvider.Embed(ctx, model.QuestionEmbeddingText(title, content, req.Tags))
+	if err != nil {
+		return nil, err
+	}
+	similar, err := s.questionRepository.SearchSimilarQuestion(ctx, vector, constant.SimilarQuestionDefaultSize, 0, true)
+	if err != nil {
+		return nil, err
+	}
+	duplicates := make([]v1.SimilarQuestionVO, 0, len(similar))
+	for _, q := range similar {
+		if q.Similarity >= constant.NearDuplicateQuestionThreshold {
+			duplicates = append(duplicates, q)
+		}
+	}
+	return duplicates, nil
+}
+
+// SuggestQuestion 根据输入前缀返回题目标题和标签建议
+func (s *questionService) SuggestQuestion(ctx context.Context, req *v1.QuestionSuggestRequest) (*v1.QuestionSuggestVO, error) {
+	if req.Prefix == nil {
+		return nil, v1.ParamsError
+	}
+	prefix := strings.TrimSpace(*req.Prefix)
+	if n := utf8.RuneCountInString(prefix); n == 0 || n > maxQuestionSuggestPrefixLen {
+		return nil, v1.ParamsError
+	}
+	size := defaultQuestionSuggestSize
+	if req.Size != nil && *req.Size > 0 {
+		size = min(*req.Size, maxQuestionSuggestSize)
+	}
+	return s.questionRepository.SuggestQuestion(ctx, prefix, size)
+}
+
+// SearchQuestionVoByPage 根据页码和关键词搜索问题列表（VO），ES 不可用时由数据库提供结果
+func (s *questionService) SearchQuestionVoByPage(ctx context.Context, req *v1.QuestionRequest, loginUser *jwt.User) (v1.PageQuestionVO, error) {
+	start := time.Now()
+	result, err := s.questionSearcher.Search(ctx, req)
+	if err != nil {
+		return v1.PageQuestionVO{}, err
+	}
+	searchId, err := s.sid.GenString()
 	if err != nil {
 		return v1.PageQuestionVO{}, err
 	}
+	s.questionSearchLogRepository.RecordSearch(newQuestionSearchLog(req, result, searchId, time.Since(start), loginUser))
 	var questionVOList []v1.QuestionVO
 
-	for _, question := range questions {
+	for _, question := range result.Questions {
 		var id, userId string
 		id = *question.ID
 		userId = *question.UserID
@@ -158,22 +403,33 @@ func (s *questionService) SearchQuestionVoByPage(ctx context.Context, req *v1.Qu
 			Title:      question.Title,
 			UpdateTime: question.UpdateTime,
 			UserID:     &userId,
+			Highlight:  question.Highlight,
 		}
 		questionVOList = append(questionVOList, q)
 	}
-	pages := total / *size + 1
+	if err = s.fillQuestionVOUserState(ctx, questionVOList, loginUser); err != nil {
+		return v1.PageQuestionVO{}, err
+	}
+	size, current := 12, 1
+	if req.PageSize != nil && req.Current != nil {
+		size, current = *req.PageSize, *req.Current
+	}
+	pages := utils.GetPages(result.Total, size)
 	return v1.PageQuestionVO{
-		Records: questionVOList,
-		Total:   &total,
-		Pages:   &pages,
-		Size:    size,
-		Current: current,
+		Records:       questionVOList,
+		Total:         &result.Total,
+		Pages:         &pages,
+		Size:          &size,
+		Current:       &current,
+		TagFacets:     result.TagFacets,
+		SearchBackend: &result.Backend,
+		SearchID:      &searchId,
 	}, nil
 
 }
 
 // ListQuestionVoByPage 根据页码获取问题列表（VO）
-func (s *questionService) ListQuestionVoByPage(ctx context.Context, req *v1.QuestionRequest) (v1.PageQuestionVO, error) {
+func (s *questionService) ListQuestionVoByPage(ctx context.Context, req *v1.QuestionRequest, loginUser *jwt.User) (v1.PageQuestionVO, error) {
 	current := req.Current
 	size := req.PageSize
 	questions, total, err := s.questionRepository.GetQuestion(ctx, req)
@@ -202,9 +458,16 @@ func (s *questionService) ListQuestionVoByPage(ctx context.Context, req *v1.Ques
 			Title:      question.Title,
 			UpdateTime: &question.UpdateTime,
 			UserID:     &userId,
+
+			ViewNum:   &question.ViewNum,
+			ThumbNum:  &question.ThumbNum,
+			FavourNum: &question.FavourNum,
 		}
 		questionVOList = append(questionVOList, q)
 	}
+	if err = s.fillQuestionVOUserState(ctx, questionVOList, loginUser); err != nil {
+		return v1.PageQuestionVO{}, err
+	}
 	pages := total / *size + 1
 	return v1.PageQuestionVO{
 		Records: questionVOList,
@@ -216,7 +479,7 @@ func (s *questionService) ListQuestionVoByPage(ctx context.Context, req *v1.Ques
 }
 
 // GetQuestionById 根据问题ID获取问题
-func (s *questionService) GetQuestionById(ctx context.Context, req *v1.GetQuestionRequest, isHot any, cacheKey any) (v1.QuestionVO, error) {
+func (s *questionService) GetQuestionById(ctx context.Context, req *v1.GetQuestionRequest, isHot any, cacheKey any, loginUser *jwt.User) (v1.QuestionVO, error) {
 	if req.ID == nil || *req.ID == "" {
 		return v1.QuestionVO{}, v1.ParamsError
 	}
@@ -229,6 +492,12 @@ func (s *questionService) GetQuestionById(ctx context.Context, req *v1.GetQuesti
 	if err != nil {
 		return v1.QuestionVO{}, err
 	}
+	// 未审核通过的题目仅作者和管理员可见
+	if question.ReviewStatus != constant.ReviewStatusPass {
+		if loginUser == nil || (loginUser.ID != question.UserID && loginUser.UserRole != constant.AdminRole) {
+			return v1.QuestionVO{}, v1.ErrNotFound
+		}
+	}
 
 	tagList, err := utils.StringToStrings(*question.Tags)
 	if err != nil {
@@ -237,7 +506,7 @@ func (s *questionService) GetQuestionById(ctx context.Context, req *v1.GetQuesti
 
 	userId := strconv.FormatUint(question.UserID, 10)
 
-	return v1.QuestionVO{
+	questionVO := v1.QuestionVO{
 		Answer:     question.Answer,
 		Content:    question.Content,
 		CreateTime: &question.CreateTime,
@@ -246,7 +515,19 @@ func (s *questionService) GetQuestionById(ctx context.Context, req *v1.GetQuesti
 		Title:      question.Title,
 		UpdateTime: &question.UpdateTime,
 		UserID:     &userId,
-	}, nil
+
+		ReviewStatus:  &question.ReviewStatus,
+		ReviewMessage: question.ReviewMessage,
+
+		ViewNum:   &question.ViewNum,
+		ThumbNum:  &question.ThumbNum,
+		FavourNum: &question.FavourNum,
+	}
+	questionVOList := []v1.QuestionVO{questionVO}
+	if err = s.fillQuestionVOUserState(ctx, questionVOList, loginUser); err != nil {
+		return v1.QuestionVO{}, err
+	}
+	return questionVOList[0], nil
 }
 
 // ListQuestionByBankId 根据题库ID获取问题列表
@@ -287,8 +568,8 @@ func (s *questionService) ListQuestionByBankId(ctx context.Context, bankId uint6
 }
 
 // UpdateQuestion 更新问题
-func (s *questionService) UpdateQuestion(ctx context.Context, req *v1.UpdateQuestionRequest) (bool, error) {
-	if req == nil || *req.ID == "" {
+func (s *questionService) UpdateQuestion(ctx context.Context, req *v1.UpdateQuestionRequest, loginUser *jwt.User) (bool, error) {
+	if req == nil || req.ID == nil || *req.ID == "" {
 		return false, v1.ParamsError
 	}
 
@@ -296,11 +577,16 @@ func (s *questionService) UpdateQuestion(ctx context.Context, req *v1.UpdateQues
 	if err != nil {
 		return false, v1.ParamsError
 	}
-	cacheKey := fmt.Sprintf("question:cache:%d", id)
-	question, err := s.questionRepository.GetByID(ctx, id, true, cacheKey)
+	question, err := s.questionRepository.GetByID(ctx, id, false, nil)
 	if err != nil {
 		return false, err
 	}
+	// 仅题目创建者和管理员可以修改题目
+	if question.UserID != loginUser.ID && loginUser.UserRole != constant.AdminRole {
+		return false, v1.ErrUnauthorized
+	}
+	// 修改前的内容，历史题目没有修订记录时作为第一个版本保存
+	before := *question
 	if req.Title != nil && *req.Title != "" {
 		question.Title = req.Title
 	}
@@ -317,11 +603,30 @@ func (s *questionService) UpdateQuestion(ctx context.Context, req *v1.UpdateQues
 	if req.Content != nil && *req.Content != "" {
 		question.Content = req.Content
 	}
+	// 修改后的题目需要重新审核
+	question.ReviewStatus = constant.ReviewStatusPending
+	question.ReviewMessage = nil
+	question.ReviewerID = nil
+	question.ReviewTime = nil
 
-	err = s.questionRepository.Update(ctx, question)
+	err = s.tm.Transaction(ctx, func(ctx context.Context) error {
+		if err := saveBaselineRevision(ctx, s.questionRevisionRepository, &before); err != nil {
+			return err
+		}
+		if err := s.questionRepository.Update(ctx, question); err != nil {
+			return err
+		}
+		if err := saveQuestionOutbox(ctx, s.questionOutboxRepository, constant.QuestionOutboxEventUpdate, question.ID); err != nil {
+			return err
+		}
+		return saveQuestionRevision(ctx, s.questionRevisionRepository, question, loginUser.ID, constant.QuestionRevisionActionUpdate, nil)
+	})
 	if err != nil {
 		return false, err
 	}
+	if err = s.questionRepository.DeleteQuestionCache(ctx, id); err != nil {
+		return false, err
+	}
 
 	return true, nil
 }
@@ -342,7 +647,12 @@ func (s *questionService) DeleteQuestion(ctx context.Context, req *v1.DeleteQues
 
 	// 删除
 	bank.IsDelete = 1
-	err = s.questionRepository.DeleteById(ctx, bank, id)
+	err = s.tm.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.questionRepository.DeleteById(ctx, bank, id); err != nil {
+			return err
+		}
+		return saveQuestionOutbox(ctx, s.questionOutboxRepository, constant.QuestionOutboxEventDelete, id)
+	})
 	if err != nil {
 		return false, err
 	}
@@ -351,13 +661,7 @@ func (s *questionService) DeleteQuestion(ctx context.Context, req *v1.DeleteQues
 }
 
 // AddQuestion 添加问题
-func (s *questionService) AddQuestion(ctx context.Context, req *v1.AddQuestionRequest, token string) (string, error) {
-	// 解析 token
-	claims, err := s.jwt.ParseToken(token)
-	if err != nil {
-		return "", err
-	}
-
+func (s *questionService) AddQuestion(ctx context.Context, req *v1.AddQuestionRequest, loginUser *jwt.User) (string, error) {
 	if *req.Title == "" {
 		return "", v1.ErrIllegalAccount
 	}
@@ -377,9 +681,19 @@ func (s *questionService) AddQuestion(ctx context.Context, req *v1.AddQuestionRe
 		Content: req.Content,
 		Tags:    &tags,
 		Title:   req.Title,
-		UserID:  claims.User.ID,
+		UserID:  loginUser.ID,
+		// 新建的题目需要审核通过后才能公开
+		ReviewStatus: constant.ReviewStatusPending,
 	}
-	err = s.questionRepository.Create(ctx, questionBank)
+	err = s.tm.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.questionRepository.Create(ctx, questionBank); err != nil {
+			return err
+		}
+		if err := saveQuestionOutbox(ctx, s.questionOutboxRepository, constant.QuestionOutboxEventCreate, questionBank.ID); err != nil {
+			return err
+		}
+		return saveQuestionRevision(ctx, s.questionRevisionRepository, questionBank, loginUser.ID, constant.QuestionRevisionActionCreate, nil)
+	})
 	if err != nil {
 		return "", err
 	}
@@ -407,6 +721,11 @@ func (s *questionService) ListQuestionByPage(ctx context.Context, req *v1.Questi
 		var id, userId string
 		id = utils.Uint64TOString(question.ID)
 		userId = utils.Uint64TOString(question.UserID)
+		var reviewerId *string
+		if question.ReviewerID != nil {
+			rid := utils.Uint64TOString(*question.ReviewerID)
+			reviewerId = &rid
+		}
 		q := v1.Question{
 			Answer:     question.Answer,
 			Content:    question.Content,
@@ -418,6 +737,11 @@ func (s *questionService) ListQuestionByPage(ctx context.Context, req *v1.Questi
 			Title:      question.Title,
 			UpdateTime: &question.UpdateTime,
 			UserID:     &userId,
+
+			ReviewStatus:  &question.ReviewStatus,
+			ReviewMessage: question.ReviewMessage,
+			ReviewerID:    reviewerId,
+			ReviewTime:    question.ReviewTime,
 		}
 		questionList = append(questionList, q)
 	}
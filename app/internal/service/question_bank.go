@@ -4,7 +4,10 @@ import (
 	v1 "app/api/v1"
 	"app/internal/model"
 	"app/internal/repository"
+	"app/pkg/constant"
+	"app/pkg/jwt"
 	"context"
+	"go.uber.org/zap"
 	"strconv"
 )
 
@@ -14,8 +17,10 @@ type QuestionBankService interface {
 	AddQuestionBank(ctx context.Context, req *v1.AddQuestionBankRequest) (string, error)
 	DeleteUser(ctx context.Context, req *v1.DeleteQuestionBankRequest) (bool, error)
 	UpdateQuestionBank(ctx context.Context, req *v1.UpdateQuestionBankRequest) (bool, error)
-	GetQuestionBankById(ctx context.Context, req *v1.GetQuestionBankRequest) (v1.GetQuestionBankResponse, error)
+	GetQuestionBankById(ctx context.Context, req *v1.GetQuestionBankRequest, loginUser *jwt.User) (v1.GetQuestionBankResponse, error)
 	ListBankByVOPage(ctx context.Context, req *v1.QuestionBankRequest) (v1.QuestionBankVO, error)
+	ReviewQuestionBank(ctx context.Context, req *v1.ReviewRequest, reviewerId uint64) (bool, error)
+	BatchReviewQuestionBank(ctx context.Context, req *v1.BatchReviewRequest, reviewerId uint64) (bool, error)
 }
 
 // NewQuestionBankService 实现问题库服务接口
@@ -34,6 +39,40 @@ type questionBankService struct {
 	questionBankRepository repository.QuestionBankRepository
 }
 
+// ReviewQuestionBank 审核问题库
+func (s *questionBankService) ReviewQuestionBank(ctx context.Context, req *v1.ReviewRequest, reviewerId uint64) (bool, error) {
+	if req.ID == nil || *req.ID == "" {
+		return false, v1.ParamsError
+	}
+	return s.BatchReviewQuestionBank(ctx, &v1.BatchReviewRequest{
+		IdList:        []string{*req.ID},
+		ReviewStatus:  req.ReviewStatus,
+		ReviewMessage: req.ReviewMessage,
+	}, reviewerId)
+}
+
+// BatchReviewQuestionBank 批量审核问题库
+func (s *questionBankService) BatchReviewQuestionBank(ctx context.Context, req *v1.BatchReviewRequest, reviewerId uint64) (bool, error) {
+	if len(req.IdList) == 0 {
+		return false, v1.ParamsError
+	}
+	if err := checkReviewRequest(req.ReviewStatus, req.ReviewMessage); err != nil {
+		return false, err
+	}
+	ids := make([]uint64, 0, len(req.IdList))
+	for _, idStr := range req.IdList {
+		id, err := strconv.ParseUint(idStr, 10, 64)
+		if err != nil {
+			return false, v1.ParamsError
+		}
+		ids = append(ids, id)
+	}
+	if err := s.questionBankRepository.UpdateReviewStatus(ctx, ids, *req.ReviewStatus, req.ReviewMessage, reviewerId); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 // ListBankByVOPage 根据分页请求获取问题库列表
 func (s *questionBankService) ListBankByVOPage(ctx context.Context, req *v1.QuestionBankRequest) (v1.QuestionBankVO, error) {
 	if req.ID == nil || *req.ID == "" {
@@ -61,7 +100,7 @@ func (s *questionBankService) ListBankByVOPage(ctx context.Context, req *v1.Ques
 }
 
 // GetQuestionBankById 根据ID获取问题库
-func (s *questionBankService) GetQuestionBankById(ctx context.Context, req *v1.GetQuestionBankRequest) (v1.GetQuestionBankResponse, error) {
+func (s *questionBankService) GetQuestionBankById(ctx context.Context, req *v1.GetQuestionBankRequest, loginUser *jwt.User) (v1.GetQuestionBankResponse, error) {
 	if req == nil || *req.ID == "" {
 		return v1.GetQuestionBankResponse{}, v1.ParamsError
 	}
@@ -74,6 +113,14 @@ func (s *questionBankService) GetQuestionBankById(ctx context.Context, req *v1.G
 	if err != nil {
 		return v1.GetQuestionBankResponse{}, err
 	}
+	// 未审核通过的题库仅管理员可见
+	if bank.ReviewStatus != constant.ReviewStatusPass && (loginUser == nil || loginUser.UserRole != constant.AdminRole) {
+		return v1.GetQuestionBankResponse{}, v1.ErrNotFound
+	}
+	// 浏览量先记在 Redis 中，由定时任务刷回数据库
+	if err = s.questionBankRepository.IncrViewNum(ctx, id); err != nil {
+		s.logger.Warn("IncrViewNum error", zap.Error(err))
+	}
 
 	return v1.GetQuestionBankResponse{
 		CreateTime:  &bank.CreateTime,
@@ -108,6 +155,11 @@ func (s *questionBankService) UpdateQuestionBank(ctx context.Context, req *v1.Up
 	if req.Description != nil && *req.Description != "" {
 		bank.Description = req.Description
 	}
+	// 修改后的题库需要重新审核
+	bank.ReviewStatus = constant.ReviewStatusPending
+	bank.ReviewMessage = nil
+	bank.ReviewerID = nil
+	bank.ReviewTime = nil
 
 	err = s.questionBankRepository.Update(ctx, bank)
 	if err != nil {
@@ -156,6 +208,8 @@ func (s *questionBankService) AddQuestionBank(ctx context.Context, req *v1.AddQu
 		Description: req.Description,
 		Picture:     req.Picture,
 		Title:       req.Title,
+		// 新建的题库需要审核通过后才能公开
+		ReviewStatus: constant.ReviewStatusPending,
 	}
 	err = s.questionBankRepository.Create(ctx, questionBank)
 	if err != nil {
@@ -184,6 +238,11 @@ func (s *questionBankService) ListBankByPage(ctx context.Context, req *v1.Questi
 		var id, userId string
 		id = strconv.Itoa(int(questionBank.ID))
 		userId = strconv.Itoa(int(questionBank.UserID))
+		var reviewerId *string
+		if questionBank.ReviewerID != nil {
+			rid := strconv.FormatUint(*questionBank.ReviewerID, 10)
+			reviewerId = &rid
+		}
 		q := v1.QuestionBank{
 			CreateTime:  &questionBank.CreateTime,
 			Description: questionBank.Description,
@@ -194,6 +253,11 @@ func (s *questionBankService) ListBankByPage(ctx context.Context, req *v1.Questi
 			Title:       questionBank.Title,
 			UpdateTime:  &questionBank.UpdateTime,
 			UserID:      &userId,
+
+			ReviewStatus:  &questionBank.ReviewStatus,
+			ReviewMessage: questionBank.ReviewMessage,
+			ReviewerID:    reviewerId,
+			ReviewTime:    questionBank.ReviewTime,
 		}
 		questionBankList = append(questionBankList, q)
 	}
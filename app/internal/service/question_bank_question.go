@@ -4,6 +4,8 @@ import (
 	v1 "app/api/v1"
 	"app/internal/model"
 	"app/internal/repository"
+	"app/pkg/constant"
+	"app/pkg/jwt"
 	"app/pkg/utils"
 	"context"
 	"strconv"
@@ -12,20 +14,30 @@ import (
 // QuestionBankQuestionService 定义了题目题库服务接口
 type QuestionBankQuestionService interface {
 	ListQuestionBankQuestion(ctx context.Context, req *v1.QuestionBankQuestionQueryRequest) (v1.PageQuestionBankQuestionVO, error)
-	AddQuestionBankQuestion(ctx context.Context, req *v1.QuestionBankQuestionRequest) (string, error)
+	AddQuestionBankQuestion(ctx context.Context, req *v1.QuestionBankQuestionRequest, userId uint64) (string, error)
 	RemoveQuestionBankQuestion(ctx context.Context, req *v1.QuestionBankQuestionRequest) (bool, error)
-	BatchAddQuestionBankQuestion(ctx context.Context, req *v1.QuestionBankQuestionBatchRequest) (bool, error)
+	BatchAddQuestionBankQuestion(ctx context.Context, req *v1.QuestionBankQuestionBatchRequest, userId uint64) (bool, error)
 	BatchRemoveQuestionBankQuestion(ctx context.Context, req *v1.QuestionBankQuestionBatchRemoveRequest) (bool, error)
+	InsertQuestionBankQuestion(ctx context.Context, req *v1.QuestionBankQuestionPositionRequest, userId uint64) (string, error)
+	MoveQuestionBankQuestion(ctx context.Context, req *v1.QuestionBankQuestionPositionRequest) (bool, error)
+	ReorderQuestionBankQuestion(ctx context.Context, req *v1.QuestionBankQuestionReorderRequest) (bool, error)
+	RenumberQuestionBankQuestion(ctx context.Context, req *v1.QuestionBankQuestionRenumberRequest) (int, error)
+	GetQuestionBankNavigation(ctx context.Context, req *v1.QuestionBankNavigationRequest, loginUser *jwt.User) (v1.QuestionBankNavigationVO, error)
+	ListQuestionBelongBank(ctx context.Context, req *v1.QuestionBelongBankRequest) ([]v1.QuestionBelongBankVO, error)
 }
 
 // NewQuestionBankQuestionService 创建题目题库服务实例
 func NewQuestionBankQuestionService(
 	service *Service,
 	questionBankQuestionRepository repository.QuestionBankQuestionRepository,
+	questionBankRepository repository.QuestionBankRepository,
+	questionRepository repository.QuestionRepository,
 ) QuestionBankQuestionService {
 	return &questionBankQuestionService{
 		Service:                        service,
 		questionBankQuestionRepository: questionBankQuestionRepository,
+		questionBankRepository:         questionBankRepository,
+		questionRepository:             questionRepository,
 	}
 }
 
@@ -33,9 +45,306 @@ func NewQuestionBankQuestionService(
 type questionBankQuestionService struct {
 	*Service
 	questionBankQuestionRepository repository.QuestionBankQuestionRepository
+	questionBankRepository         repository.QuestionBankRepository
+	questionRepository             repository.QuestionRepository
 }
 
-// BatchRemoveQuestionBankQuestion 批量移除题目题库关系
+// parseBankAndQuestionId 解析题库ID和题目ID
+func parseBankAndQuestionId(questionBankIdStr *string, questionIdStr *string) (uint64, uint64, error) {
+	if questionBankIdStr == nil || questionIdStr == nil {
+		return 0, 0, v1.ParamsError
+	}
+	questionBankId, err := utils.StringToUint64(*questionBankIdStr)
+	if err != nil {
+		return 0, 0, v1.ParamsError
+	}
+	questionId, err := utils.StringToUint64(*questionIdStr)
+	if err != nil {
+		return 0, 0, v1.ParamsError
+	}
+	return questionBankId, questionId, nil
+}
+
+// renumber 按当前顺序把题号整理为 1..N，只更新题号发生变化的记录，需要在事务中调用
+func (s *questionBankQuestionService) renumber(ctx context.Context, questionBankId uint64) (int, error) {
+	questionBankQuestions, err := s.questionBankQuestionRepository.GetQuestionBankQuestion(ctx, questionBankId, 1)
+	if err != nil {
+		return 0, err
+	}
+	changed := 0
+	for i, q := range questionBankQuestions {
+		if q.QuestionOrder == i+1 {
+			continue
+		}
+		if err = s.questionBankQuestionRepository.UpdateQuestionOrder(ctx, q.ID, i+1); err != nil {
+			return 0, err
+		}
+		changed++
+	}
+	return changed, nil
+}
+
+// InsertQuestionBankQuestion 将题目插入到题库的指定位置，后面的题目依次后移
+func (s *questionBankQuestionService) InsertQuestionBankQuestion(ctx context.Context, req *v1.QuestionBankQuestionPositionRequest, userId uint64) (string, error) {
+	questionBankId, questionId, err := parseBankAndQuestionId(req.QuestionBankID, req.QuestionID)
+	if err != nil {
+		return "", err
+	}
+	if req.Position == nil {
+		return "", v1.ParamsError
+	}
+	questionBankQuestion := &model.QuestionBankQuestion{
+		QuestionBankID: questionBankId,
+		QuestionID:     questionId,
+		UserID:         userId,
+	}
+	err = s.tm.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.questionBankQuestionRepository.LockQuestionBank(ctx, questionBankId); err != nil {
+			return err
+		}
+		exist, err := s.questionBankQuestionRepository.GetByQuestionBankIdAndQuestionId(ctx, questionBankId, questionId)
+		if err != nil {
+			return err
+		}
+		if exist != nil {
+			return v1.ErrQuestionAlreadyInBank
+		}
+		if _, err = s.renumber(ctx, questionBankId); err != nil {
+			return err
+		}
+		maxOrder, err := s.questionBankQuestionRepository.GetMaxQuestionOrder(ctx, questionBankId)
+		if err != nil {
+			return err
+		}
+		position := clampPosition(*req.Position, maxOrder+1)
+		if err = s.questionBankQuestionRepository.ShiftQuestionOrder(ctx, questionBankId, position, -1, 1); err != nil {
+			return err
+		}
+		questionBankQuestion.QuestionOrder = position
+		return s.questionBankQuestionRepository.CreateQuestionBankQuestion(ctx, questionBankQuestion)
+	})
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatUint(questionBankQuestion.ID, 10), nil
+}
+
+// MoveQuestionBankQuestion 将题目移动到题库的指定位置，只调整移动区间内的题号
+func (s *questionBankQuestionService) MoveQuestionBankQuestion(ctx context.Context, req *v1.QuestionBankQuestionPositionRequest) (bool, error) {
+	questionBankId, questionId, err := parseBankAndQuestionId(req.QuestionBankID, req.QuestionID)
+	if err != nil {
+		return false, err
+	}
+	if req.Position == nil {
+		return false, v1.ParamsError
+	}
+	err = s.tm.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.questionBankQuestionRepository.LockQuestionBank(ctx, questionBankId); err != nil {
+			return err
+		}
+		if _, err := s.renumber(ctx, questionBankId); err != nil {
+			return err
+		}
+		current, err := s.questionBankQuestionRepository.GetByQuestionBankIdAndQuestionId(ctx, questionBankId, questionId)
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			return v1.ErrNotFound
+		}
+		maxOrder, err := s.questionBankQuestionRepository.GetMaxQuestionOrder(ctx, questionBankId)
+		if err != nil {
+			return err
+		}
+		position := clampPosition(*req.Position, maxOrder)
+		switch {
+		case position < current.QuestionOrder:
+			// 上移：[position, 原位置) 区间内的题目后移一位
+			err = s.questionBankQuestionRepository.ShiftQuestionOrder(ctx, questionBankId, position, current.QuestionOrder-1, 1)
+		case position > current.QuestionOrder:
+			// 下移：(原位置, position] 区间内的题目前移一位
+			err = s.questionBankQuestionRepository.ShiftQuestionOrder(ctx, questionBankId, current.QuestionOrder+1, position, -1)
+		default:
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return s.questionBankQuestionRepository.UpdateQuestionOrder(ctx, current.ID, position)
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ReorderQuestionBankQuestion 按给定顺序批量调整题目顺序，只更新题号发生变化的记录
+func (s *questionBankQuestionService) ReorderQuestionBankQuestion(ctx context.Context, req *v1.QuestionBankQuestionReorderRequest) (bool, error) {
+	if req.QuestionBankID == nil || len(req.QuestionIDList) == 0 {
+		return false, v1.ParamsError
+	}
+	questionBankId, err := utils.StringToUint64(*req.QuestionBankID)
+	if err != nil {
+		return false, v1.ParamsError
+	}
+	questionIds := make([]uint64, 0, len(req.QuestionIDList))
+	seen := make(map[uint64]bool, len(req.QuestionIDList))
+	for _, idStr := range req.QuestionIDList {
+		id, err := utils.StringToUint64(idStr)
+		if err != nil || seen[id] {
+			return false, v1.ParamsError
+		}
+		seen[id] = true
+		questionIds = append(questionIds, id)
+	}
+
+	err = s.tm.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.questionBankQuestionRepository.LockQuestionBank(ctx, questionBankId); err != nil {
+			return err
+		}
+		questionBankQuestions, err := s.questionBankQuestionRepository.GetQuestionBankQuestion(ctx, questionBankId, 1)
+		if err != nil {
+			return err
+		}
+		byQuestionId := make(map[uint64]model.QuestionBankQuestion, len(questionBankQuestions))
+		for _, q := range questionBankQuestions {
+			byQuestionId[q.QuestionID] = q
+		}
+		// 先放入请求中列出的题目，再按原顺序放入其余题目
+		ordered := make([]model.QuestionBankQuestion, 0, len(questionBankQuestions))
+		for _, id := range questionIds {
+			q, ok := byQuestionId[id]
+			if !ok {
+				return v1.ParamsError
+			}
+			ordered = append(ordered, q)
+		}
+		for _, q := range questionBankQuestions {
+			if !seen[q.QuestionID] {
+				ordered = append(ordered, q)
+			}
+		}
+		for i, q := range ordered {
+			if q.QuestionOrder == i+1 {
+				continue
+			}
+			if err = s.questionBankQuestionRepository.UpdateQuestionOrder(ctx, q.ID, i+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RenumberQuestionBankQuestion 重新编号消除题号空洞，返回被修改的记录数
+func (s *questionBankQuestionService) RenumberQuestionBankQuestion(ctx context.Context, req *v1.QuestionBankQuestionRenumberRequest) (int, error) {
+	if req.QuestionBankID == nil {
+		return 0, v1.ParamsError
+	}
+	questionBankId, err := utils.StringToUint64(*req.QuestionBankID)
+	if err != nil {
+		return 0, v1.ParamsError
+	}
+	var changed int
+	err = s.tm.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.questionBankQuestionRepository.LockQuestionBank(ctx, questionBankId); err != nil {
+			return err
+		}
+		changed, err = s.renumber(ctx, questionBankId)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return changed, nil
+}
+
+// GetQuestionBankNavigation 获取题目在题库中的位置以及上一题、下一题，当前题目未审核通过时仅作者和管理员可以查看
+func (s *questionBankQuestionService) GetQuestionBankNavigation(ctx context.Context, req *v1.QuestionBankNavigationRequest, loginUser *jwt.User) (v1.QuestionBankNavigationVO, error) {
+	questionBankId, questionId, err := parseBankAndQuestionId(req.QuestionBankID, req.QuestionID)
+	if err != nil {
+		return v1.QuestionBankNavigationVO{}, err
+	}
+	bank, err := s.questionBankRepository.GetByID(ctx, questionBankId)
+	if err != nil {
+		return v1.QuestionBankNavigationVO{}, err
+	}
+	if bank.ReviewStatus != constant.ReviewStatusPass {
+		return v1.QuestionBankNavigationVO{}, v1.ErrNotFound
+	}
+	current, err := s.questionBankQuestionRepository.GetByQuestionBankIdAndQuestionId(ctx, questionBankId, questionId)
+	if err != nil {
+		return v1.QuestionBankNavigationVO{}, err
+	}
+	if current == nil {
+		return v1.QuestionBankNavigationVO{}, v1.ErrNotFound
+	}
+	// 与题目详情一致，未审核通过的题目仅作者和管理员可见
+	question, err := s.questionRepository.GetByID(ctx, questionId, false, nil)
+	if err != nil {
+		return v1.QuestionBankNavigationVO{}, err
+	}
+	if question.ReviewStatus != constant.ReviewStatusPass {
+		if loginUser == nil || (loginUser.ID != question.UserID && loginUser.UserRole != constant.AdminRole) {
+			return v1.QuestionBankNavigationVO{}, v1.ErrNotFound
+		}
+	}
+
+	before, err := s.questionBankQuestionRepository.CountPassQuestionBefore(ctx, questionBankId, current.QuestionOrder, current.ID)
+	if err != nil {
+		return v1.QuestionBankNavigationVO{}, err
+	}
+	total, err := s.questionBankQuestionRepository.CountPassQuestion(ctx, questionBankId)
+	if err != nil {
+		return v1.QuestionBankNavigationVO{}, err
+	}
+	position := before + 1
+	navigation := v1.QuestionBankNavigationVO{
+		Position: &position,
+		Total:    &total,
+	}
+	if navigation.Previous, err = s.getNavigationItem(ctx, questionBankId, current, false); err != nil {
+		return v1.QuestionBankNavigationVO{}, err
+	}
+	if navigation.Next, err = s.getNavigationItem(ctx, questionBankId, current, true); err != nil {
+		return v1.QuestionBankNavigationVO{}, err
+	}
+	return navigation, nil
+}
+
+// getNavigationItem 获取上一题 / 下一题，不存在时返回 nil
+func (s *questionBankQuestionService) getNavigationItem(ctx context.Context, questionBankId uint64, current *model.QuestionBankQuestion, next bool) (*v1.QuestionBankNavigationItem, error) {
+	neighbor, err := s.questionBankQuestionRepository.GetNeighborQuestionBankQuestion(ctx, questionBankId, current.QuestionOrder, current.ID, next)
+	if err != nil || neighbor == nil {
+		return nil, err
+	}
+	question, err := s.questionRepository.GetByID(ctx, neighbor.QuestionID, false, nil)
+	if err != nil {
+		return nil, err
+	}
+	questionId := strconv.FormatUint(question.ID, 10)
+	return &v1.QuestionBankNavigationItem{
+		QuestionID: &questionId,
+		Title:      question.Title,
+	}, nil
+}
+
+// clampPosition 将题号限制在 [1, max] 范围内
+func clampPosition(position int, max int) int {
+	if position < 1 {
+		return 1
+	}
+	if position > max {
+		return max
+	}
+	return position
+}
+
+// BatchRemoveQuestionBankQuestion 批量移除题目题库关系，移除后重新编号
 func (s *questionBankQuestionService) BatchRemoveQuestionBankQuestion(ctx context.Context, req *v1.QuestionBankQuestionBatchRemoveRequest) (bool, error) {
 	// 判断参数是否合法
 	if req.QuestionBankID == nil || req.QuestionIDList == nil || len(req.QuestionIDList) == 0 {
@@ -57,16 +366,24 @@ func (s *questionBankQuestionService) BatchRemoveQuestionBankQuestion(ctx contex
 			QuestionID:     questionID,
 		})
 	}
-	if err = s.questionBankQuestionRepository.BatchRemoveQuestionBankQuestion(ctx, needRemoveQuestion); err != nil {
+	err = s.tm.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.questionBankQuestionRepository.LockQuestionBank(ctx, questionBankID); err != nil {
+			return err
+		}
+		if err := s.questionBankQuestionRepository.BatchRemoveQuestionBankQuestion(ctx, needRemoveQuestion); err != nil {
+			return err
+		}
+		_, err := s.renumber(ctx, questionBankID)
+		return err
+	})
+	if err != nil {
 		return false, err
 	}
 	return true, nil
 }
 
-// BatchAddQuestionBankQuestion 批量添加题目题库关系
-func (s *questionBankQuestionService) BatchAddQuestionBankQuestion(ctx context.Context, req *v1.QuestionBankQuestionBatchRequest) (bool, error) {
-	// 获取题库中已存在的题目
-	var questionExistList = make(map[uint64]bool)
+// BatchAddQuestionBankQuestion 批量添加题目题库关系，新题目依次追加到题库末尾
+func (s *questionBankQuestionService) BatchAddQuestionBankQuestion(ctx context.Context, req *v1.QuestionBankQuestionBatchRequest, userId uint64) (bool, error) {
 	if req.QuestionBankID == nil || len(req.QuestionIDList) == 0 {
 		return false, v1.ParamsError
 	}
@@ -74,99 +391,203 @@ func (s *questionBankQuestionService) BatchAddQuestionBankQuestion(ctx context.C
 	if err != nil {
 		return false, err
 	}
-	questions, err := s.questionBankQuestionRepository.GetQuestionBankQuestion(ctx, questionBankID, 1)
-	if err != nil {
-		return false, err
-	}
-	for _, question := range questions {
-		questionExistList[question.QuestionID] = true
+	questionIds := make([]uint64, 0, len(req.QuestionIDList))
+	for _, idStr := range req.QuestionIDList {
+		questionID, err := utils.StringToUint64(idStr)
+		if err != nil {
+			return false, err
+		}
+		questionIds = append(questionIds, questionID)
 	}
-	// 批量操作每次操作1000条
-	for i := 0; i < len(req.QuestionIDList); i += 1000 {
-		var needAddQuestion []model.QuestionBankQuestion
-		for j := i; j < i+1000 && j < len(req.QuestionIDList); j++ {
-			// 判断题库中是否已存在该题目
-			questionID, err := utils.StringToUint64(req.QuestionIDList[j])
-			if err != nil {
-				return false, err
+
+	err = s.tm.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.questionBankQuestionRepository.LockQuestionBank(ctx, questionBankID); err != nil {
+			return err
+		}
+		// 获取题库中已存在的题目
+		questions, err := s.questionBankQuestionRepository.GetQuestionBankQuestion(ctx, questionBankID, 1)
+		if err != nil {
+			return err
+		}
+		questionExistList := make(map[uint64]bool, len(questions))
+		maxOrder := 0
+		for _, question := range questions {
+			questionExistList[question.QuestionID] = true
+			if question.QuestionOrder > maxOrder {
+				maxOrder = question.QuestionOrder
 			}
-			if _, ok := questionExistList[questionID]; ok {
+		}
+		var needAddQuestion []model.QuestionBankQuestion
+		for _, questionID := range questionIds {
+			// 跳过题库中已存在或重复传入的题目
+			if questionExistList[questionID] {
 				continue
 			}
+			questionExistList[questionID] = true
+			maxOrder++
 			needAddQuestion = append(needAddQuestion, model.QuestionBankQuestion{
 				QuestionBankID: questionBankID,
 				QuestionID:     questionID,
+				UserID:         userId,
+				QuestionOrder:  maxOrder,
 			})
 		}
-		err = s.questionBankQuestionRepository.BatchAddQuestionBankQuestion(ctx, needAddQuestion)
-		if err != nil {
-			return false, err
-		}
+		return s.questionBankQuestionRepository.BatchAddQuestionBankQuestion(ctx, needAddQuestion)
+	})
+	if err != nil {
+		return false, err
 	}
 	return true, nil
 }
 
-// RemoveQuestionBankQuestion 移除题目题库关系
+// RemoveQuestionBankQuestion 移除题目题库关系，后面的题目依次前移
 func (s *questionBankQuestionService) RemoveQuestionBankQuestion(ctx context.Context, req *v1.QuestionBankQuestionRequest) (bool, error) {
-	questionID, err := strconv.ParseUint(*req.QuestionID, 10, 64)
-	questionBankID, err := strconv.ParseUint(*req.QuestionBankID, 10, 64)
+	questionBankID, questionID, err := parseBankAndQuestionId(req.QuestionBankID, req.QuestionID)
 	if err != nil {
 		return false, err
 	}
-	ok, err := s.questionBankQuestionRepository.RemoveQuestionBankQuestion(ctx, questionID, questionBankID)
+	err = s.tm.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.questionBankQuestionRepository.LockQuestionBank(ctx, questionBankID); err != nil {
+			return err
+		}
+		current, err := s.questionBankQuestionRepository.GetByQuestionBankIdAndQuestionId(ctx, questionBankID, questionID)
+		if err != nil || current == nil {
+			return err
+		}
+		if _, err = s.questionBankQuestionRepository.RemoveQuestionBankQuestion(ctx, questionID, questionBankID); err != nil {
+			return err
+		}
+		return s.questionBankQuestionRepository.ShiftQuestionOrder(ctx, questionBankID, current.QuestionOrder+1, -1, -1)
+	})
 	if err != nil {
 		return false, err
 	}
-	return ok, nil
+	return true, nil
 }
 
-// AddQuestionBankQuestion 添加题目题库关系
-func (s *questionBankQuestionService) AddQuestionBankQuestion(ctx context.Context, req *v1.QuestionBankQuestionRequest) (string, error) {
-	questionID, err := strconv.ParseUint(*req.QuestionID, 10, 64)
-	questionBankID, err := strconv.ParseUint(*req.QuestionBankID, 10, 64)
+// AddQuestionBankQuestion 添加题目题库关系，题目追加到题库末尾
+func (s *questionBankQuestionService) AddQuestionBankQuestion(ctx context.Context, req *v1.QuestionBankQuestionRequest, userId uint64) (string, error) {
+	questionBankID, questionID, err := parseBankAndQuestionId(req.QuestionBankID, req.QuestionID)
 	if err != nil {
 		return "", err
 	}
-	err = s.questionBankQuestionRepository.AddQuestionBankQuestion(ctx, questionID, questionBankID)
-	if err != nil {
-		return "", err
+	questionBankQuestion := &model.QuestionBankQuestion{
+		QuestionBankID: questionBankID,
+		QuestionID:     questionID,
+		UserID:         userId,
 	}
-	id, err := s.questionBankQuestionRepository.GetQuestionBankQuestionId(ctx, questionID, questionBankID)
+	err = s.tm.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.questionBankQuestionRepository.LockQuestionBank(ctx, questionBankID); err != nil {
+			return err
+		}
+		exist, err := s.questionBankQuestionRepository.GetByQuestionBankIdAndQuestionId(ctx, questionBankID, questionID)
+		if err != nil {
+			return err
+		}
+		if exist != nil {
+			return v1.ErrQuestionAlreadyInBank
+		}
+		maxOrder, err := s.questionBankQuestionRepository.GetMaxQuestionOrder(ctx, questionBankID)
+		if err != nil {
+			return err
+		}
+		questionBankQuestion.QuestionOrder = maxOrder + 1
+		return s.questionBankQuestionRepository.CreateQuestionBankQuestion(ctx, questionBankQuestion)
+	})
 	if err != nil {
 		return "", err
 	}
-	return strconv.FormatUint(id, 10), err
+	return strconv.FormatUint(questionBankQuestion.ID, 10), nil
 }
 
-// ListQuestionBankQuestion 获取题目题库列表
+// ListQuestionBankQuestion 分页获取题目题库关系，按题库或按题目查询
 func (s *questionBankQuestionService) ListQuestionBankQuestion(ctx context.Context, req *v1.QuestionBankQuestionQueryRequest) (v1.PageQuestionBankQuestionVO, error) {
-	switch {
-	// TODO
-	case req.QuestionBankID != nil:
+	if req.QuestionBankID == nil && req.QuestionID == nil {
+		return v1.PageQuestionBankQuestionVO{}, v1.ParamsError
+	}
+	if req.Current == nil || req.PageSize == nil || *req.Current <= 0 || *req.PageSize <= 0 {
 		return v1.PageQuestionBankQuestionVO{}, v1.ParamsError
-	case req.QuestionID != nil:
-		id, err := strconv.ParseUint(*req.QuestionID, 10, 64)
-		questionBankQuestions, err := s.questionBankQuestionRepository.GetQuestionBankQuestion(ctx, id, 0)
+	}
+	// 校验 id 参数，避免拼接到查询中的是非数字
+	for _, idStr := range []*string{req.QuestionBankID, req.QuestionID, req.UserID} {
+		if idStr == nil {
+			continue
+		}
+		if _, err := utils.StringToUint64(*idStr); err != nil {
+			return v1.PageQuestionBankQuestionVO{}, v1.ParamsError
+		}
+	}
+
+	details, total, err := s.questionBankQuestionRepository.ListQuestionBankQuestionByPage(ctx, req)
+	if err != nil {
+		return v1.PageQuestionBankQuestionVO{}, err
+	}
+	questionBankQuestionList := make([]v1.QuestionBankQuestionVO, 0, len(details))
+	for _, detail := range details {
+		id := utils.Uint64TOString(detail.ID)
+		questionBankId := utils.Uint64TOString(detail.QuestionBankID)
+		questionId := utils.Uint64TOString(detail.QuestionID)
+		userId := utils.Uint64TOString(detail.UserID)
+		questionBankQuestionList = append(questionBankQuestionList, v1.QuestionBankQuestionVO{
+			CreateTime:     &detail.CreateTime,
+			ID:             &id,
+			QuestionBankID: &questionBankId,
+			QuestionID:     &questionId,
+			QuestionOrder:  &detail.QuestionOrder,
+			QuestionTitle:  detail.QuestionTitle,
+			ReviewStatus:   detail.QuestionReviewStatus,
+			UpdateTime:     &detail.UpdateTime,
+			UserID:         &userId,
+		})
+	}
+	pages := utils.GetPages(total, *req.PageSize)
+	return v1.PageQuestionBankQuestionVO{
+		Records: questionBankQuestionList,
+		Current: req.Current,
+		Size:    req.PageSize,
+		Total:   &total,
+		Pages:   &pages,
+	}, nil
+}
+
+// ListQuestionBelongBank 查询一组题目各自所属的题库
+func (s *questionBankQuestionService) ListQuestionBelongBank(ctx context.Context, req *v1.QuestionBelongBankRequest) ([]v1.QuestionBelongBankVO, error) {
+	if len(req.QuestionIDList) == 0 || len(req.QuestionIDList) > 1000 {
+		return nil, v1.ParamsError
+	}
+	questionIds := make([]uint64, 0, len(req.QuestionIDList))
+	for _, idStr := range req.QuestionIDList {
+		id, err := utils.StringToUint64(idStr)
 		if err != nil {
-			return v1.PageQuestionBankQuestionVO{}, err
-		}
-		var questionBankQuestionList []v1.QuestionBankQuestionVO
-		for _, questionBankQuestion := range questionBankQuestions {
-			questionBankId := strconv.FormatUint(questionBankQuestion.QuestionBankID, 10)
-			questionId := strconv.FormatUint(questionBankQuestion.QuestionID, 10)
-			q := v1.QuestionBankQuestionVO{
-				CreateTime:     &questionBankQuestion.CreateTime,
-				QuestionBankID: &questionBankId,
-				QuestionID:     &questionId,
-				UpdateTime:     &questionBankQuestion.UpdateTime,
-			}
-			questionBankQuestionList = append(questionBankQuestionList, q)
+			return nil, v1.ParamsError
 		}
-		return v1.PageQuestionBankQuestionVO{
-			Records: questionBankQuestionList,
-			Size:    req.PageSize,
-		}, nil
-	default:
-		return v1.PageQuestionBankQuestionVO{}, v1.ParamsError
+		questionIds = append(questionIds, id)
+	}
+	belongs, err := s.questionBankQuestionRepository.ListQuestionBelongBank(ctx, questionIds)
+	if err != nil {
+		return nil, err
+	}
+	bankMap := make(map[uint64][]v1.QuestionBankBriefVO, len(questionIds))
+	for _, belong := range belongs {
+		bankId := utils.Uint64TOString(belong.QuestionBankID)
+		bankMap[belong.QuestionID] = append(bankMap[belong.QuestionID], v1.QuestionBankBriefVO{
+			ID:    &bankId,
+			Title: belong.Title,
+		})
+	}
+	// 按请求顺序返回，不属于任何题库的题目返回空列表
+	result := make([]v1.QuestionBelongBankVO, 0, len(questionIds))
+	seen := make(map[uint64]bool, len(questionIds))
+	for _, id := range questionIds {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		questionId := utils.Uint64TOString(id)
+		result = append(result, v1.QuestionBelongBankVO{
+			QuestionID: &questionId,
+			BankList:   bankMap[id],
+		})
 	}
+	return result, nil
 }
@@ -0,0 +1,156 @@
+package service
+
+import (
+	v1 "app/api/v1"
+	"app/internal/model"
+	"app/internal/repository"
+	"app/pkg/constant"
+	"app/pkg/jwt"
+	"app/pkg/utils"
+	"context"
+)
+
+// QuestionFavourService 题目收藏服务接口
+type QuestionFavourService interface {
+	// 收藏 / 取消收藏，返回操作后是否处于已收藏状态
+	DoQuestionFavour(ctx context.Context, req *v1.QuestionFavourRequest, loginUser *jwt.User) (bool, error)
+	// 分页获取我收藏的题目
+	ListMyFavourQuestionByPage(ctx context.Context, req *v1.QuestionFavourQueryRequest, loginUser *jwt.User) (v1.PageQuestionVO, error)
+}
+
+// NewQuestionFavourService 创建题目收藏服务实例
+func NewQuestionFavourService(
+	service *Service,
+	questionFavourRepository repository.QuestionFavourRepository,
+	questionThumbRepository repository.QuestionThumbRepository,
+	questionRepository repository.QuestionRepository,
+) QuestionFavourService {
+	return &questionFavourService{
+		Service:                  service,
+		questionFavourRepository: questionFavourRepository,
+		questionThumbRepository:  questionThumbRepository,
+		questionRepository:       questionRepository,
+	}
+}
+
+type questionFavourService struct {
+	*Service
+	questionFavourRepository repository.QuestionFavourRepository
+	questionThumbRepository  repository.QuestionThumbRepository
+	questionRepository       repository.QuestionRepository
+}
+
+// DoQuestionFavour 收藏 / 取消收藏
+func (s *questionFavourService) DoQuestionFavour(ctx context.Context, req *v1.QuestionFavourRequest, loginUser *jwt.User) (bool, error) {
+	if req.QuestionID == nil || *req.QuestionID == "" {
+		return false, v1.ParamsError
+	}
+	questionId, err := utils.StringToUint64(*req.QuestionID)
+	if err != nil {
+		return false, v1.ParamsError
+	}
+	question, err := s.questionRepository.GetByID(ctx, questionId, false, nil)
+	if err != nil {
+		return false, err
+	}
+	if question.ReviewStatus != constant.ReviewStatusPass {
+		return false, v1.ErrNotFound
+	}
+
+	// 已收藏则取消，未收藏则收藏，依赖唯一索引保证同一用户只有一条记录；
+	// 并发请求时只有实际新增或删除了记录的请求才修改收藏数，避免重复计数
+	var hasFavour, changed bool
+	err = s.tm.Transaction(ctx, func(ctx context.Context) error {
+		favour, err := s.questionFavourRepository.GetByQuestionIdAndUserId(ctx, questionId, loginUser.ID)
+		if err != nil {
+			return err
+		}
+		if favour != nil {
+			changed, err = s.questionFavourRepository.DeleteById(ctx, favour.ID)
+			return err
+		}
+		hasFavour = true
+		changed, err = s.questionFavourRepository.Create(ctx, &model.QuestionFavour{
+			QuestionID: questionId,
+			UserID:     loginUser.ID,
+		})
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+	if !changed {
+		return hasFavour, nil
+	}
+
+	// 收藏数先记在 Redis 中，由定时任务刷回数据库
+	var delta int64 = -1
+	if hasFavour {
+		delta = 1
+	}
+	if err = s.questionFavourRepository.IncrFavourNum(ctx, questionId, delta); err != nil {
+		return false, err
+	}
+	return hasFavour, nil
+}
+
+// ListMyFavourQuestionByPage 分页获取我收藏的题目
+func (s *questionFavourService) ListMyFavourQuestionByPage(ctx context.Context, req *v1.QuestionFavourQueryRequest, loginUser *jwt.User) (v1.PageQuestionVO, error) {
+	if req.Current == nil || req.PageSize == nil || *req.Current <= 0 || *req.PageSize <= 0 {
+		return v1.PageQuestionVO{}, v1.ParamsError
+	}
+	current := req.Current
+	size := req.PageSize
+	questions, total, err := s.questionFavourRepository.ListFavourQuestionByPage(ctx, loginUser.ID, *current, *size)
+	if err != nil {
+		return v1.PageQuestionVO{}, err
+	}
+
+	questionIds := make([]uint64, 0, len(questions))
+	for _, question := range questions {
+		questionIds = append(questionIds, question.ID)
+	}
+	thumbMap, err := s.questionThumbRepository.GetThumbQuestionIds(ctx, loginUser.ID, questionIds)
+	if err != nil {
+		return v1.PageQuestionVO{}, err
+	}
+
+	var questionVOList []v1.QuestionVO
+	for _, question := range questions {
+		var id, userId string
+		id = utils.Uint64TOString(question.ID)
+		userId = utils.Uint64TOString(question.UserID)
+
+		tagList, err := utils.StringToStrings(*question.Tags)
+		if err != nil {
+			return v1.PageQuestionVO{}, err
+		}
+
+		hasThumb := thumbMap[question.ID]
+		hasFavour := true
+		q := v1.QuestionVO{
+			Answer:     question.Answer,
+			Content:    question.Content,
+			CreateTime: &question.CreateTime,
+			ID:         &id,
+			TagList:    tagList,
+			Title:      question.Title,
+			UpdateTime: &question.UpdateTime,
+			UserID:     &userId,
+
+			ThumbNum:  &question.ThumbNum,
+			FavourNum: &question.FavourNum,
+			HasThumb:  &hasThumb,
+			HasFavour: &hasFavour,
+		}
+		questionVOList = append(questionVOList, q)
+	}
+	pages := total / *size + 1
+	return v1.PageQuestionVO{
+		Records: questionVOList,
+		Total:   &total,
+		Pages:   &pages,
+		Size:    size,
+		Current: current,
+	}, nil
+}
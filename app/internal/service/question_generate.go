@@ -0,0 +1,410 @@
+package service
+
+import (
+	v1 "app/api/v1"
+	"app/internal/model"
+	"app/internal/repository"
+	"app/pkg/constant"
+	"app/pkg/jwt"
+	"app/pkg/llm"
+	"app/pkg/rabbmit"
+	"app/pkg/utils"
+	"context"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// generatedQuestionLine AI 输出的一行题目，如 “1. 什么是反射？”，也兼容 “1、”“1)” 和无序列表
+var generatedQuestionLine = regexp.MustCompile(`^\s*(?:\d+\s*[.、．)）]|[-*])\s*(.+)$`)
+
+// QuestionGenerateService AI 生成题目任务服务接口
+type QuestionGenerateService interface {
+	// 提交任务
+	SubmitQuestionGenerateJob(ctx context.Context, req *v1.AddQuestionByAIRequest, loginUser *jwt.User) (*v1.QuestionGenerateJobVO, error)
+	// 获取任务
+	GetQuestionGenerateJob(ctx context.Context, req *v1.QuestionGenerateJobGetRequest, loginUser *jwt.User) (*v1.QuestionGenerateJobVO, error)
+	// 分页获取当前用户的任务
+	ListMyQuestionGenerateJobByPage(ctx context.Context, req *v1.QuestionGenerateJobQueryRequest, loginUser *jwt.User) (v1.QuestionQueryResponseData[v1.QuestionGenerateJobVO], error)
+	// 执行任务，由消息队列消费者调用
+	ExecuteQuestionGenerateJob(ctx context.Context, jobId uint64) error
+	// 将任务标记为失败，由消息队列消费者在重试次数耗尽时调用
+	FailQuestionGenerateJob(ctx context.Context, jobId uint64, reason string) error
+}
+
+// NewQuestionGenerateService 创建 AI 生成题目任务服务实例
+func NewQuestionGenerateService(
+	service *Service,
+	questionGenerateJobRepository repository.QuestionGenerateJobRepository,
+	questionRepository repository.QuestionRepository,
+	questionRevisionRepository repository.QuestionRevisionRepository,
+	questionOutboxRepository repository.QuestionOutboxRepository,
+	questionBankRepository repository.QuestionBankRepository,
+	questionBankQuestionRepository repository.QuestionBankQuestionRepository,
+	promptTemplateRepository repository.PromptTemplateRepository,
+	llmClient llm.Client,
+) QuestionGenerateService {
+	return &questionGenerateService{
+		Service:                        service,
+		questionGenerateJobRepository:  questionGenerateJobRepository,
+		questionRepository:             questionRepository,
+		questionRevisionRepository:     questionRevisionRepository,
+		questionOutboxRepository:       questionOutboxRepository,
+		questionBankRepository:         questionBankRepository,
+		questionBankQuestionRepository: questionBankQuestionRepository,
+		promptTemplateRepository:       promptTemplateRepository,
+		llmClient:                      llmClient,
+	}
+}
+
+type questionGenerateService struct {
+	*Service
+	questionGenerateJobRepository  repository.QuestionGenerateJobRepository
+	questionRepository             repository.QuestionRepository
+	questionRevisionRepository     repository.QuestionRevisionRepository
+	questionOutboxRepository       repository.QuestionOutboxRepository
+	questionBankRepository         repository.QuestionBankRepository
+	questionBankQuestionRepository repository.QuestionBankQuestionRepository
+	promptTemplateRepository       repository.PromptTemplateRepository
+	llmClient                      llm.Client
+}
+
+// SubmitQuestionGenerateJob 创建任务并投递到消息队列，立即返回任务；指定题库时只有管理员或题库创建人可以加入题目
+func (s *questionGenerateService) SubmitQuestionGenerateJob(ctx context.Context, req *v1.AddQuestionByAIRequest, loginUser *jwt.User) (*v1.QuestionGenerateJobVO, error) {
+	direction := strings.TrimSpace(req.Direction)
+	if direction == "" || utf8.RuneCountInString(direction) > 256 {
+		return nil, v1.ParamsError
+	}
+	number := req.Number
+	if number <= 0 {
+		number = constant.QuestionGenerateDefaultNumber
+	}
+	if number > constant.QuestionGenerateMaxNumber {
+		return nil, v1.ParamsError
+	}
+	job := &model.QuestionGenerateJob{
+		UserID:    loginUser.ID,
+		Direction: direction,
+		Number:    number,
+		Status:    constant.QuestionGenerateStatusPending,
+	}
+	if req.QuestionBankID != nil && *req.QuestionBankID != "" {
+		questionBankId, err := utils.StringToUint64(*req.QuestionBankID)
+		if err != nil {
+			return nil, v1.ParamsError
+		}
+		bank, err := s.questionBankRepository.GetByID(ctx, questionBankId)
+		if err != nil {
+			return nil, err
+		}
+		if bank.UserID != loginUser.ID && loginUser.UserRole != constant.AdminRole {
+			return nil, v1.ErrUnauthorized
+		}
+		job.QuestionBankID = &questionBankId
+	}
+	if err := s.questionGenerateJobRepository.Create(ctx, job); err != nil {
+		return nil, err
+	}
+	// 投递失败时任务直接标记为失败，避免一直停留在排队中
+	if err := rabbmit.Publish(rabbmit.QuestionGenerateQueue, []byte(strconv.FormatUint(job.ID, 10)), nil); err != nil {
+		s.logger.WithContext(ctx).Error("publish question generate job error", zap.Uint64("jobId", job.ID), zap.Error(err))
+		if err := s.finishQuestionGenerateJob(ctx, job, constant.QuestionGenerateStatusFailed, "提交任务失败"); err != nil {
+			s.logger.WithContext(ctx).Error("fail question generate job error", zap.Uint64("jobId", job.ID), zap.Error(err))
+		}
+		return nil, v1.ErrSystemIsBusy
+	}
+	vo := toQuestionGenerateJobVO(job)
+	return &vo, nil
+}
+
+// GetQuestionGenerateJob 获取任务，只有本人或管理员可以查看
+func (s *questionGenerateService) GetQuestionGenerateJob(ctx context.Context, req *v1.QuestionGenerateJobGetRequest, loginUser *jwt.User) (*v1.QuestionGenerateJobVO, error) {
+	if req.ID == nil {
+		return nil, v1.ParamsError
+	}
+	id, err := utils.StringToUint64(*req.ID)
+	if err != nil {
+		return nil, v1.ParamsError
+	}
+	job, err := s.questionGenerateJobRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, v1.ErrNotFound
+	}
+	if job.UserID != loginUser.ID && loginUser.UserRole != constant.AdminRole {
+		return nil, v1.ErrUnauthorized
+	}
+	vo := toQuestionGenerateJobVO(job)
+	return &vo, nil
+}
+
+// ListMyQuestionGenerateJobByPage 分页获取当前用户的任务
+func (s *questionGenerateService) ListMyQuestionGenerateJobByPage(ctx context.Context, req *v1.QuestionGenerateJobQueryRequest, loginUser *jwt.User) (v1.QuestionQueryResponseData[v1.QuestionGenerateJobVO], error) {
+	if req.Current == nil || req.PageSize == nil || *req.Current <= 0 || *req.PageSize <= 0 {
+		return v1.QuestionQueryResponseData[v1.QuestionGenerateJobVO]{}, v1.ParamsError
+	}
+	jobs, total, err := s.questionGenerateJobRepository.ListByPage(ctx, loginUser.ID, *req.Current, *req.PageSize)
+	if err != nil {
+		return v1.QuestionQueryResponseData[v1.QuestionGenerateJobVO]{}, err
+	}
+	records := make([]v1.QuestionGenerateJobVO, 0, len(jobs))
+	for i := range jobs {
+		records = append(records, toQuestionGenerateJobVO(&jobs[i]))
+	}
+	pages := utils.GetPages(total, *req.PageSize)
+	return v1.QuestionQueryResponseData[v1.QuestionGenerateJobVO]{
+		Records: records,
+		Total:   &total,
+		Pages:   &pages,
+		Size:    req.PageSize,
+		Current: req.Current,
+	}, nil
+}
+
+// ExecuteQuestionGenerateJob 调用 AI 生成题目并逐题入库：已存在的标题跳过，新题目为待审核状态，指定题库时追加到题库末尾；
+// 消息重复投递时已结束的任务直接跳过，执行中的任务使用已保存的标题从已处理的位置继续执行，不再重新调用 AI
+func (s *questionGenerateService) ExecuteQuestionGenerateJob(ctx context.Context, jobId uint64) error {
+	job, err := s.questionGenerateJobRepository.GetByID(ctx, jobId)
+	if err != nil {
+		return err
+	}
+	if job == nil || job.Status == constant.QuestionGenerateStatusSucceeded || job.Status == constant.QuestionGenerateStatusFailed {
+		return nil
+	}
+	if job.Status == constant.QuestionGenerateStatusPending {
+		ok, err := s.questionGenerateJobRepository.UpdateStatus(ctx, job.ID, job.Status, constant.QuestionGenerateStatusRunning)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+		job.Status = constant.QuestionGenerateStatusRunning
+	}
+
+	titles, err := s.loadQuestionGenerateTitles(ctx, job)
+	if err != nil {
+		return err
+	}
+	if titles == nil {
+		// 任务已被标记为失败
+		return nil
+	}
+	for _, title := range titles[job.ProcessedNum:] {
+		if err = s.addGeneratedQuestion(ctx, job, title); errors.Is(err, v1.ErrNotFound) {
+			return s.finishQuestionGenerateJob(ctx, job, constant.QuestionGenerateStatusFailed, "目标题库不存在或已删除")
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return s.finishQuestionGenerateJob(ctx, job, constant.QuestionGenerateStatusSucceeded, "")
+}
+
+// FailQuestionGenerateJob 将未结束的任务标记为失败
+func (s *questionGenerateService) FailQuestionGenerateJob(ctx context.Context, jobId uint64, reason string) error {
+	job, err := s.questionGenerateJobRepository.GetByID(ctx, jobId)
+	if err != nil {
+		return err
+	}
+	if job == nil || job.Status == constant.QuestionGenerateStatusSucceeded || job.Status == constant.QuestionGenerateStatusFailed {
+		return nil
+	}
+	return s.finishQuestionGenerateJob(ctx, job, constant.QuestionGenerateStatusFailed, reason)
+}
+
+// loadQuestionGenerateTitles 返回任务要处理的题目标题：已保存过标题时直接读取，否则调用 AI 生成并在处理前保存到任务上；
+// AI 调用失败或没有有效题目时将任务标记为失败并返回 nil
+func (s *questionGenerateService) loadQuestionGenerateTitles(ctx context.Context, job *model.QuestionGenerateJob) ([]string, error) {
+	if job.Titles != nil {
+		titles, err := utils.StringToStrings(*job.Titles)
+		if err != nil {
+			return nil, err
+		}
+		if job.ProcessedNum > len(titles) {
+			job.ProcessedNum = len(titles)
+		}
+		return titles, nil
+	}
+
+	// 读取模板失败时返回错误由消费者重试，模板渲染或 AI 调用失败时任务直接失败
+	promptTemplate, err := getPromptTemplate(ctx, s.promptTemplateRepository, constant.PromptSceneGenerateQuestion, constant.PromptPersonaDefault)
+	if err != nil {
+		return nil, err
+	}
+	titles, err := s.generateQuestionTitles(ctx, job, promptTemplate)
+	if err != nil {
+		s.logger.WithContext(ctx).Error("generate question by ai error", zap.Uint64("jobId", job.ID), zap.Error(err))
+		return nil, s.finishQuestionGenerateJob(ctx, job, constant.QuestionGenerateStatusFailed, v1.ErrAIGenerateQuestion.Error())
+	}
+	if len(titles) == 0 {
+		return nil, s.finishQuestionGenerateJob(ctx, job, constant.QuestionGenerateStatusFailed, "AI 没有返回有效的题目")
+	}
+
+	titlesStr := utils.StringsToString(titles)
+	job.Titles = &titlesStr
+	job.Total, job.ProcessedNum, job.CreatedNum, job.SkippedNum = len(titles), 0, 0, 0
+	if err = s.questionGenerateJobRepository.SaveTitles(ctx, job); err != nil {
+		return nil, err
+	}
+	return titles, nil
+}
+
+// generateQuestionTitles 使用模板渲染系统 Prompt 并调用 AI，返回去重后的题目标题，最多为请求的数量
+func (s *questionGenerateService) generateQuestionTitles(ctx context.Context, job *model.QuestionGenerateJob, promptTemplate *model.PromptTemplate) ([]string, error) {
+	systemPrompt, err := renderPromptTemplate(promptTemplate.Content, generateQuestionPromptVars{Number: job.Number, Direction: job.Direction})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.llmClient.Chat(ctx, &llm.Request{
+		Messages: []llm.Message{
+			{Role: llm.RoleSystem, Content: systemPrompt},
+			{Role: llm.RoleUser, Content: fmt.Sprintf("数量：%d\n方向：%s\n", job.Number, job.Direction)},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return parseGeneratedQuestions(resp.Content, job.Number), nil
+}
+
+// parseGeneratedQuestions 解析 AI 输出的编号列表：去掉编号和 Markdown 标记，跳过空行、说明文字和超长标题，同一批次内忽略大小写去重
+func parseGeneratedQuestions(content string, limit int) []string {
+	var titles []string
+	seen := make(map[string]struct{})
+	for _, line := range strings.Split(content, "\n") {
+		matches := generatedQuestionLine.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		title := strings.NewReplacer("`", "", "**", "").Replace(matches[1])
+		title = strings.TrimSpace(title)
+		if title == "" || utf8.RuneCountInString(title) > 256 {
+			continue
+		}
+		key := strings.ToLower(title)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		titles = append(titles, title)
+		if len(titles) >= limit {
+			break
+		}
+	}
+	return titles
+}
+
+// addGeneratedQuestion 在事务内创建一道待审核的题目，记录同步事件和修订记录，指定题库时追加到题库末尾，标题已存在时跳过；
+// 任务进度在同一事务内更新，重新执行时从已处理的位置继续不会重复计数
+func (s *questionGenerateService) addGeneratedQuestion(ctx context.Context, job *model.QuestionGenerateJob, title string) error {
+	progress := *job
+	err := s.tm.Transaction(ctx, func(ctx context.Context) error {
+		created, err := s.createGeneratedQuestion(ctx, job, title)
+		if err != nil {
+			return err
+		}
+		if created {
+			progress.CreatedNum = job.CreatedNum + 1
+		} else {
+			progress.SkippedNum = job.SkippedNum + 1
+		}
+		progress.ProcessedNum = job.ProcessedNum + 1
+		return s.questionGenerateJobRepository.UpdateProgress(ctx, &progress)
+	})
+	if err != nil {
+		return err
+	}
+	job.ProcessedNum, job.CreatedNum, job.SkippedNum = progress.ProcessedNum, progress.CreatedNum, progress.SkippedNum
+	return nil
+}
+
+// createGeneratedQuestion 创建一道待审核的题目，标题已存在时返回 false
+func (s *questionGenerateService) createGeneratedQuestion(ctx context.Context, job *model.QuestionGenerateJob, title string) (bool, error) {
+	exist, err := s.questionRepository.GetByTitle(ctx, title)
+	if err != nil {
+		return false, err
+	}
+	if exist != nil {
+		return false, nil
+	}
+	tags := utils.StringsToString([]string{job.Direction})
+	question := &model.Question{
+		Title:        &title,
+		Content:      &title,
+		Tags:         &tags,
+		UserID:       job.UserID,
+		ReviewStatus: constant.ReviewStatusPending,
+	}
+	if err := s.questionRepository.Create(ctx, question); err != nil {
+		return false, err
+	}
+	if err := saveQuestionOutbox(ctx, s.questionOutboxRepository, constant.QuestionOutboxEventCreate, question.ID); err != nil {
+		return false, err
+	}
+	if err := saveQuestionRevision(ctx, s.questionRevisionRepository, question, job.UserID, constant.QuestionRevisionActionCreate, nil); err != nil {
+		return false, err
+	}
+	if job.QuestionBankID != nil {
+		if err := s.questionBankQuestionRepository.LockQuestionBank(ctx, *job.QuestionBankID); err != nil {
+			return false, err
+		}
+		maxOrder, err := s.questionBankQuestionRepository.GetMaxQuestionOrder(ctx, *job.QuestionBankID)
+		if err != nil {
+			return false, err
+		}
+		if err := s.questionBankQuestionRepository.CreateQuestionBankQuestion(ctx, &model.QuestionBankQuestion{
+			QuestionBankID: *job.QuestionBankID,
+			QuestionID:     question.ID,
+			UserID:         job.UserID,
+			QuestionOrder:  maxOrder + 1,
+		}); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// finishQuestionGenerateJob 结束任务
+func (s *questionGenerateService) finishQuestionGenerateJob(ctx context.Context, job *model.QuestionGenerateJob, status int, reason string) error {
+	now := time.Now()
+	job.Status = status
+	job.ErrorMessage = truncateRunes(reason, constant.QuestionGenerateErrorMaxLen)
+	job.FinishTime = &now
+	return s.questionGenerateJobRepository.Finish(ctx, job)
+}
+
+// toQuestionGenerateJobVO 转换为任务视图，进度按已处理的题目数计算
+func toQuestionGenerateJobVO(job *model.QuestionGenerateJob) v1.QuestionGenerateJobVO {
+	vo := v1.QuestionGenerateJobVO{
+		ID:           utils.Uint64TOString(job.ID),
+		Direction:    job.Direction,
+		Number:       job.Number,
+		Status:       job.Status,
+		Total:        job.Total,
+		ProcessedNum: job.ProcessedNum,
+		CreatedNum:   job.CreatedNum,
+		SkippedNum:   job.SkippedNum,
+		ErrorMessage: job.ErrorMessage,
+		CreateTime:   job.CreateTime,
+		FinishTime:   job.FinishTime,
+	}
+	if job.QuestionBankID != nil {
+		questionBankId := utils.Uint64TOString(*job.QuestionBankID)
+		vo.QuestionBankID = &questionBankId
+	}
+	switch {
+	case job.Status == constant.QuestionGenerateStatusSucceeded:
+		vo.Progress = 100
+	case job.Total > 0:
+		vo.Progress = job.ProcessedNum * 100 / job.Total
+	}
+	return vo
+}
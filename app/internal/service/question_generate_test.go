@@ -0,0 +1,66 @@
+package service
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseGeneratedQuestions(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		limit   int
+		want    []string
+	}{
+		{
+			name:    "numbered list",
+			content: "1. 什么是反射？\n2、Redis 持久化方式\n3) 进程和线程的区别\n4）GC 的原理\n5．HashMap 扩容",
+			limit:   10,
+			want:    []string{"什么是反射？", "Redis 持久化方式", "进程和线程的区别", "GC 的原理", "HashMap 扩容"},
+		},
+		{
+			name:    "unordered list and markdown",
+			content: "- **什么是 `volatile`？**\n* 线程池参数",
+			limit:   10,
+			want:    []string{"什么是 volatile？", "线程池参数"},
+		},
+		{
+			name:    "skip explanation and blank lines",
+			content: "好的，以下是题目：\n\n1. 什么是反射？\n\n以上题目仅供参考\n2.   \n",
+			limit:   10,
+			want:    []string{"什么是反射？"},
+		},
+		{
+			name:    "deduplicate ignoring case",
+			content: "1. JVM 内存模型\n2. jvm 内存模型\n3. 类加载过程",
+			limit:   10,
+			want:    []string{"JVM 内存模型", "类加载过程"},
+		},
+		{
+			name:    "skip overlong title",
+			content: "1. " + strings.Repeat("长", 257) + "\n2. 什么是反射？",
+			limit:   10,
+			want:    []string{"什么是反射？"},
+		},
+		{
+			name:    "stop at limit",
+			content: "1. a\n2. b\n3. c",
+			limit:   2,
+			want:    []string{"a", "b"},
+		},
+		{
+			name:    "no questions",
+			content: "抱歉，我无法生成题目。",
+			limit:   10,
+			want:    nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseGeneratedQuestions(tt.content, tt.limit); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseGeneratedQuestions() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
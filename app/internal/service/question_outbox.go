@@ -0,0 +1,24 @@
+package service
+
+import (
+	"app/internal/model"
+	"app/internal/repository"
+	"app/pkg/constant"
+	"context"
+	"time"
+)
+
+// saveQuestionOutbox 写入题目变更事件，需要和题目修改在同一事务中调用，提交后由中继任务同步到 ES
+func saveQuestionOutbox(ctx context.Context, repo repository.QuestionOutboxRepository, eventType string, questionIds ...uint64) error {
+	now := time.Now()
+	events := make([]model.QuestionOutbox, 0, len(questionIds))
+	for _, id := range questionIds {
+		events = append(events, model.QuestionOutbox{
+			QuestionID:    id,
+			EventType:     eventType,
+			Status:        constant.QuestionOutboxStatusPending,
+			NextRetryTime: now,
+		})
+	}
+	return repo.Create(ctx, events)
+}
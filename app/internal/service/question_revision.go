@@ -0,0 +1,299 @@
+package service
+
+import (
+	v1 "app/api/v1"
+	"app/internal/model"
+	"app/internal/repository"
+	"app/pkg/constant"
+	"app/pkg/jwt"
+	"app/pkg/utils"
+	"context"
+)
+
+// QuestionRevisionService 题目修订记录服务接口
+type QuestionRevisionService interface {
+	// 分页获取题目的修订记录
+	ListRevisionByPage(ctx context.Context, req *v1.QuestionRevisionQueryRequest, loginUser *jwt.User) (v1.QuestionQueryResponseData[v1.QuestionRevisionVO], error)
+	// 对比两个版本的差异
+	DiffRevision(ctx context.Context, req *v1.QuestionRevisionDiffRequest, loginUser *jwt.User) (v1.QuestionRevisionDiffVO, error)
+	// 回滚到指定版本
+	RollbackRevision(ctx context.Context, req *v1.QuestionRevisionRollbackRequest, loginUser *jwt.User) (bool, error)
+}
+
+// NewQuestionRevisionService 创建题目修订记录服务实例
+func NewQuestionRevisionService(
+	service *Service,
+	questionRevisionRepository repository.QuestionRevisionRepository,
+	questionRepository repository.QuestionRepository,
+	questionOutboxRepository repository.QuestionOutboxRepository,
+) QuestionRevisionService {
+	return &questionRevisionService{
+		Service:                    service,
+		questionRevisionRepository: questionRevisionRepository,
+		questionRepository:         questionRepository,
+		questionOutboxRepository:   questionOutboxRepository,
+	}
+}
+
+type questionRevisionService struct {
+	*Service
+	questionRevisionRepository repository.QuestionRevisionRepository
+	questionRepository         repository.QuestionRepository
+	questionOutboxRepository   repository.QuestionOutboxRepository
+}
+
+// saveQuestionRevision 保存题目当前内容为一个新版本
+func saveQuestionRevision(ctx context.Context, repo repository.QuestionRevisionRepository, question *model.Question, editorId uint64, action string, rollbackVersion *int) error {
+	version, err := repo.GetLatestVersion(ctx, question.ID)
+	if err != nil {
+		return err
+	}
+	return repo.Create(ctx, &model.QuestionRevision{
+		QuestionID:      question.ID,
+		Version:         version + 1,
+		Title:           question.Title,
+		Content:         question.Content,
+		Tags:            question.Tags,
+		Answer:          question.Answer,
+		EditorID:        editorId,
+		Action:          action,
+		RollbackVersion: rollbackVersion,
+	})
+}
+
+// saveBaselineRevision 题目还没有修订记录时（历史数据），先把修改前的内容保存为第一个版本
+func saveBaselineRevision(ctx context.Context, repo repository.QuestionRevisionRepository, question *model.Question) error {
+	version, err := repo.GetLatestVersion(ctx, question.ID)
+	if err != nil {
+		return err
+	}
+	if version > 0 {
+		return nil
+	}
+	return repo.Create(ctx, &model.QuestionRevision{
+		QuestionID: question.ID,
+		Version:    1,
+		Title:      question.Title,
+		Content:    question.Content,
+		Tags:       question.Tags,
+		Answer:     question.Answer,
+		EditorID:   question.UserID,
+		Action:     constant.QuestionRevisionActionCreate,
+		CreateTime: question.UpdateTime,
+	})
+}
+
+// getEditableQuestion 获取题目，并校验当前用户是否为题目创建者或管理员
+func (s *questionRevisionService) getEditableQuestion(ctx context.Context, questionIdStr *string, loginUser *jwt.User) (*model.Question, uint64, error) {
+	if questionIdStr == nil || *questionIdStr == "" {
+		return nil, 0, v1.ParamsError
+	}
+	questionId, err := utils.StringToUint64(*questionIdStr)
+	if err != nil {
+		return nil, 0, v1.ParamsError
+	}
+	question, err := s.questionRepository.GetByID(ctx, questionId, false, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	if question.UserID != loginUser.ID && loginUser.UserRole != constant.AdminRole {
+		return nil, 0, v1.ErrUnauthorized
+	}
+	return question, loginUser.ID, nil
+}
+
+// ListRevisionByPage 分页获取题目的修订记录
+func (s *questionRevisionService) ListRevisionByPage(ctx context.Context, req *v1.QuestionRevisionQueryRequest, loginUser *jwt.User) (v1.QuestionQueryResponseData[v1.QuestionRevisionVO], error) {
+	question, _, err := s.getEditableQuestion(ctx, req.QuestionID, loginUser)
+	if err != nil {
+		return v1.QuestionQueryResponseData[v1.QuestionRevisionVO]{}, err
+	}
+	if req.Current == nil || req.PageSize == nil || *req.Current <= 0 || *req.PageSize <= 0 {
+		return v1.QuestionQueryResponseData[v1.QuestionRevisionVO]{}, v1.ParamsError
+	}
+	current := req.Current
+	size := req.PageSize
+	revisions, total, err := s.questionRevisionRepository.ListByQuestionId(ctx, question.ID, *current, *size)
+	if err != nil {
+		return v1.QuestionQueryResponseData[v1.QuestionRevisionVO]{}, err
+	}
+
+	var revisionList []v1.QuestionRevisionVO
+	for _, revision := range revisions {
+		vo, err := toQuestionRevisionVO(revision)
+		if err != nil {
+			return v1.QuestionQueryResponseData[v1.QuestionRevisionVO]{}, err
+		}
+		revisionList = append(revisionList, vo)
+	}
+	pages := utils.GetPages(total, *size)
+	return v1.QuestionQueryResponseData[v1.QuestionRevisionVO]{
+		Records: revisionList,
+		Total:   &total,
+		Pages:   &pages,
+		Size:    size,
+		Current: current,
+	}, nil
+}
+
+// DiffRevision 对比两个版本的差异
+func (s *questionRevisionService) DiffRevision(ctx context.Context, req *v1.QuestionRevisionDiffRequest, loginUser *jwt.User) (v1.QuestionRevisionDiffVO, error) {
+	question, _, err := s.getEditableQuestion(ctx, req.QuestionID, loginUser)
+	if err != nil {
+		return v1.QuestionRevisionDiffVO{}, err
+	}
+	if req.FromVersion == nil || req.ToVersion == nil {
+		return v1.QuestionRevisionDiffVO{}, v1.ParamsError
+	}
+	from, err := s.questionRevisionRepository.GetByVersion(ctx, question.ID, *req.FromVersion)
+	if err != nil {
+		return v1.QuestionRevisionDiffVO{}, err
+	}
+	to, err := s.questionRevisionRepository.GetByVersion(ctx, question.ID, *req.ToVersion)
+	if err != nil {
+		return v1.QuestionRevisionDiffVO{}, err
+	}
+	return diffQuestionRevision(from, to)
+}
+
+// RollbackRevision 回滚到指定版本，回滚本身也会记录为一个新版本
+func (s *questionRevisionService) RollbackRevision(ctx context.Context, req *v1.QuestionRevisionRollbackRequest, loginUser *jwt.User) (bool, error) {
+	question, editorId, err := s.getEditableQuestion(ctx, req.QuestionID, loginUser)
+	if err != nil {
+		return false, err
+	}
+	if req.Version == nil {
+		return false, v1.ParamsError
+	}
+	revision, err := s.questionRevisionRepository.GetByVersion(ctx, question.ID, *req.Version)
+	if err != nil {
+		return false, err
+	}
+
+	question.Title = revision.Title
+	question.Content = revision.Content
+	question.Tags = revision.Tags
+	question.Answer = revision.Answer
+	// 回滚后的题目与修改一样需要重新审核
+	question.ReviewStatus = constant.ReviewStatusPending
+	question.ReviewMessage = nil
+	question.ReviewerID = nil
+	question.ReviewTime = nil
+
+	err = s.tm.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.questionRepository.Update(ctx, question); err != nil {
+			return err
+		}
+		if err := saveQuestionOutbox(ctx, s.questionOutboxRepository, constant.QuestionOutboxEventUpdate, question.ID); err != nil {
+			return err
+		}
+		return saveQuestionRevision(ctx, s.questionRevisionRepository, question, editorId, constant.QuestionRevisionActionRollback, req.Version)
+	})
+	if err != nil {
+		return false, err
+	}
+	if err = s.questionRepository.DeleteQuestionCache(ctx, question.ID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// toQuestionRevisionVO 修订记录转换为 VO
+func toQuestionRevisionVO(revision model.QuestionRevision) (v1.QuestionRevisionVO, error) {
+	var tagList []string
+	if revision.Tags != nil {
+		tags, err := utils.StringToStrings(*revision.Tags)
+		if err != nil {
+			return v1.QuestionRevisionVO{}, err
+		}
+		tagList = tags
+	}
+	id := utils.Uint64TOString(revision.ID)
+	questionId := utils.Uint64TOString(revision.QuestionID)
+	editorId := utils.Uint64TOString(revision.EditorID)
+	return v1.QuestionRevisionVO{
+		ID:              &id,
+		QuestionID:      &questionId,
+		Version:         &revision.Version,
+		Title:           revision.Title,
+		Content:         revision.Content,
+		Answer:          revision.Answer,
+		TagList:         tagList,
+		EditorID:        &editorId,
+		Action:          &revision.Action,
+		RollbackVersion: revision.RollbackVersion,
+		CreateTime:      &revision.CreateTime,
+	}, nil
+}
+
+// diffQuestionRevision 计算两个版本之间字段级别的差异
+func diffQuestionRevision(from *model.QuestionRevision, to *model.QuestionRevision) (v1.QuestionRevisionDiffVO, error) {
+	questionId := utils.Uint64TOString(from.QuestionID)
+	result := v1.QuestionRevisionDiffVO{
+		QuestionID:  &questionId,
+		FromVersion: &from.Version,
+		ToVersion:   &to.Version,
+		Fields:      []v1.QuestionRevisionFieldDiff{},
+	}
+
+	fields := []struct {
+		name     string
+		from, to *string
+	}{
+		{"title", from.Title, to.Title},
+		{"content", from.Content, to.Content},
+		{"answer", from.Answer, to.Answer},
+		{"tags", from.Tags, to.Tags},
+	}
+	for _, f := range fields {
+		if stringValue(f.from) != stringValue(f.to) {
+			result.Fields = append(result.Fields, v1.QuestionRevisionFieldDiff{
+				Field: f.name,
+				From:  f.from,
+				To:    f.to,
+			})
+		}
+	}
+
+	// 标签额外给出增删明细
+	fromTags, err := revisionTags(from)
+	if err != nil {
+		return v1.QuestionRevisionDiffVO{}, err
+	}
+	toTags, err := revisionTags(to)
+	if err != nil {
+		return v1.QuestionRevisionDiffVO{}, err
+	}
+	result.AddedTags = subtractStrings(toTags, fromTags)
+	result.RemovedTags = subtractStrings(fromTags, toTags)
+	return result, nil
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func revisionTags(revision *model.QuestionRevision) ([]string, error) {
+	if revision.Tags == nil || *revision.Tags == "" {
+		return nil, nil
+	}
+	return utils.StringToStrings(*revision.Tags)
+}
+
+// subtractStrings 返回在 a 中但不在 b 中的元素
+func subtractStrings(a []string, b []string) []string {
+	set := make(map[string]struct{}, len(b))
+	for _, s := range b {
+		set[s] = struct{}{}
+	}
+	var result []string
+	for _, s := range a {
+		if _, ok := set[s]; !ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
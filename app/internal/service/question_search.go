@@ -0,0 +1,165 @@
+package service
+
+import (
+	v1 "app/api/v1"
+	"app/internal/model"
+	"app/internal/repository"
+	"app/pkg/constant"
+	"app/pkg/jwt"
+	"app/pkg/utils"
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultQuestionSearchStatsDays 搜索统计默认天数
+	defaultQuestionSearchStatsDays = 7
+	// maxQuestionSearchStatsDays 搜索统计最多天数，与日志保留时间一致
+	maxQuestionSearchStatsDays = 90
+	// defaultQuestionSearchStatsLimit 搜索统计默认返回条数
+	defaultQuestionSearchStatsLimit = 20
+	// maxQuestionSearchStatsLimit 搜索统计最多返回条数
+	maxQuestionSearchStatsLimit = 100
+)
+
+// QuestionSearchService 题目搜索统计服务接口
+type QuestionSearchService interface {
+	// 记录搜索结果点击
+	RecordClick(ctx context.Context, req *v1.QuestionSearchClickRequest, loginUser *jwt.User) (bool, error)
+	// 统计搜索次数最多的搜索词
+	ListTopQuery(ctx context.Context, req *v1.QuestionSearchStatsRequest) ([]v1.QuestionSearchQueryStat, error)
+	// 统计无结果次数最多的搜索词
+	ListZeroResultQuery(ctx context.Context, req *v1.QuestionSearchStatsRequest) ([]v1.QuestionZeroResultStat, error)
+	// 统计搜索词的点击率
+	ListClickThrough(ctx context.Context, req *v1.QuestionSearchStatsRequest) ([]v1.QuestionClickThroughStat, error)
+	// 获取最近 24 小时的热门搜索
+	ListTrending(ctx context.Context) ([]v1.QuestionTrendingSearch, error)
+}
+
+// NewQuestionSearchService 创建题目搜索统计服务实例
+func NewQuestionSearchService(
+	service *Service,
+	questionSearchLogRepository repository.QuestionSearchLogRepository,
+) QuestionSearchService {
+	return &questionSearchService{
+		Service:                     service,
+		questionSearchLogRepository: questionSearchLogRepository,
+	}
+}
+
+type questionSearchService struct {
+	*Service
+	questionSearchLogRepository repository.QuestionSearchLogRepository
+}
+
+// questionSearchFilters 搜索日志中记录的过滤条件
+type questionSearchFilters struct {
+	Tags           []string `json:"tags,omitempty"`
+	QuestionBankID *string  `json:"questionBankId,omitempty"`
+	UserID         *string  `json:"userId,omitempty"`
+	ReviewStatus   *int     `json:"reviewStatus,omitempty"`
+	SortField      *string  `json:"sortField,omitempty"`
+	SortOrder      *string  `json:"sortOrder,omitempty"`
+	Current        *int     `json:"current,omitempty"`
+}
+
+// normalizeSearchQuery 规范化搜索词：去掉首尾空白、合并连续空白并转为小写，超长时截断
+func normalizeSearchQuery(searchText *string) string {
+	if searchText == nil {
+		return ""
+	}
+	query := []rune(strings.ToLower(strings.Join(strings.Fields(*searchText), " ")))
+	if len(query) > constant.QuestionSearchQueryMaxLen {
+		query = query[:constant.QuestionSearchQueryMaxLen]
+	}
+	return string(query)
+}
+
+// newQuestionSearchLog 根据搜索请求和结果构造搜索日志
+func newQuestionSearchLog(req *v1.QuestionRequest, result *repository.QuestionSearchResult, searchId string, latency time.Duration, loginUser *jwt.User) *model.QuestionSearchLog {
+	log := &model.QuestionSearchLog{
+		SearchID:    searchId,
+		Query:       normalizeSearchQuery(req.SearchText),
+		ResultCount: result.Total,
+		LatencyMs:   int(latency.Milliseconds()),
+		Backend:     result.Backend,
+		CreateTime:  time.Now(),
+	}
+	if filters, err := json.Marshal(questionSearchFilters{
+		Tags:           req.Tags,
+		QuestionBankID: req.QuestionBankID,
+		UserID:         req.UserID,
+		ReviewStatus:   req.ReviewStatus,
+		SortField:      req.SortField,
+		SortOrder:      req.SortOrder,
+		Current:        req.Current,
+	}); err == nil && len(filters) <= 1024 {
+		f := string(filters)
+		log.Filters = &f
+	}
+	if loginUser != nil {
+		log.UserID = &loginUser.ID
+	}
+	return log
+}
+
+// questionSearchStatsParams 获取统计起始时间和返回条数
+func questionSearchStatsParams(req *v1.QuestionSearchStatsRequest) (time.Time, int) {
+	days, limit := defaultQuestionSearchStatsDays, defaultQuestionSearchStatsLimit
+	if req.Days != nil && *req.Days > 0 {
+		days = min(*req.Days, maxQuestionSearchStatsDays)
+	}
+	if req.Limit != nil && *req.Limit > 0 {
+		limit = min(*req.Limit, maxQuestionSearchStatsLimit)
+	}
+	return time.Now().AddDate(0, 0, -days), limit
+}
+
+// RecordClick 记录搜索结果点击，异步写入
+func (s *questionSearchService) RecordClick(ctx context.Context, req *v1.QuestionSearchClickRequest, loginUser *jwt.User) (bool, error) {
+	if req.SearchID == nil || req.QuestionID == nil || *req.SearchID == "" || len(*req.SearchID) > 32 {
+		return false, v1.ParamsError
+	}
+	questionId, err := utils.StringToUint64(*req.QuestionID)
+	if err != nil {
+		return false, v1.ParamsError
+	}
+	click := &model.QuestionSearchClick{
+		SearchID:   *req.SearchID,
+		QuestionID: questionId,
+		CreateTime: time.Now(),
+	}
+	if req.Position != nil && *req.Position > 0 {
+		click.Position = *req.Position
+	}
+	if loginUser != nil {
+		click.UserID = &loginUser.ID
+	}
+	s.questionSearchLogRepository.RecordClick(click)
+	return true, nil
+}
+
+// ListTopQuery 统计搜索次数最多的搜索词
+func (s *questionSearchService) ListTopQuery(ctx context.Context, req *v1.QuestionSearchStatsRequest) ([]v1.QuestionSearchQueryStat, error) {
+	since, limit := questionSearchStatsParams(req)
+	return s.questionSearchLogRepository.ListTopQuery(ctx, since, limit)
+}
+
+// ListZeroResultQuery 统计无结果次数最多的搜索词
+func (s *questionSearchService) ListZeroResultQuery(ctx context.Context, req *v1.QuestionSearchStatsRequest) ([]v1.QuestionZeroResultStat, error) {
+	since, limit := questionSearchStatsParams(req)
+	return s.questionSearchLogRepository.ListZeroResultQuery(ctx, since, limit)
+}
+
+// ListClickThrough 统计搜索词的点击率
+func (s *questionSearchService) ListClickThrough(ctx context.Context, req *v1.QuestionSearchStatsRequest) ([]v1.QuestionClickThroughStat, error) {
+	since, limit := questionSearchStatsParams(req)
+	return s.questionSearchLogRepository.ListClickThrough(ctx, since, limit)
+}
+
+// ListTrending 获取最近 24 小时的热门搜索
+func (s *questionSearchService) ListTrending(ctx context.Context) ([]v1.QuestionTrendingSearch, error) {
+	return s.questionSearchLogRepository.ListTrending(ctx)
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	v1 "app/api/v1"
+	"app/internal/model"
+	"app/internal/repository"
+	"app/pkg/utils"
+	"context"
+	"strings"
+	"unicode/utf8"
+)
+
+// QuestionSynonymService 搜索同义词服务接口
+type QuestionSynonymService interface {
+	// 添加同义词规则
+	AddQuestionSynonym(ctx context.Context, req *v1.AddQuestionSynonymRequest, userId uint64) (string, error)
+	// 修改同义词规则
+	UpdateQuestionSynonym(ctx context.Context, req *v1.UpdateQuestionSynonymRequest) (bool, error)
+	// 删除同义词规则
+	DeleteQuestionSynonym(ctx context.Context, req *v1.DeleteQuestionSynonymRequest) (bool, error)
+	// 分页获取同义词规则
+	ListQuestionSynonymByPage(ctx context.Context, req *v1.QuestionSynonymQueryRequest) (v1.QuestionQueryResponseData[v1.QuestionSynonymVO], error)
+	// 将全部同义词规则推送到 ES
+	PushQuestionSynonym(ctx context.Context) (bool, error)
+}
+
+// NewQuestionSynonymService 创建搜索同义词服务实例
+func NewQuestionSynonymService(
+	service *Service,
+	questionSynonymRepository repository.QuestionSynonymRepository,
+	questionIndexRepository repository.QuestionIndexRepository,
+) QuestionSynonymService {
+	return &questionSynonymService{
+		Service:                   service,
+		questionSynonymRepository: questionSynonymRepository,
+		questionIndexRepository:   questionIndexRepository,
+	}
+}
+
+type questionSynonymService struct {
+	*Service
+	questionSynonymRepository repository.QuestionSynonymRepository
+	questionIndexRepository   repository.QuestionIndexRepository
+}
+
+// normalizeSynonymRule 校验并规范化 Solr 格式的同义词规则：
+// "a, b, c" 表示互为同义词，"a, b => c" 表示把 a、b 替换为 c；词语统一转为小写
+func normalizeSynonymRule(rule string) (string, bool) {
+	sides := strings.Split(rule, "=>")
+	if len(sides) > 2 {
+		return "", false
+	}
+	normalized := make([]string, 0, len(sides))
+	count := 0
+	for _, side := range sides {
+		var words []string
+		for _, word := range strings.Split(side, ",") {
+			if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+				words = append(words, word)
+			}
+		}
+		if len(words) == 0 {
+			return "", false
+		}
+		count += len(words)
+		normalized = append(normalized, strings.Join(words, ", "))
+	}
+	// 互为同义词的规则至少需要两个词
+	if count < 2 {
+		return "", false
+	}
+	rule = strings.Join(normalized, " => ")
+	if utf8.RuneCountInString(rule) > 512 {
+		return "", false
+	}
+	return rule, true
+}
+
+// pushSynonyms 读取全部规则并推送到 ES；在事务内调用时推送失败会回滚本次修改，保证数据库与 ES 一致
+func (s *questionSynonymService) pushSynonyms(ctx context.Context) error {
+	synonyms, err := s.questionSynonymRepository.ListAll(ctx)
+	if err != nil {
+		return err
+	}
+	return s.questionIndexRepository.PutSynonyms(ctx, synonyms)
+}
+
+// AddQuestionSynonym 添加同义词规则
+func (s *questionSynonymService) AddQuestionSynonym(ctx context.Context, req *v1.AddQuestionSynonymRequest, userId uint64) (string, error) {
+	if req.Rule == nil {
+		return "", v1.ParamsError
+	}
+	rule, ok := normalizeSynonymRule(*req.Rule)
+	if !ok {
+		return "", v1.ErrIllegalSynonymRule
+	}
+	synonym := &model.QuestionSynonym{Rule: rule, UserID: userId}
+	err := s.tm.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.questionSynonymRepository.Create(ctx, synonym); err != nil {
+			return err
+		}
+		return s.pushSynonyms(ctx)
+	})
+	if err != nil {
+		return "", err
+	}
+	return utils.Uint64TOString(synonym.ID), nil
+}
+
+// UpdateQuestionSynonym 修改同义词规则
+func (s *questionSynonymService) UpdateQuestionSynonym(ctx context.Context, req *v1.UpdateQuestionSynonymRequest) (bool, error) {
+	if req.ID == nil || req.Rule == nil {
+		return false, v1.ParamsError
+	}
+	id, err := utils.StringToUint64(*req.ID)
+	if err != nil {
+		return false, v1.ParamsError
+	}
+	rule, ok := normalizeSynonymRule(*req.Rule)
+	if !ok {
+		return false, v1.ErrIllegalSynonymRule
+	}
+	err = s.tm.Transaction(ctx, func(ctx context.Context) error {
+		synonym, err := s.questionSynonymRepository.GetById(ctx, id)
+		if err != nil {
+			return err
+		}
+		synonym.Rule = rule
+		if err = s.questionSynonymRepository.Update(ctx, synonym); err != nil {
+			return err
+		}
+		return s.pushSynonyms(ctx)
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// DeleteQuestionSynonym 删除同义词规则
+func (s *questionSynonymService) DeleteQuestionSynonym(ctx context.Context, req *v1.DeleteQuestionSynonymRequest) (bool, error) {
+	if req.ID == nil {
+		return false, v1.ParamsError
+	}
+	id, err := utils.StringToUint64(*req.ID)
+	if err != nil {
+		return false, v1.ParamsError
+	}
+	err = s.tm.Transaction(ctx, func(ctx context.Context) error {
+		if _, err := s.questionSynonymRepository.GetById(ctx, id); err != nil {
+			return err
+		}
+		if err := s.questionSynonymRepository.DeleteById(ctx, id); err != nil {
+			return err
+		}
+		return s.pushSynonyms(ctx)
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ListQuestionSynonymByPage 分页获取同义词规则
+func (s *questionSynonymService) ListQuestionSynonymByPage(ctx context.Context, req *v1.QuestionSynonymQueryRequest) (v1.QuestionQueryResponseData[v1.QuestionSynonymVO], error) {
+	if req.Current == nil || req.PageSize == nil || *req.Current <= 0 || *req.PageSize <= 0 {
+		return v1.QuestionQueryResponseData[v1.QuestionSynonymVO]{}, v1.ParamsError
+	}
+	var searchText string
+	if req.SearchText != nil {
+		searchText = strings.ToLower(strings.TrimSpace(*req.SearchText))
+	}
+	synonyms, total, err := s.questionSynonymRepository.ListByPage(ctx, searchText, *req.Current, *req.PageSize)
+	if err != nil {
+		return v1.QuestionQueryResponseData[v1.QuestionSynonymVO]{}, err
+	}
+
+	records := make([]v1.QuestionSynonymVO, 0, len(synonyms))
+	for _, synonym := range synonyms {
+		id := utils.Uint64TOString(synonym.ID)
+		userId := utils.Uint64TOString(synonym.UserID)
+		rule := synonym.Rule
+		createTime, updateTime := synonym.CreateTime, synonym.UpdateTime
+		records = append(records, v1.QuestionSynonymVO{
+			ID:         &id,
+			Rule:       &rule,
+			UserID:     &userId,
+			CreateTime: &createTime,
+			UpdateTime: &updateTime,
+		})
+	}
+	pages := utils.GetPages(total, *req.PageSize)
+	return v1.QuestionQueryResponseData[v1.QuestionSynonymVO]{
+		Records: records,
+		Total:   &total,
+		Pages:   &pages,
+		Size:    req.PageSize,
+		Current: req.Current,
+	}, nil
+}
+
+// PushQuestionSynonym 将全部同义词规则推送到 ES，用于 ES 重建或推送失败后手动同步
+func (s *questionSynonymService) PushQuestionSynonym(ctx context.Context) (bool, error) {
+	if err := s.pushSynonyms(ctx); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeSynonymRule(t *testing.T) {
+	tests := []struct {
+		rule   string
+		want   string
+		wantOk bool
+	}{
+		{"JS, JavaScript", "js, javascript", true},
+		{" k8s ,kubernetes,, ", "k8s, kubernetes", true},
+		{"mysql => MySQL 数据库", "mysql => mysql 数据库", true},
+		{"js, ecmascript=>javascript", "js, ecmascript => javascript", true},
+		{"redis", "", false},
+		{"", "", false},
+		{"a => ", "", false},
+		{" => b", "", false},
+		{"a => b => c", "", false},
+		{strings.Repeat("a", 300) + ", " + strings.Repeat("b", 300), "", false},
+	}
+	for _, tt := range tests {
+		got, ok := normalizeSynonymRule(tt.rule)
+		if got != tt.want || ok != tt.wantOk {
+			t.Errorf("normalizeSynonymRule(%q) = %q, %v, want %q, %v", tt.rule, got, ok, tt.want, tt.wantOk)
+		}
+	}
+}
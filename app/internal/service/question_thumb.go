@@ -0,0 +1,90 @@
+package service
+
+import (
+	v1 "app/api/v1"
+	"app/internal/model"
+	"app/internal/repository"
+	"app/pkg/constant"
+	"app/pkg/jwt"
+	"app/pkg/utils"
+	"context"
+)
+
+// QuestionThumbService 题目点赞服务接口
+type QuestionThumbService interface {
+	// 点赞 / 取消点赞，返回操作后是否处于已点赞状态
+	DoQuestionThumb(ctx context.Context, req *v1.QuestionThumbRequest, loginUser *jwt.User) (bool, error)
+}
+
+// NewQuestionThumbService 创建题目点赞服务实例
+func NewQuestionThumbService(
+	service *Service,
+	questionThumbRepository repository.QuestionThumbRepository,
+	questionRepository repository.QuestionRepository,
+) QuestionThumbService {
+	return &questionThumbService{
+		Service:                 service,
+		questionThumbRepository: questionThumbRepository,
+		questionRepository:      questionRepository,
+	}
+}
+
+type questionThumbService struct {
+	*Service
+	questionThumbRepository repository.QuestionThumbRepository
+	questionRepository      repository.QuestionRepository
+}
+
+// DoQuestionThumb 点赞 / 取消点赞
+func (s *questionThumbService) DoQuestionThumb(ctx context.Context, req *v1.QuestionThumbRequest, loginUser *jwt.User) (bool, error) {
+	if req.QuestionID == nil || *req.QuestionID == "" {
+		return false, v1.ParamsError
+	}
+	questionId, err := utils.StringToUint64(*req.QuestionID)
+	if err != nil {
+		return false, v1.ParamsError
+	}
+	question, err := s.questionRepository.GetByID(ctx, questionId, false, nil)
+	if err != nil {
+		return false, err
+	}
+	if question.ReviewStatus != constant.ReviewStatusPass {
+		return false, v1.ErrNotFound
+	}
+
+	// 已点赞则取消，未点赞则点赞，依赖唯一索引保证同一用户只有一条记录；
+	// 并发请求时只有实际新增或删除了记录的请求才修改点赞数，避免重复计数
+	var hasThumb, changed bool
+	err = s.tm.Transaction(ctx, func(ctx context.Context) error {
+		thumb, err := s.questionThumbRepository.GetByQuestionIdAndUserId(ctx, questionId, loginUser.ID)
+		if err != nil {
+			return err
+		}
+		if thumb != nil {
+			changed, err = s.questionThumbRepository.DeleteById(ctx, thumb.ID)
+			return err
+		}
+		hasThumb = true
+		changed, err = s.questionThumbRepository.Create(ctx, &model.QuestionThumb{
+			QuestionID: questionId,
+			UserID:     loginUser.ID,
+		})
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+	if !changed {
+		return hasThumb, nil
+	}
+
+	// 点赞数先记在 Redis 中，由定时任务刷回数据库
+	var delta int64 = -1
+	if hasThumb {
+		delta = 1
+	}
+	if err = s.questionThumbRepository.IncrThumbNum(ctx, questionId, delta); err != nil {
+		return false, err
+	}
+	return hasThumb, nil
+}
@@ -24,8 +24,8 @@ type UserService interface {
 	AddUser(ctx context.Context, req *v1.AddUserRequest) (uint64, error)
 	DeleteUser(ctx context.Context, req *v1.DeleteUserRequest) (bool, error)
 	UpdateUser(ctx context.Context, req *v1.UpdateUserRequest) (bool, error)
-	AddUserSignIn(ctx context.Context, token string) (bool, error)
-	GetUserSignIn(ctx context.Context, token string, year int) ([]int, error)
+	AddUserSignIn(ctx context.Context, loginUser *jwt.User) (bool, error)
+	GetUserSignIn(ctx context.Context, loginUser *jwt.User, year int) ([]int, error)
 	Logout(ctx context.Context, token string, userAgent string) (bool, error)
 }
 
@@ -62,14 +62,8 @@ func (s *userService) Logout(ctx context.Context, token string, userAgent string
 }
 
 // GetUserSignIn 获取用户签到记录
-func (s *userService) GetUserSignIn(ctx context.Context, token string, year int) ([]int, error) {
-	// 解析 token
-	claims, err := s.jwt.ParseToken(token)
-	if err != nil {
-		return nil, err
-	}
-
-	key := constant.GetUserSignInRedisKey(strconv.Itoa(year), strconv.FormatUint(claims.User.ID, 10))
+func (s *userService) GetUserSignIn(ctx context.Context, loginUser *jwt.User, year int) ([]int, error) {
+	key := constant.GetUserSignInRedisKey(strconv.Itoa(year), strconv.FormatUint(loginUser.ID, 10))
 	bitset, err := s.userRepo.GetUserSignIn(ctx, key)
 	if err != nil {
 		return nil, err
@@ -94,17 +88,12 @@ func (s *userService) GetUserSignIn(ctx context.Context, token string, year int)
 }
 
 // AddUserSignIn 添加用户签到记录
-func (s *userService) AddUserSignIn(ctx context.Context, token string) (bool, error) {
-	// 解析 token
-	claims, err := s.jwt.ParseToken(token)
-	if err != nil {
-		return false, err
-	}
+func (s *userService) AddUserSignIn(ctx context.Context, loginUser *jwt.User) (bool, error) {
 	date := time.Now()
 	year := date.Year()
-	key := constant.GetUserSignInRedisKey(strconv.Itoa(year), strconv.FormatUint(claims.User.ID, 10))
+	key := constant.GetUserSignInRedisKey(strconv.Itoa(year), strconv.FormatUint(loginUser.ID, 10))
 	offset := date.YearDay()
-	err = s.userRepo.AddUserSignIn(ctx, key, int64(offset))
+	err := s.userRepo.AddUserSignIn(ctx, key, int64(offset))
 	if err != nil {
 		return false, err
 	}
@@ -134,7 +123,9 @@ func (s *userService) UpdateUser(ctx context.Context, req *v1.UpdateUserRequest)
 	if req.UserName != nil && *req.UserName != "" {
 		user.UserName = req.UserName
 	}
+	roleChanged := false
 	if req.UserRole != nil && *req.UserRole != "" {
+		roleChanged = user.UserRole != *req.UserRole
 		user.UserRole = *req.UserRole
 	}
 	if req.UserProfile != nil && *req.UserProfile != "" {
@@ -145,6 +136,12 @@ func (s *userService) UpdateUser(ctx context.Context, req *v1.UpdateUserRequest)
 	if err != nil {
 		return false, err
 	}
+	// 角色保存在登录时签发的 token 中，修改角色（包括封禁）后让该用户所有登录端的 token 失效，重新登录后生效
+	if roleChanged {
+		if err = s.userRepo.DeleteAllTokens(ctx, id); err != nil {
+			return false, err
+		}
+	}
 
 	return true, nil
 }
@@ -169,6 +166,10 @@ func (s *userService) DeleteUser(ctx context.Context, req *v1.DeleteUserRequest)
 	if err != nil {
 		return false, err
 	}
+	// 被删除的用户立即下线
+	if err = s.userRepo.DeleteAllTokens(ctx, id); err != nil {
+		return false, err
+	}
 
 	return true, nil
 }
@@ -335,7 +336,7 @@ func (s *userService) Login(ctx context.Context, req *v1.LoginRequest, userAgent
 	}
 
 	// 禁止被封禁的用户登录
-	if user.UserRole == "ban" {
+	if user.UserRole == constant.BanRole {
 		return "", nil, v1.ErrBanRole
 	}
 
@@ -346,13 +347,14 @@ func (s *userService) Login(ctx context.Context, req *v1.LoginRequest, userAgent
 
 	// 生成 token
 	token, err := s.jwt.GenToken(jwt.User{
-		ID:          user.ID,
-		UserName:    user.UserName,
-		UserAvatar:  user.UserAvatar,
-		UserProfile: user.UserProfile,
-		UserRole:    user.UserRole,
-		CreateTime:  user.CreateTime,
-		UpdateTime:  user.UpdateTime,
+		ID:            user.ID,
+		UserName:      user.UserName,
+		UserAvatar:    user.UserAvatar,
+		UserProfile:   user.UserProfile,
+		UserRole:      user.UserRole,
+		VipExpireTime: user.VipExpireTime,
+		CreateTime:    user.CreateTime,
+		UpdateTime:    user.UpdateTime,
 	}, time.Now().Add(time.Hour*24))
 	if err != nil {
 		return "", nil, v1.ErrInternalServerError
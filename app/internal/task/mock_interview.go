@@ -0,0 +1,59 @@
+package task
+
+import (
+	"app/internal/repository"
+	"app/pkg/constant"
+	"context"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+	"time"
+)
+
+type MockInterviewTask interface {
+	AbandonIdleMockInterview(ctx context.Context) error
+}
+
+func NewMockInterviewTask(
+	task *Task,
+	conf *viper.Viper,
+	mockInterviewRepo repository.MockInterviewRepository,
+) MockInterviewTask {
+	idleTimeout := conf.GetDuration("mock_interview.idle_timeout")
+	if idleTimeout <= 0 {
+		idleTimeout = constant.MockInterviewDefaultIdleTimeout
+	}
+	pausedTimeout := conf.GetDuration("mock_interview.paused_timeout")
+	if pausedTimeout <= 0 {
+		pausedTimeout = constant.MockInterviewDefaultPausedTimeout
+	}
+	return &mockInterviewTask{
+		mockInterviewRepo: mockInterviewRepo,
+		idleTimeout:       idleTimeout,
+		pausedTimeout:     pausedTimeout,
+		Task:              task,
+	}
+}
+
+type mockInterviewTask struct {
+	mockInterviewRepo repository.MockInterviewRepository
+	idleTimeout       time.Duration
+	pausedTimeout     time.Duration
+	*Task
+}
+
+// AbandonIdleMockInterview 将长时间无活动的进行中面试和长时间未继续的暂停面试置为已放弃
+func (t mockInterviewTask) AbandonIdleMockInterview(ctx context.Context) error {
+	now := time.Now()
+	idle, err := t.mockInterviewRepo.UpdateIdleMockInterviewStatus(ctx, constant.MockInterviewStatusInProgress, now.Add(-t.idleTimeout), constant.MockInterviewStatusAbandoned)
+	if err != nil {
+		return err
+	}
+	paused, err := t.mockInterviewRepo.UpdateIdleMockInterviewStatus(ctx, constant.MockInterviewStatusPaused, now.Add(-t.pausedTimeout), constant.MockInterviewStatusAbandoned)
+	if err != nil {
+		return err
+	}
+	if idle+paused > 0 {
+		t.logger.Info("AbandonIdleMockInterview", zap.Int64("idle", idle), zap.Int64("paused", paused))
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package task
+
+import (
+	"app/internal/repository"
+	"app/pkg/constant"
+	"context"
+	"go.uber.org/zap"
+	"time"
+)
+
+type QuestionTask interface {
+	SyncQuestionThumbFavourNum(ctx context.Context) error
+	SyncViewNum(ctx context.Context) error
+	CleanQuestionOutbox(ctx context.Context) error
+	CleanQuestionSearchLog(ctx context.Context) error
+}
+
+func NewQuestionTask(
+	task *Task,
+	questionRepo repository.QuestionRepository,
+	questionBankRepo repository.QuestionBankRepository,
+	questionThumbRepo repository.QuestionThumbRepository,
+	questionFavourRepo repository.QuestionFavourRepository,
+	questionOutboxRepo repository.QuestionOutboxRepository,
+	questionSearchLogRepo repository.QuestionSearchLogRepository,
+) QuestionTask {
+	return &questionTask{
+		questionRepo:          questionRepo,
+		questionBankRepo:      questionBankRepo,
+		questionThumbRepo:     questionThumbRepo,
+		questionFavourRepo:    questionFavourRepo,
+		questionOutboxRepo:    questionOutboxRepo,
+		questionSearchLogRepo: questionSearchLogRepo,
+		Task:                  task,
+	}
+}
+
+type questionTask struct {
+	questionRepo          repository.QuestionRepository
+	questionBankRepo      repository.QuestionBankRepository
+	questionThumbRepo     repository.QuestionThumbRepository
+	questionFavourRepo    repository.QuestionFavourRepository
+	questionOutboxRepo    repository.QuestionOutboxRepository
+	questionSearchLogRepo repository.QuestionSearchLogRepository
+	*Task
+}
+
+// SyncViewNum 将 Redis 中累计的题目、题库浏览量增量刷回数据库
+func (t questionTask) SyncViewNum(ctx context.Context) error {
+	if err := t.questionRepo.FlushViewNum(ctx); err != nil {
+		return err
+	}
+	return t.questionBankRepo.FlushViewNum(ctx)
+}
+
+// SyncQuestionThumbFavourNum 将 Redis 中累计的点赞数、收藏数增量刷回数据库
+func (t questionTask) SyncQuestionThumbFavourNum(ctx context.Context) error {
+	if err := t.questionThumbRepo.FlushThumbNum(ctx); err != nil {
+		return err
+	}
+	return t.questionFavourRepo.FlushFavourNum(ctx)
+}
+
+// CleanQuestionOutbox 清理超过保留时间的已投递题目变更事件
+func (t questionTask) CleanQuestionOutbox(ctx context.Context) error {
+	n, err := t.questionOutboxRepo.DeleteSentBefore(ctx, time.Now().Add(-constant.QuestionOutboxRetentionTime))
+	if err != nil {
+		return err
+	}
+	t.logger.Info("CleanQuestionOutbox", zap.Int64("deleted", n))
+	return nil
+}
+
+// CleanQuestionSearchLog 清理超过保留时间的搜索和点击日志
+func (t questionTask) CleanQuestionSearchLog(ctx context.Context) error {
+	n, err := t.questionSearchLogRepo.DeleteBefore(ctx, time.Now().Add(-constant.QuestionSearchLogRetentionTime))
+	if err != nil {
+		return err
+	}
+	t.logger.Info("CleanQuestionSearchLog", zap.Int64("deleted", n))
+	return nil
+}
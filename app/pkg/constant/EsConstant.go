@@ -0,0 +1,21 @@
+package constant
+
+const (
+	QuestionIndexAlias  = "question"   // 题目索引别名，读写都通过别名访问
+	QuestionIndexPrefix = "question_v" // 题目索引实际名称前缀，后接创建时间
+
+	QuestionTagFacetSize = 20 // 搜索结果返回的标签聚合数量
+)
+
+const (
+	QuestionSearchBackendEs = "elasticsearch" // 搜索结果由 ES 提供
+	QuestionSearchBackendDB = "database"      // ES 不可用时由数据库提供搜索结果
+
+	QuestionSearchEsResource = "question-search:es" // ES 搜索的熔断资源名
+)
+
+// QuestionSynonymSet 题目索引使用的 ES 同义词集合，由管理员维护的同义词表推送
+const QuestionSynonymSet = "question-synonyms"
+
+// QuestionSearchFields 关键词搜索的字段及权重，标题 > 答案 > 内容
+var QuestionSearchFields = []string{"title^3", "answer^2", "content"}
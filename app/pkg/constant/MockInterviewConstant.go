@@ -0,0 +1,71 @@
+package constant
+
+import "time"
+
+// 模拟面试状态
+const (
+	MockInterviewStatusPending    = 0 // 待开始
+	MockInterviewStatusInProgress = 1 // 进行中
+	MockInterviewStatusFinished   = 2 // 已结束
+	MockInterviewStatusPaused     = 3 // 已暂停
+	MockInterviewStatusAbandoned  = 4 // 已放弃
+)
+
+// 模拟面试事件
+const (
+	MockInterviewEventStart   = "start"   // 开始面试
+	MockInterviewEventChat    = "chat"    // 回答问题
+	MockInterviewEventPause   = "pause"   // 暂停面试
+	MockInterviewEventResume  = "resume"  // 继续面试
+	MockInterviewEventEnd     = "end"     // 结束面试并生成总结
+	MockInterviewEventAbandon = "abandon" // 放弃面试
+)
+
+// MockInterviewEndMarker 面试官主动结束面试时回复中包含的标记
+const MockInterviewEndMarker = "【面试结束】"
+
+// MockInterviewReportMaxAttempts 生成评估报告时，模型输出不符合 JSON Schema 的最大尝试次数
+const MockInterviewReportMaxAttempts = 3
+
+// MockInterviewRecommendSize 评估报告推荐的题目数量
+const MockInterviewRecommendSize = 5
+
+// 题库模式出题数量
+const (
+	MockInterviewDefaultQuestionNum = 5
+	MockInterviewMaxQuestionNum     = 20
+)
+
+// MockInterviewRecentPracticeDays 抽题时排除用户最近多少天内在模拟面试中练习过的题目
+const MockInterviewRecentPracticeDays = 30
+
+// MockInterviewReferenceAnswerMaxLen 系统 Prompt 中每道题参考答案的最大字符数，避免上下文过长
+const MockInterviewReferenceAnswerMaxLen = 500
+
+// 模拟面试超时默认值，可通过 mock_interview.idle_timeout、mock_interview.paused_timeout 配置
+const (
+	MockInterviewDefaultIdleTimeout   = 30 * time.Minute // 进行中的面试无活动超过该时间自动放弃
+	MockInterviewDefaultPausedTimeout = 24 * time.Hour   // 暂停的面试超过该时间未继续自动放弃
+)
+
+// 模拟面试上下文管理：发送给 AI 的上下文超过 token 预算时，将较早的对话合并为滚动摘要，始终保留系统 Prompt 和最近几轮对话
+const (
+	MockInterviewContextMaxTokens = 6000 // 上下文 token 预算
+	MockInterviewContextKeepTurns = 4    // 始终保留的最近对话轮数
+	MockInterviewSummaryMaxLen    = 1500 // 滚动摘要的最大字符数
+)
+
+// MockInterviewMessageMaxPageSize 分页查询对话记录的最大每页条数
+const MockInterviewMessageMaxPageSize = 100
+
+// 模拟面试导出格式
+const (
+	MockInterviewExportMarkdown = "markdown"
+	MockInterviewExportHTML     = "html"
+)
+
+// 模拟面试分享链接有效期（小时）
+const (
+	MockInterviewShareDefaultExpireHours = 72
+	MockInterviewShareMaxExpireHours     = 720
+)
@@ -0,0 +1,24 @@
+package constant
+
+import "time"
+
+// 题目同步事件类型
+const (
+	QuestionOutboxEventCreate = "create" // 创建
+	QuestionOutboxEventUpdate = "update" // 修改
+	QuestionOutboxEventDelete = "delete" // 删除
+)
+
+// 题目同步事件状态
+const (
+	QuestionOutboxStatusPending = 0 // 待投递
+	QuestionOutboxStatusSent    = 1 // 已投递
+	QuestionOutboxStatusFailed  = 2 // 重试次数耗尽，需要人工处理
+)
+
+const (
+	QuestionOutboxRelayBatchSize = 100                // 中继每次投递的事件数
+	QuestionOutboxMaxRetry       = 10                 // 中继投递失败的最大重试次数
+	QuestionOutboxRetentionTime  = 7 * 24 * time.Hour // 已投递事件的保留时间
+	QuestionSyncConsumeMaxRetry  = 5                  // 消费失败的最大重试次数，超过后放入停放队列
+)
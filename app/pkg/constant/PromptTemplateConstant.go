@@ -0,0 +1,18 @@
+package constant
+
+// Prompt 模板场景
+const (
+	PromptSceneMockInterview    = "mock_interview"    // 模拟面试官系统 Prompt
+	PromptSceneGenerateQuestion = "generate_question" // AI 生成题目系统 Prompt
+)
+
+// 各场景的默认风格，数据库中没有对应模板时使用内置模板
+const (
+	PromptPersonaDefault        = "default" // 只有一种风格的场景使用
+	MockInterviewPersonaStrict  = "strict"  // 严厉的大厂面试官
+	MockInterviewPersonaMentor  = "mentor"  // 友善的导师
+	MockInterviewPersonaEnglish = "english" // 英文面试
+)
+
+// PromptTemplateMaxLen Prompt 模板内容的最大字符数
+const PromptTemplateMaxLen = 20000
@@ -0,0 +1,21 @@
+package constant
+
+// AI 生成题目任务状态
+const (
+	QuestionGenerateStatusPending   = 0 // 排队中
+	QuestionGenerateStatusRunning   = 1 // 生成中
+	QuestionGenerateStatusSucceeded = 2 // 已完成
+	QuestionGenerateStatusFailed    = 3 // 失败
+)
+
+// 单个任务生成的题目数量
+const (
+	QuestionGenerateDefaultNumber = 10
+	QuestionGenerateMaxNumber     = 50
+)
+
+// QuestionGenerateConsumeMaxRetry 消费失败的最大重试次数，超过后任务标记为失败
+const QuestionGenerateConsumeMaxRetry = 3
+
+// QuestionGenerateErrorMaxLen 任务失败原因的最大字符数
+const QuestionGenerateErrorMaxLen = 500
@@ -1,6 +1,9 @@
 package constant
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 const UserSignInRedisKeyPrefix = "user:signins"
 
@@ -21,3 +24,49 @@ func NextSetBit(bitmapData []byte, start int) int {
 	}
 	return -1
 }
+
+// QuestionThumbNumRedisKey 题目点赞数增量，hash 结构，field 为题目 id，由定时任务刷回数据库
+const QuestionThumbNumRedisKey = "question:thumb_num"
+
+// QuestionFavourNumRedisKey 题目收藏数增量，hash 结构，field 为题目 id，由定时任务刷回数据库
+const QuestionFavourNumRedisKey = "question:favour_num"
+
+// QuestionViewNumRedisKey 题目浏览量增量，hash 结构，field 为题目 id，由定时任务刷回数据库
+const QuestionViewNumRedisKey = "question:view_num"
+
+// QuestionBankViewNumRedisKey 题库浏览量增量，hash 结构，field 为题库 id，由定时任务刷回数据库
+const QuestionBankViewNumRedisKey = "question_bank:view_num"
+
+// HotQuestionWindowMinutes 热点题目统计窗口（分钟），按分钟分桶滑动统计
+const HotQuestionWindowMinutes = 10
+
+// HotQuestionThreshold 统计窗口内浏览次数达到该值即视为热点题目
+const HotQuestionThreshold = 100
+
+// GetViewBucket 获取当前时间所在的分钟桶
+func GetViewBucket(t time.Time) int64 {
+	return t.Unix() / 60
+}
+
+// GetQuestionViewCountRedisKey 题目某个分钟桶内的浏览次数 Key
+func GetQuestionViewCountRedisKey(questionId string, bucket int64) string {
+	return fmt.Sprintf("question:count:%s:%d", questionId, bucket)
+}
+
+// GetHotQuestionRankRedisKey 某个分钟桶内的题目浏览排行 Key，zset 结构
+func GetHotQuestionRankRedisKey(bucket int64) string {
+	return fmt.Sprintf("question:hot_rank:%d", bucket)
+}
+
+// QuestionSuggestCacheTTL 搜索建议缓存时间，前缀输入频繁且允许短暂不一致
+const QuestionSuggestCacheTTL = 30 * time.Second
+
+// GetQuestionSuggestRedisKey 搜索建议缓存 Key
+func GetQuestionSuggestRedisKey(prefix string, size int) string {
+	return fmt.Sprintf("question:suggest:%d:%s", size, prefix)
+}
+
+// GetMockInterviewReportGeneratingRedisKey 评估报告生成中标记 Key，存在时不重复生成
+func GetMockInterviewReportGeneratingRedisKey(mockInterviewId uint64) string {
+	return fmt.Sprintf("mock_interview:report_generating:%d", mockInterviewId)
+}
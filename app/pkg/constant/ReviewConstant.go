@@ -0,0 +1,8 @@
+package constant
+
+// 审核状态
+const (
+	ReviewStatusPending = 0 // 待审核
+	ReviewStatusPass    = 1 // 通过
+	ReviewStatusReject  = 2 // 拒绝
+)
@@ -0,0 +1,8 @@
+package constant
+
+// 题目修订记录的操作类型
+const (
+	QuestionRevisionActionCreate   = "create"   // 创建
+	QuestionRevisionActionUpdate   = "update"   // 修改
+	QuestionRevisionActionRollback = "rollback" // 回滚
+)
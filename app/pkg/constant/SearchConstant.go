@@ -0,0 +1,25 @@
+package constant
+
+import "time"
+
+const (
+	QuestionSearchLogBufferSize    = 4096                // 搜索日志异步写入缓冲区大小，写满时丢弃新日志
+	QuestionSearchLogBatchSize     = 200                 // 搜索日志每批写入的条数
+	QuestionSearchLogFlushInterval = time.Second         // 搜索日志最长攒批时间
+	QuestionSearchLogRetentionTime = 90 * 24 * time.Hour // 搜索日志保留时间
+	QuestionSearchQueryMaxLen      = 256                 // 记录的搜索词最大字符数
+
+	QuestionTrendingSearchWindow   = 24 * time.Hour  // 热门搜索统计窗口
+	QuestionTrendingSearchSize     = 10              // 热门搜索返回数量
+	QuestionTrendingSearchCacheTTL = 5 * time.Minute // 热门搜索缓存时间
+	QuestionTrendingSearchMinUsers = 3               // 进入热门搜索至少需要的不同登录用户数
+
+	// QuestionTrendingSearchRedisKey 热门搜索缓存 Key
+	QuestionTrendingSearchRedisKey = "question:search:trending"
+)
+
+const (
+	SimilarQuestionDefaultSize     = 5   // 相似题目默认返回数量
+	SimilarQuestionMaxSize         = 20  // 相似题目最多返回数量
+	NearDuplicateQuestionThreshold = 0.9 // 余弦相似度达到该值即视为疑似重复题目
+)
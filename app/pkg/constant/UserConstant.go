@@ -0,0 +1,10 @@
+package constant
+
+// 用户角色
+const (
+	NotLoginRole = "notLogin" // 未登录
+	DefaultRole  = "user"     // 普通用户
+	VipRole      = "vip"      // 会员
+	AdminRole    = "admin"    // 管理员
+	BanRole      = "ban"      // 被封禁
+)
@@ -0,0 +1,28 @@
+package embedding
+
+import (
+	"context"
+	"github.com/spf13/viper"
+)
+
+// Provider 文本向量计算接口，同一文本在同一配置下必须得到相同的向量
+type Provider interface {
+	// Embed 计算文本的单位向量，文本中没有有效词语时返回 nil
+	Embed(ctx context.Context, text string) ([]float32, error)
+	// Dims 向量维度
+	Dims() int
+}
+
+// NewProvider 根据配置创建向量计算实例
+func NewProvider(conf *viper.Viper) Provider {
+	dims := conf.GetInt("embedding.dims")
+	if dims <= 0 {
+		dims = 256
+	}
+	switch provider := conf.GetString("embedding.provider"); provider {
+	case "", "hash":
+		return NewHashProvider(dims)
+	default:
+		panic("unknown embedding provider: " + provider)
+	}
+}
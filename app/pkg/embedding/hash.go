@@ -0,0 +1,111 @@
+package embedding
+
+import (
+	"context"
+	"hash/fnv"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// stopWords 不参与向量计算的常见词
+var stopWords = map[string]bool{
+	"a": true, "an": true, "the": true, "is": true, "are": true, "of": true, "to": true,
+	"in": true, "and": true, "or": true, "for": true, "on": true, "with": true, "what": true,
+	"how": true, "why": true, "的": true, "是": true, "了": true, "和": true, "在": true,
+	"什么": true, "如何": true, "怎么": true, "为什么": true,
+}
+
+// NewHashProvider 创建本地哈希词频向量实现，不依赖外部服务
+func NewHashProvider(dims int) Provider {
+	return &hashProvider{dims: dims}
+}
+
+// hashProvider 将词语通过哈希映射到固定维度，按对数词频加权后归一化；
+// 英文和数字按单词切分，中文按单字和相邻两字切分
+type hashProvider struct {
+	dims int
+}
+
+// Dims 向量维度
+func (p *hashProvider) Dims() int {
+	return p.dims
+}
+
+// Embed 计算文本的单位向量
+func (p *hashProvider) Embed(ctx context.Context, text string) ([]float32, error) {
+	tf := make(map[string]int)
+	for _, token := range tokenize(text) {
+		if !stopWords[token] {
+			tf[token]++
+		}
+	}
+	if len(tf) == 0 {
+		return nil, nil
+	}
+
+	vector := make([]float64, p.dims)
+	for token, n := range tf {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(token))
+		sum := h.Sum32()
+		// 用哈希值的最高位决定符号，减少哈希冲突带来的偏差
+		sign := 1.0
+		if sum>>31 == 1 {
+			sign = -1.0
+		}
+		vector[int(sum%uint32(p.dims))] += sign * (1 + math.Log(float64(n)))
+	}
+
+	var norm float64
+	for _, v := range vector {
+		norm += v * v
+	}
+	if norm == 0 {
+		return nil, nil
+	}
+	norm = math.Sqrt(norm)
+	result := make([]float32, p.dims)
+	for i, v := range vector {
+		result[i] = float32(v / norm)
+	}
+	return result, nil
+}
+
+// tokenize 切分文本：连续的字母数字作为一个词，连续的汉字输出单字和相邻两字
+func tokenize(text string) []string {
+	var tokens []string
+	var word strings.Builder
+	var han []rune
+	flushWord := func() {
+		if word.Len() > 0 {
+			tokens = append(tokens, word.String())
+			word.Reset()
+		}
+	}
+	flushHan := func() {
+		for i, r := range han {
+			tokens = append(tokens, string(r))
+			if i+1 < len(han) {
+				tokens = append(tokens, string(han[i:i+2]))
+			}
+		}
+		han = han[:0]
+	}
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			flushWord()
+			han = append(han, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			flushHan()
+			word.WriteRune(r)
+		default:
+			flushWord()
+			flushHan()
+		}
+	}
+	flushWord()
+	flushHan()
+	return tokens
+}
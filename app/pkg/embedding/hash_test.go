@@ -0,0 +1,70 @@
+package embedding
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"", nil},
+		{"Redis AOF", []string{"redis", "aof"}},
+		{"k8s, HTTP/2", []string{"k8s", "http", "2"}},
+		{"反射", []string{"反", "反射", "射"}},
+		{"Java反射机制", []string{"java", "反", "反射", "射", "射机", "机", "机制", "制"}},
+		{"什么是 B+树", []string{"什", "什么", "么", "么是", "是", "b", "树"}},
+	}
+	for _, tt := range tests {
+		if got := tokenize(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("tokenize(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func cosine(a, b []float32) float64 {
+	var dot float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+	}
+	return dot
+}
+
+func TestHashProvider_Embed(t *testing.T) {
+	p := NewHashProvider(256)
+	ctx := context.Background()
+	embed := func(text string) []float32 {
+		vector, err := p.Embed(ctx, text)
+		if err != nil {
+			t.Fatalf("Embed(%q) error = %v", text, err)
+		}
+		return vector
+	}
+
+	for _, text := range []string{"", "  ,. ", "what is the"} {
+		if vector := embed(text); vector != nil {
+			t.Errorf("Embed(%q) = %v, want nil", text, vector)
+		}
+	}
+
+	vector := embed("Redis 持久化方式有哪些")
+	if len(vector) != p.Dims() {
+		t.Fatalf("len = %d, want %d", len(vector), p.Dims())
+	}
+	if norm := math.Sqrt(cosine(vector, vector)); math.Abs(norm-1) > 1e-5 {
+		t.Errorf("norm = %f, want 1", norm)
+	}
+	if !reflect.DeepEqual(vector, embed("redis 持久化方式有哪些")) {
+		t.Error("embedding is not case insensitive")
+	}
+
+	similar := cosine(vector, embed("Redis 有哪些持久化方式"))
+	different := cosine(vector, embed("Java 反射的原理"))
+	if similar <= different {
+		t.Errorf("similar = %f, different = %f, want similar > different", similar, different)
+	}
+}
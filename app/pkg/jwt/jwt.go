@@ -20,8 +20,10 @@ type User struct {
 	UserAvatar  *string
 	UserProfile *string
 	UserRole    string
-	CreateTime  time.Time
-	UpdateTime  time.Time
+	// 会员过期时间，为空表示非会员
+	VipExpireTime *time.Time
+	CreateTime    time.Time
+	UpdateTime    time.Time
 }
 
 type MyCustomClaims struct {
@@ -72,3 +74,32 @@ func (j *JWT) ParseToken(tokenString string) (*MyCustomClaims, error) {
 		return nil, err
 	}
 }
+
+// shareAudience 分享链接 token 的受众，用于和登录 token 区分
+const shareAudience = "share"
+
+// GenShareToken 生成分享链接的签名 token，subject 为分享对象的标识
+func (j *JWT) GenShareToken(subject string, expiresAt time.Time) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Subject:   subject,
+		Audience:  []string{shareAudience},
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+	})
+	return token.SignedString(j.key)
+}
+
+// ParseShareToken 校验分享 token 的签名、受众和有效期，返回分享对象的标识
+func (j *JWT) ParseShareToken(tokenString string) (string, error) {
+	if strings.TrimSpace(tokenString) == "" {
+		return "", errors.New("token is empty")
+	}
+	claims := &jwt.RegisteredClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		return j.key, nil
+	}, jwt.WithAudience(shareAudience), jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil {
+		return "", err
+	}
+	return claims.Subject, nil
+}
@@ -0,0 +1,159 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/spf13/viper"
+	"github.com/volcengine/volcengine-go-sdk/service/arkruntime"
+	"github.com/volcengine/volcengine-go-sdk/service/arkruntime/model"
+	"github.com/volcengine/volcengine-go-sdk/volcengine"
+)
+
+const (
+	defaultArkBaseURL = "https://ark.cn-beijing.volces.com/api/v3"
+	defaultArkModel   = "deepseek-v3-250324"
+)
+
+// arkClient 火山方舟客户端
+type arkClient struct {
+	client *arkruntime.Client
+	model  string
+}
+
+// NewArkClient 创建火山方舟客户端，api_key 未配置时读取环境变量 ARK_API_KEY；
+// 超时和重试由外层控制，这里关闭 SDK 自带的重试
+func NewArkClient(conf *viper.Viper) Client {
+	apiKey := subString(conf, "api_key")
+	if apiKey == "" {
+		apiKey = os.Getenv("ARK_API_KEY")
+	}
+	baseURL := subString(conf, "base_url")
+	if baseURL == "" {
+		baseURL = defaultArkBaseURL
+	}
+	m := subString(conf, "model")
+	if m == "" {
+		m = defaultArkModel
+	}
+	return &arkClient{
+		client: arkruntime.NewClientWithApiKey(apiKey,
+			arkruntime.WithBaseUrl(baseURL),
+			arkruntime.WithRetryTimes(0),
+			arkruntime.WithHTTPClient(&http.Client{}),
+		),
+		model: m,
+	}
+}
+
+func (c *arkClient) request(req *Request) model.CreateChatCompletionRequest {
+	r := model.CreateChatCompletionRequest{
+		Model:       c.model,
+		Temperature: req.Temperature,
+	}
+	if req.Model != "" {
+		r.Model = req.Model
+	}
+	if req.MaxTokens > 0 {
+		r.MaxTokens = volcengine.Int(req.MaxTokens)
+	}
+	for _, m := range req.Messages {
+		r.Messages = append(r.Messages, &model.ChatCompletionMessage{
+			Role: m.Role,
+			Content: &model.ChatCompletionMessageContent{
+				StringValue: volcengine.String(m.Content),
+			},
+		})
+	}
+	return r
+}
+
+// Chat 等待完整回复
+func (c *arkClient) Chat(ctx context.Context, req *Request) (*Response, error) {
+	resp, err := c.client.CreateChatCompletion(ctx, c.request(req))
+	if err != nil {
+		return nil, c.error(ctx, err)
+	}
+	if len(resp.Choices) == 0 || resp.Choices[0].Message.Content == nil ||
+		resp.Choices[0].Message.Content.StringValue == nil || *resp.Choices[0].Message.Content.StringValue == "" {
+		return nil, &Error{Provider: "ark", Kind: ErrEmptyReply, Err: errors.New("no content in response")}
+	}
+	return &Response{
+		Content:      *resp.Choices[0].Message.Content.StringValue,
+		Model:        resp.Model,
+		FinishReason: string(resp.Choices[0].FinishReason),
+		Usage: Usage{
+			PromptTokens:     resp.Usage.PromptTokens,
+			CompletionTokens: resp.Usage.CompletionTokens,
+			TotalTokens:      resp.Usage.TotalTokens,
+		},
+	}, nil
+}
+
+// ChatStream 流式对话，最后一个分片返回 token 用量
+func (c *arkClient) ChatStream(ctx context.Context, req *Request, onDelta func(delta string) error) (*Response, error) {
+	r := c.request(req)
+	r.StreamOptions = &model.StreamOptions{IncludeUsage: true}
+	stream, err := c.client.CreateChatCompletionStream(ctx, r)
+	if err != nil {
+		return nil, c.error(ctx, err)
+	}
+	defer stream.Close()
+
+	var sb strings.Builder
+	resp := &Response{Model: r.Model}
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, c.error(ctx, err)
+		}
+		if chunk.Model != "" {
+			resp.Model = chunk.Model
+		}
+		if chunk.Usage != nil {
+			resp.Usage = Usage{
+				PromptTokens:     chunk.Usage.PromptTokens,
+				CompletionTokens: chunk.Usage.CompletionTokens,
+				TotalTokens:      chunk.Usage.TotalTokens,
+			}
+		}
+		for _, choice := range chunk.Choices {
+			if choice.FinishReason != "" {
+				resp.FinishReason = string(choice.FinishReason)
+			}
+			if choice.Delta.Content == "" {
+				continue
+			}
+			sb.WriteString(choice.Delta.Content)
+			if err = onDelta(choice.Delta.Content); err != nil {
+				return nil, &Error{Provider: "ark", Kind: ErrCanceled, Err: err}
+			}
+		}
+	}
+	if sb.Len() == 0 {
+		return nil, &Error{Provider: "ark", Kind: ErrEmptyReply, Err: errors.New("no content in stream")}
+	}
+	resp.Content = sb.String()
+	return resp, nil
+}
+
+// error 从 SDK 错误中取出 HTTP 状态码并归类
+func (c *arkClient) error(ctx context.Context, err error) *Error {
+	statusCode := 0
+	var apiErr *model.APIError
+	var reqErr *model.RequestError
+	switch {
+	case errors.As(err, &apiErr):
+		statusCode = apiErr.HTTPStatusCode
+	case errors.As(err, &reqErr):
+		statusCode = reqErr.HTTPStatusCode
+	}
+	return newError(ctx, "ark", statusCode, err)
+}
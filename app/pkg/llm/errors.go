@@ -0,0 +1,70 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// 错误类型，可通过 errors.Is 判断
+var (
+	ErrTimeout        = errors.New("llm request timeout")
+	ErrCanceled       = errors.New("llm request canceled")
+	ErrRateLimited    = errors.New("llm rate limited")
+	ErrUnavailable    = errors.New("llm service unavailable")
+	ErrUnauthorized   = errors.New("llm unauthorized")
+	ErrInvalidRequest = errors.New("llm invalid request")
+	ErrEmptyReply     = errors.New("llm empty reply")
+)
+
+// Error 大模型调用错误
+type Error struct {
+	// 供应商名称
+	Provider string
+	// 上游 HTTP 状态码，网络错误时为 0
+	StatusCode int
+	// 错误类型，为上面定义的错误之一
+	Kind error
+	// 原始错误
+	Err error
+}
+
+func (e *Error) Error() string {
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("%s: %v (status %d): %v", e.Provider, e.Kind, e.StatusCode, e.Err)
+	}
+	return fmt.Sprintf("%s: %v: %v", e.Provider, e.Kind, e.Err)
+}
+
+func (e *Error) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// Retryable 是否可以重试：超时、限流和服务不可用可以重试，取消和请求本身有误不重试
+func Retryable(err error) bool {
+	return errors.Is(err, ErrTimeout) || errors.Is(err, ErrRateLimited) || errors.Is(err, ErrUnavailable)
+}
+
+// newError 根据 ctx 状态和 HTTP 状态码归类错误
+func newError(ctx context.Context, provider string, statusCode int, err error) *Error {
+	e := &Error{Provider: provider, StatusCode: statusCode, Err: err}
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded), errors.Is(err, context.DeadlineExceeded):
+		e.Kind = ErrTimeout
+	case errors.Is(ctx.Err(), context.Canceled), errors.Is(err, context.Canceled):
+		e.Kind = ErrCanceled
+	case statusCode == http.StatusTooManyRequests:
+		e.Kind = ErrRateLimited
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		e.Kind = ErrUnauthorized
+	case statusCode == http.StatusRequestTimeout:
+		e.Kind = ErrTimeout
+	case statusCode >= 400 && statusCode < 500:
+		e.Kind = ErrInvalidRequest
+	default:
+		// 5xx 和网络错误
+		e.Kind = ErrUnavailable
+	}
+	return e
+}
@@ -0,0 +1,118 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// FakeReply 脚本中的一次回复，Err 不为空时返回该错误
+type FakeReply struct {
+	Content string
+	Usage   Usage
+	Err     error
+	// 每段流式内容之间的间隔，用于模拟慢速生成
+	Delay time.Duration
+}
+
+// FakeClient 按脚本依次返回回复的客户端，用于测试和本地开发，并记录收到的请求
+type FakeClient struct {
+	mu       sync.Mutex
+	replies  []FakeReply
+	next     int
+	loop     bool
+	requests []*Request
+}
+
+// NewFakeClient 创建按顺序返回 replies 的客户端，脚本用完后返回 ErrUnavailable
+func NewFakeClient(replies ...FakeReply) *FakeClient {
+	return &FakeClient{replies: replies}
+}
+
+// NewFakeClientFromConfig 根据 llm.fake.replies 配置创建客户端，脚本循环使用
+func NewFakeClientFromConfig(conf *viper.Viper) Client {
+	c := &FakeClient{loop: true}
+	if conf != nil {
+		for _, content := range conf.GetStringSlice("replies") {
+			c.replies = append(c.replies, FakeReply{Content: content})
+		}
+	}
+	if len(c.replies) == 0 {
+		c.replies = []FakeReply{{Content: "这是一条测试回复。"}}
+	}
+	return c
+}
+
+// Requests 返回已收到的请求
+func (c *FakeClient) Requests() []*Request {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*Request(nil), c.requests...)
+}
+
+func (c *FakeClient) take(req *Request) (FakeReply, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests = append(c.requests, req)
+	if c.next >= len(c.replies) {
+		if !c.loop || len(c.replies) == 0 {
+			return FakeReply{}, &Error{Provider: "fake", Kind: ErrUnavailable, Err: errors.New("script exhausted")}
+		}
+		c.next = 0
+	}
+	reply := c.replies[c.next]
+	c.next++
+	return reply, nil
+}
+
+func (c *FakeClient) response(reply FakeReply) (*Response, error) {
+	if reply.Err != nil {
+		return nil, reply.Err
+	}
+	if reply.Content == "" {
+		return nil, &Error{Provider: "fake", Kind: ErrEmptyReply, Err: errors.New("no content in script")}
+	}
+	return &Response{Content: reply.Content, Model: "fake", FinishReason: "stop", Usage: reply.Usage}, nil
+}
+
+// Chat 返回脚本中的下一条回复
+func (c *FakeClient) Chat(ctx context.Context, req *Request) (*Response, error) {
+	reply, err := c.take(req)
+	if err != nil {
+		return nil, err
+	}
+	if err = ctx.Err(); err != nil {
+		return nil, newError(ctx, "fake", 0, err)
+	}
+	return c.response(reply)
+}
+
+// ChatStream 将脚本中的下一条回复按字符逐段推送
+func (c *FakeClient) ChatStream(ctx context.Context, req *Request, onDelta func(delta string) error) (*Response, error) {
+	reply, err := c.take(req)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.response(reply)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range resp.Content {
+		if reply.Delay > 0 {
+			select {
+			case <-ctx.Done():
+			case <-time.After(reply.Delay):
+			}
+		}
+		if err = ctx.Err(); err != nil {
+			return nil, newError(ctx, "fake", 0, err)
+		}
+		if err = onDelta(string(r)); err != nil {
+			return nil, &Error{Provider: "fake", Kind: ErrCanceled, Err: err}
+		}
+	}
+	return resp, nil
+}
@@ -0,0 +1,83 @@
+package llm
+
+import (
+	"app/pkg/log"
+	"context"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	RoleSystem    = "system"
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+// Message 对话消息
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// Request 对话请求，Model 为空时使用供应商配置的默认模型
+type Request struct {
+	Messages    []Message
+	Model       string
+	Temperature *float32
+	MaxTokens   int
+}
+
+// Usage token 用量
+type Usage struct {
+	PromptTokens     int `json:"promptTokens"`
+	CompletionTokens int `json:"completionTokens"`
+	TotalTokens      int `json:"totalTokens"`
+}
+
+// Response 对话结果
+type Response struct {
+	Content      string
+	Model        string
+	FinishReason string
+	Usage        Usage
+	// 调用耗时，包含重试
+	Latency time.Duration
+}
+
+// Client 大模型对话接口，出错时返回 *Error，回复为空视为 ErrEmptyReply
+type Client interface {
+	// Chat 等待完整回复
+	Chat(ctx context.Context, req *Request) (*Response, error)
+	// ChatStream 流式对话，每收到一段回复调用一次 onDelta，返回完整回复；
+	// ctx 取消或 onDelta 返回错误时中断上游请求
+	ChatStream(ctx context.Context, req *Request, onDelta func(delta string) error) (*Response, error)
+}
+
+// NewClient 根据 llm.provider 配置创建客户端，并加上超时、重试和用量日志
+func NewClient(conf *viper.Viper, logger *log.Logger) Client {
+	var provider Client
+	switch name := conf.GetString("llm.provider"); name {
+	case "", "ark":
+		provider = NewArkClient(conf.Sub("llm.ark"))
+	case "openai":
+		provider = NewOpenAIClient(conf.Sub("llm.openai"))
+	case "fake":
+		provider = NewFakeClientFromConfig(conf.Sub("llm.fake"))
+	default:
+		panic("unknown llm provider: " + name)
+	}
+	return newRetryClient(provider, conf.GetString("llm.provider"), retryOptions{
+		timeout:     conf.GetDuration("llm.timeout"),
+		maxRetries:  conf.GetInt("llm.max_retries"),
+		baseBackoff: conf.GetDuration("llm.retry_backoff"),
+	}, logger)
+}
+
+// subString 读取子配置，子配置不存在时返回空字符串
+func subString(conf *viper.Viper, key string) string {
+	if conf == nil {
+		return ""
+	}
+	return conf.GetString(key)
+}
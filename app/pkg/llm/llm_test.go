@@ -0,0 +1,114 @@
+package llm
+
+import (
+	"app/pkg/log"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func newTestClient(fake *FakeClient, maxRetries int) Client {
+	return newRetryClient(fake, "fake", retryOptions{
+		timeout:     time.Second,
+		maxRetries:  maxRetries,
+		baseBackoff: time.Millisecond,
+	}, &log.Logger{Logger: zap.NewNop()})
+}
+
+func TestRetryClient_Chat(t *testing.T) {
+	unavailable := &Error{Provider: "fake", StatusCode: 503, Kind: ErrUnavailable, Err: errors.New("busy")}
+	invalid := &Error{Provider: "fake", StatusCode: 400, Kind: ErrInvalidRequest, Err: errors.New("bad")}
+	tests := []struct {
+		name       string
+		replies    []FakeReply
+		maxRetries int
+		wantErr    error
+		wantCalls  int
+	}{
+		{
+			name:       "retry until success",
+			replies:    []FakeReply{{Err: unavailable}, {Err: unavailable}, {Content: "ok"}},
+			maxRetries: 2,
+			wantCalls:  3,
+		},
+		{
+			name:       "give up after max retries",
+			replies:    []FakeReply{{Err: unavailable}, {Err: unavailable}, {Content: "ok"}},
+			maxRetries: 1,
+			wantErr:    ErrUnavailable,
+			wantCalls:  2,
+		},
+		{
+			name:       "invalid request is not retried",
+			replies:    []FakeReply{{Err: invalid}, {Content: "ok"}},
+			maxRetries: 3,
+			wantErr:    ErrInvalidRequest,
+			wantCalls:  1,
+		},
+		{
+			name:       "empty reply",
+			replies:    []FakeReply{{}},
+			maxRetries: 3,
+			wantErr:    ErrEmptyReply,
+			wantCalls:  1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := NewFakeClient(tt.replies...)
+			resp, err := newTestClient(fake, tt.maxRetries).Chat(context.Background(), &Request{})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Chat() error = %v, want %v", err, tt.wantErr)
+				}
+			} else if err != nil || resp.Content != "ok" {
+				t.Fatalf("Chat() = %v, %v", resp, err)
+			}
+			if got := len(fake.Requests()); got != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetryClient_ChatStreamCanceled(t *testing.T) {
+	fake := NewFakeClient(FakeReply{Content: "你好，请做一下自我介绍", Delay: time.Millisecond}, FakeReply{Content: "ok"})
+	ctx, cancel := context.WithCancel(context.Background())
+	var got string
+	_, err := newTestClient(fake, 3).ChatStream(ctx, &Request{}, func(delta string) error {
+		got += delta
+		if len([]rune(got)) == 2 {
+			cancel()
+		}
+		return nil
+	})
+	if !errors.Is(err, ErrCanceled) {
+		t.Fatalf("ChatStream() error = %v, want %v", err, ErrCanceled)
+	}
+	if got != "你好" {
+		t.Errorf("deltas = %q, want %q", got, "你好")
+	}
+	if calls := len(fake.Requests()); calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
+
+func TestEstimateTokens(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"", 0},
+		{"hello world!", 3},
+		{"你好，世界", 5},
+		{"Redis 持久化", 5},
+	}
+	for _, tt := range tests {
+		if got := EstimateTokens(tt.text); got != tt.want {
+			t.Errorf("EstimateTokens(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
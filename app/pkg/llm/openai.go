@@ -0,0 +1,205 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+const defaultOpenAIBaseURL = "https://api.openai.com/v1"
+
+// openAIClient 兼容 OpenAI Chat Completions 接口的客户端，可对接 DeepSeek、通义、vLLM、Ollama 等
+type openAIClient struct {
+	httpClient *http.Client
+	baseURL    string
+	apiKey     string
+	model      string
+}
+
+// NewOpenAIClient 创建兼容 OpenAI 接口的客户端，api_key 未配置时读取环境变量 OPENAI_API_KEY
+func NewOpenAIClient(conf *viper.Viper) Client {
+	apiKey := subString(conf, "api_key")
+	if apiKey == "" {
+		apiKey = os.Getenv("OPENAI_API_KEY")
+	}
+	baseURL := subString(conf, "base_url")
+	if baseURL == "" {
+		baseURL = defaultOpenAIBaseURL
+	}
+	return &openAIClient{
+		httpClient: &http.Client{},
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		apiKey:     apiKey,
+		model:      subString(conf, "model"),
+	}
+}
+
+type openAIRequest struct {
+	Model         string              `json:"model"`
+	Messages      []Message           `json:"messages"`
+	Temperature   *float32            `json:"temperature,omitempty"`
+	MaxTokens     int                 `json:"max_tokens,omitempty"`
+	Stream        bool                `json:"stream,omitempty"`
+	StreamOptions *openAIStreamOption `json:"stream_options,omitempty"`
+}
+
+type openAIStreamOption struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+type openAIUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+type openAIResponse struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+		FinishReason *string `json:"finish_reason"`
+	} `json:"choices"`
+	Usage *openAIUsage `json:"usage"`
+}
+
+func (u *openAIUsage) usage() Usage {
+	if u == nil {
+		return Usage{}
+	}
+	return Usage{PromptTokens: u.PromptTokens, CompletionTokens: u.CompletionTokens, TotalTokens: u.TotalTokens}
+}
+
+// post 发送请求，非 2xx 时读取响应体作为错误信息
+func (c *openAIClient) post(ctx context.Context, req *Request, stream bool) (*http.Response, string, error) {
+	body := openAIRequest{
+		Model:       c.model,
+		Messages:    req.Messages,
+		Temperature: req.Temperature,
+		MaxTokens:   req.MaxTokens,
+		Stream:      stream,
+	}
+	if req.Model != "" {
+		body.Model = req.Model
+	}
+	if stream {
+		body.StreamOptions = &openAIStreamOption{IncludeUsage: true}
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, body.Model, &Error{Provider: "openai", Kind: ErrInvalidRequest, Err: err}
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/chat/completions", bytes.NewReader(data))
+	if err != nil {
+		return nil, body.Model, &Error{Provider: "openai", Kind: ErrInvalidRequest, Err: err}
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, body.Model, newError(ctx, "openai", 0, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		resp.Body.Close()
+		return nil, body.Model, newError(ctx, "openai", resp.StatusCode, fmt.Errorf("%s", strings.TrimSpace(string(msg))))
+	}
+	return resp, body.Model, nil
+}
+
+// Chat 等待完整回复
+func (c *openAIClient) Chat(ctx context.Context, req *Request) (*Response, error) {
+	httpResp, m, err := c.post(ctx, req, false)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+	var rr openAIResponse
+	if err = json.NewDecoder(httpResp.Body).Decode(&rr); err != nil {
+		return nil, newError(ctx, "openai", 0, err)
+	}
+	if len(rr.Choices) == 0 || rr.Choices[0].Message.Content == "" {
+		return nil, &Error{Provider: "openai", Kind: ErrEmptyReply, Err: errors.New("no content in response")}
+	}
+	resp := &Response{
+		Content: rr.Choices[0].Message.Content,
+		Model:   m,
+		Usage:   rr.Usage.usage(),
+	}
+	if rr.Model != "" {
+		resp.Model = rr.Model
+	}
+	if rr.Choices[0].FinishReason != nil {
+		resp.FinishReason = *rr.Choices[0].FinishReason
+	}
+	return resp, nil
+}
+
+// ChatStream 解析 data: 开头的 SSE 分片，以 [DONE] 结束
+func (c *openAIClient) ChatStream(ctx context.Context, req *Request, onDelta func(delta string) error) (*Response, error) {
+	httpResp, m, err := c.post(ctx, req, true)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+
+	var sb strings.Builder
+	resp := &Response{Model: m}
+	scanner := bufio.NewScanner(httpResp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "data:")
+		if !ok {
+			continue
+		}
+		data = strings.TrimSpace(data)
+		if data == "[DONE]" {
+			break
+		}
+		var chunk openAIResponse
+		if err = json.Unmarshal([]byte(data), &chunk); err != nil {
+			return nil, newError(ctx, "openai", 0, err)
+		}
+		if chunk.Model != "" {
+			resp.Model = chunk.Model
+		}
+		if chunk.Usage != nil {
+			resp.Usage = chunk.Usage.usage()
+		}
+		for _, choice := range chunk.Choices {
+			if choice.FinishReason != nil {
+				resp.FinishReason = *choice.FinishReason
+			}
+			if choice.Delta.Content == "" {
+				continue
+			}
+			sb.WriteString(choice.Delta.Content)
+			if err = onDelta(choice.Delta.Content); err != nil {
+				return nil, &Error{Provider: "openai", Kind: ErrCanceled, Err: err}
+			}
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, newError(ctx, "openai", 0, err)
+	}
+	if sb.Len() == 0 {
+		return nil, &Error{Provider: "openai", Kind: ErrEmptyReply, Err: errors.New("no content in stream")}
+	}
+	resp.Content = sb.String()
+	return resp, nil
+}
@@ -0,0 +1,98 @@
+package llm
+
+import (
+	"app/pkg/log"
+	"context"
+	"math/rand"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultTimeout     = 120 * time.Second
+	defaultBaseBackoff = 500 * time.Millisecond
+	maxBackoff         = 10 * time.Second
+)
+
+type retryOptions struct {
+	// 单次请求超时时间
+	timeout time.Duration
+	// 最大重试次数，不含首次请求
+	maxRetries  int
+	baseBackoff time.Duration
+}
+
+// retryClient 为供应商客户端加上单次超时、指数退避重试和用量日志
+type retryClient struct {
+	Client
+	provider string
+	opts     retryOptions
+	logger   *log.Logger
+}
+
+func newRetryClient(client Client, provider string, opts retryOptions, logger *log.Logger) Client {
+	if opts.timeout <= 0 {
+		opts.timeout = defaultTimeout
+	}
+	if opts.maxRetries < 0 {
+		opts.maxRetries = 0
+	}
+	if opts.baseBackoff <= 0 {
+		opts.baseBackoff = defaultBaseBackoff
+	}
+	return &retryClient{Client: client, provider: provider, opts: opts, logger: logger}
+}
+
+// Chat 失败且可重试时按指数退避重试
+func (c *retryClient) Chat(ctx context.Context, req *Request) (*Response, error) {
+	return c.do(ctx, func(ctx context.Context) (*Response, bool, error) {
+		resp, err := c.Client.Chat(ctx, req)
+		return resp, true, err
+	})
+}
+
+// ChatStream 只有在尚未推送任何内容时才重试，避免调用方收到重复内容
+func (c *retryClient) ChatStream(ctx context.Context, req *Request, onDelta func(delta string) error) (*Response, error) {
+	return c.do(ctx, func(ctx context.Context) (*Response, bool, error) {
+		sent := false
+		resp, err := c.Client.ChatStream(ctx, req, func(delta string) error {
+			sent = true
+			return onDelta(delta)
+		})
+		return resp, !sent, err
+	})
+}
+
+func (c *retryClient) do(ctx context.Context, call func(ctx context.Context) (*Response, bool, error)) (*Response, error) {
+	start := time.Now()
+	for attempt := 0; ; attempt++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, c.opts.timeout)
+		resp, retryable, err := call(attemptCtx)
+		cancel()
+		if err == nil {
+			resp.Latency = time.Since(start)
+			c.logger.WithContext(ctx).Info("llm chat",
+				zap.String("provider", c.provider),
+				zap.String("model", resp.Model),
+				zap.Int("promptTokens", resp.Usage.PromptTokens),
+				zap.Int("completionTokens", resp.Usage.CompletionTokens),
+				zap.Int("attempts", attempt+1),
+				zap.Duration("latency", resp.Latency))
+			return resp, nil
+		}
+		if !retryable || !Retryable(err) || attempt >= c.opts.maxRetries || ctx.Err() != nil {
+			c.logger.WithContext(ctx).Warn("llm chat error", zap.String("provider", c.provider), zap.Int("attempts", attempt+1), zap.Error(err))
+			return nil, err
+		}
+		backoff := min(c.opts.baseBackoff<<attempt, maxBackoff)
+		// 加入随机抖动，避免限流时集中重试
+		backoff = backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+		c.logger.WithContext(ctx).Warn("llm chat retry", zap.String("provider", c.provider), zap.Int("attempt", attempt+1), zap.Duration("backoff", backoff), zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return nil, newError(ctx, c.provider, 0, ctx.Err())
+		case <-time.After(backoff):
+		}
+	}
+}
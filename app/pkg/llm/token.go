@@ -0,0 +1,25 @@
+package llm
+
+import "unicode"
+
+// messageOverheadTokens 每条消息的角色、分隔符等格式开销
+const messageOverheadTokens = 4
+
+// EstimateTokens 粗略估算文本的 token 数：中日韩字符按每字 1 个 token，其余字符按每 4 个字符 1 个 token，
+// 不依赖具体模型的分词器，结果偏保守
+func EstimateTokens(text string) int {
+	cjk, other := 0, 0
+	for _, r := range text {
+		if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) {
+			cjk++
+		} else {
+			other++
+		}
+	}
+	return cjk + (other+3)/4
+}
+
+// EstimateMessageTokens 估算单条消息的 token 数，包含格式开销
+func EstimateMessageTokens(message Message) int {
+	return EstimateTokens(message.Content) + messageOverheadTokens
+}
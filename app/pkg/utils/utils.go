@@ -67,6 +67,14 @@ func Uint64TOString(i uint64) string {
 	return strconv.FormatUint(i, 10)
 }
 
+// GetPages 根据总数和每页大小计算总页数
+func GetPages(total int, size int) int {
+	if size <= 0 {
+		return 0
+	}
+	return (total + size - 1) / size
+}
+
 // GetIPAddress 获取ip地址
 func GetIPAddress(c *gin.Context) string {
 	ip := c.Request.Header.Get("X-Forwarded-For")